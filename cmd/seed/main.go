@@ -43,6 +43,9 @@ func main() {
 	if err := seedArtifactItems(db.DB()); err != nil {
 		log.Printf("Failed to seed artifact items: %v", err)
 	}
+	if err := seedConsumableItems(db.DB()); err != nil {
+		log.Printf("Failed to seed consumable items: %v", err)
+	}
 	if err := seedLocations(db.DB()); err != nil {
 		log.Printf("Failed to seed locations: %v", err)
 	}
@@ -59,6 +62,7 @@ func truncateTables(db *sqlx.DB) error {
 
 	tables := []string{
 		"inventory",
+		"consumable_items",
 		"location_locations",
 		"equipment_items",
 		"equipment_categories",
@@ -191,6 +195,10 @@ func seedUsers(db *sqlx.DB) {
 	log.Printf("Successfully created user: %s (%s)", user.Username, user.Email)
 }
 
+// innRegenMultiplier makes HP come back three times as fast while resting in
+// the inn.
+const innRegenMultiplier = 3
+
 func seedLocations(db *sqlx.DB) error {
 	log.Println("Seeding locations...")
 
@@ -198,7 +206,7 @@ func seedLocations(db *sqlx.DB) error {
 
 	moonshineLocation, err := locationRepo.FindStartLocation()
 	if err == nil && moonshineLocation != nil {
-		if _, err := db.Exec("UPDATE locations SET cell = false WHERE slug IN ('moonshine', 'shop_of_artifacts', 'weapon_shop')"); err != nil {
+		if _, err := db.Exec("UPDATE locations SET cell = false WHERE slug IN ('moonshine', 'shop_of_artifacts', 'weapon_shop', 'inn')"); err != nil {
 		}
 		if _, err := db.Exec("UPDATE locations SET cell = true WHERE slug LIKE '%cell'"); err != nil {
 		}
@@ -225,6 +233,7 @@ func seedLocations(db *sqlx.DB) error {
 	}{
 		{"weapon_shop", "Weapon shop"},
 		{"shop_of_artifacts", "Артефакты"},
+		{domain.InnSlug, "Таверна"},
 	}
 
 	shopLocations := make(map[string]uuid.UUID)
@@ -246,6 +255,12 @@ func seedLocations(db *sqlx.DB) error {
 
 		shopLocations[shop.slug] = shopLocation.ID
 
+		if shop.slug == domain.InnSlug {
+			if err := locationRepo.UpdateRegenMultiplier(shopLocation.ID, innRegenMultiplier); err != nil {
+				return fmt.Errorf("failed to set inn regen multiplier: %w", err)
+			}
+		}
+
 		locLocID := uuid.New()
 		locationLocationQuery := `INSERT INTO location_locations (id, location_id, near_location_id) 
 			VALUES ($1, $2, $3)`
@@ -290,10 +305,11 @@ func seedLocations(db *sqlx.DB) error {
 		"moonshine":         moonshineLocation.ID,
 		"shop_of_artifacts": shopLocations["shop_of_artifacts"],
 		"weapon_shop":       shopLocations["weapon_shop"],
+		"inn":               shopLocations["inn"],
 		"wayward_pines":     waywardPinesLocation.ID,
 	}
 
-	locationNames := []string{"moonshine", "shop_of_artifacts", "weapon_shop", "wayward_pines"}
+	locationNames := []string{"moonshine", "shop_of_artifacts", "weapon_shop", "inn", "wayward_pines"}
 
 	for i, loc1Name := range locationNames {
 		for j, loc2Name := range locationNames {
@@ -482,13 +498,14 @@ func seedBots(db *sqlx.DB) error {
 	err := db.QueryRow("SELECT id FROM bots WHERE slug = $1 AND deleted_at IS NULL", "rat").Scan(&existingBotID)
 	if err != nil {
 		ratBot := &domain.Bot{
-			Name:    "Крыса",
-			Slug:    "rat",
-			Attack:  2,
-			Defense: 10,
-			Hp:      20,
-			Level:   1,
-			Avatar:  "images/bots/rat.jpg",
+			Name:     "Крыса",
+			Slug:     "rat",
+			Attack:   2,
+			Defense:  10,
+			Hp:       20,
+			Level:    1,
+			Avatar:   "images/bots/rat.jpg",
+			Strategy: domain.BotStrategyRandom,
 		}
 
 		if err := botRepo.Create(ratBot); err != nil {
@@ -514,8 +531,8 @@ func seedBots(db *sqlx.DB) error {
 
 	if err != nil {
 		linkID := uuid.New()
-		linkQuery := `INSERT INTO location_bots (id, location_id, bot_id) VALUES ($1, $2, $3)`
-		if _, err := db.Exec(linkQuery, linkID, cell29Location.ID, existingBotID); err != nil {
+		linkQuery := `INSERT INTO location_bots (id, location_id, bot_id, max_population, respawn_seconds) VALUES ($1, $2, $3, $4, $5)`
+		if _, err := db.Exec(linkQuery, linkID, cell29Location.ID, existingBotID, 3, 30); err != nil {
 			return fmt.Errorf("failed to link rat bot to 29cell: %w", err)
 		}
 		log.Printf("Linked bot 'Крыса' to location 29cell")
@@ -523,10 +540,186 @@ func seedBots(db *sqlx.DB) error {
 		log.Println("Bot 'rat' already linked to 29cell")
 	}
 
+	if err := seedBotLoot(db, existingBotID); err != nil {
+		return fmt.Errorf("failed to seed rat loot: %w", err)
+	}
+
+	if err := seedConsumableDrops(db, existingBotID); err != nil {
+		return fmt.Errorf("failed to seed rat consumable drops: %w", err)
+	}
+
+	if err := seedRatKing(db); err != nil {
+		return fmt.Errorf("failed to seed rat king: %w", err)
+	}
+
 	log.Println("Bots seeding completed!")
 	return nil
 }
 
+// seedRatKing adds a boss to the far corner of the map: it hits harder below
+// half HP and heals itself once nearly dead.
+func seedRatKing(db *sqlx.DB) error {
+	botRepo := repository.NewBotRepository(db)
+
+	if _, err := botRepo.FindBySlug("rat-king"); err == nil {
+		log.Println("Bot 'rat-king' already exists")
+		return nil
+	}
+
+	ratKing := &domain.Bot{
+		Name:                "Крысиный король",
+		Slug:                "rat-king",
+		Attack:              6,
+		Defense:             12,
+		Hp:                  120,
+		Level:               3,
+		Avatar:              "images/bots/rat.jpg",
+		Strategy:            domain.BotStrategyWeighted,
+		IsBoss:              true,
+		KillCooldownSeconds: 3600,
+		CombatStats:         domain.CombatStats{CritChance: 10, DodgeChance: 5},
+	}
+	if err := botRepo.Create(ratKing); err != nil {
+		return err
+	}
+
+	enragedAttack := uint(9)
+	doubleStrike := domain.BotAbilityDoubleStrike
+	heal := domain.BotAbilityHeal
+	mirror := domain.BotStrategyMirror
+	phases := []*domain.BotPhase{
+		{BotID: ratKing.ID, HpPercent: 50, Attack: &enragedAttack, Ability: &doubleStrike, AbilityChance: 25},
+		{BotID: ratKing.ID, HpPercent: 15, Attack: &enragedAttack, Strategy: &mirror, Ability: &heal, AbilityChance: 30, AbilityPower: 10},
+	}
+	for _, phase := range phases {
+		if err := botRepo.AddPhase(phase); err != nil {
+			return err
+		}
+	}
+
+	cell64Location, err := repository.NewLocationRepository(db).FindBySlug("64cell")
+	if err != nil {
+		return fmt.Errorf("failed to find 64cell location: %w", err)
+	}
+
+	linkQuery := `INSERT INTO location_bots (id, location_id, bot_id, max_population, respawn_seconds) VALUES ($1, $2, $3, $4, $5)`
+	if _, err := db.Exec(linkQuery, uuid.New(), cell64Location.ID, ratKing.ID, 1, 1800); err != nil {
+		return err
+	}
+
+	var itemIDs []uuid.UUID
+	query := `
+		SELECT id FROM equipment_items
+		WHERE artifact = false AND required_level <= 4 AND deleted_at IS NULL
+		ORDER BY price DESC
+		LIMIT 2
+	`
+	if err := db.Select(&itemIDs, query); err != nil {
+		return err
+	}
+	for _, itemID := range itemIDs {
+		if err := botRepo.AddLoot(&domain.BotLoot{BotID: ratKing.ID, EquipmentItemID: itemID, Weight: 1, MinLevel: 1}); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Created boss: Крысиный король (ID: %s) in 64cell", ratKing.ID.String())
+	return nil
+}
+
+func seedBotLoot(db *sqlx.DB, botID uuid.UUID) error {
+	botRepo := repository.NewBotRepository(db)
+
+	existingLoot, err := botRepo.FindLootByBotID(botID)
+	if err != nil {
+		return err
+	}
+	if len(existingLoot) > 0 {
+		log.Println("Bot loot already exists")
+		return nil
+	}
+
+	var itemIDs []uuid.UUID
+	query := `
+		SELECT id FROM equipment_items
+		WHERE artifact = false AND required_level <= 2 AND deleted_at IS NULL
+		ORDER BY price ASC
+		LIMIT 3
+	`
+	if err := db.Select(&itemIDs, query); err != nil {
+		return err
+	}
+
+	for i, itemID := range itemIDs {
+		loot := &domain.BotLoot{
+			BotID:           botID,
+			EquipmentItemID: itemID,
+			Weight:          uint(len(itemIDs) - i),
+			MinLevel:        1,
+		}
+		if err := botRepo.AddLoot(loot); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Added %d loot entries", len(itemIDs))
+	return nil
+}
+
+// seedConsumableItems adds the potions and elixirs sold in shops.
+func seedConsumableItems(db *sqlx.DB) error {
+	log.Println("Seeding consumable items...")
+
+	consumableRepo := repository.NewConsumableItemRepository(db)
+
+	items := []*domain.ConsumableItem{
+		{Name: "Малое зелье здоровья", Slug: "small-healing-potion", Image: "images/consumables/small-healing-potion.png", Price: 10, RequiredLevel: 1, HealHp: 30},
+		{Name: "Зелье здоровья", Slug: "healing-potion", Image: "images/consumables/healing-potion.png", Price: 30, RequiredLevel: 3, HealHp: 100},
+		{Name: "Большое зелье здоровья", Slug: "big-healing-potion", Image: "images/consumables/big-healing-potion.png", Price: 80, RequiredLevel: 6, HealHp: 300},
+		{Name: "Эликсир силы", Slug: "strength-elixir", Image: "images/consumables/strength-elixir.png", Price: 50, RequiredLevel: 2, BuffAttack: 5, BuffSeconds: 300},
+		{Name: "Эликсир стойкости", Slug: "stone-skin-elixir", Image: "images/consumables/stone-skin-elixir.png", Price: 50, RequiredLevel: 2, BuffDefense: 5, BuffSeconds: 300},
+		{Name: "Эликсир ловкости", Slug: "agility-elixir", Image: "images/consumables/agility-elixir.png", Price: 70, RequiredLevel: 4, BuffCritChance: 10, BuffDodgeChance: 10, BuffSeconds: 180},
+	}
+
+	for _, item := range items {
+		if _, err := consumableRepo.FindBySlug(item.Slug); err == nil {
+			log.Printf("Consumable item '%s' already exists", item.Slug)
+			continue
+		}
+		if err := consumableRepo.Create(item); err != nil {
+			return fmt.Errorf("failed to create consumable item %s: %w", item.Slug, err)
+		}
+		log.Printf("Created consumable item: %s", item.Name)
+	}
+
+	return nil
+}
+
+func seedConsumableDrops(db *sqlx.DB, botID uuid.UUID) error {
+	consumableRepo := repository.NewConsumableItemRepository(db)
+
+	existingDrops, err := consumableRepo.FindDropsByBotID(botID)
+	if err != nil {
+		return err
+	}
+	if len(existingDrops) > 0 {
+		log.Println("Bot consumable drops already exist")
+		return nil
+	}
+
+	potion, err := consumableRepo.FindBySlug("small-healing-potion")
+	if err != nil {
+		return err
+	}
+
+	if err := consumableRepo.AddDrop(&domain.BotConsumableDrop{BotID: botID, ConsumableItemID: potion.ID, Chance: 20}); err != nil {
+		return err
+	}
+
+	log.Println("Added consumable drops")
+	return nil
+}
+
 func seedEquipmentCategories(db *sqlx.DB) {
 	log.Println("Seeding equipment categories...")
 
@@ -837,9 +1030,12 @@ func seedArtifactItems(db *sqlx.DB) error {
 		}
 		slug := generateSlugFromImage(dbImagePath)
 		itemID := uuid.New()
-		q := `INSERT INTO equipment_items (id, name, slug, attack, defense, hp, required_level, price, artifact, equipment_category_id, image)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
-		_, execErr := db.Exec(q, itemID, af.name, slug, af.attack, af.defense, af.hp, af.requiredLevel, af.price, true, catID, dbImagePath)
+		stats := artifactCombatStats(af.categoryType, af.requiredLevel)
+		q := `INSERT INTO equipment_items (id, name, slug, attack, defense, hp, required_level, price, artifact, equipment_category_id, image,
+				crit_chance, crit_power, dodge_chance, block_chance)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+		_, execErr := db.Exec(q, itemID, af.name, slug, af.attack, af.defense, af.hp, af.requiredLevel, af.price, true, catID, dbImagePath,
+			stats.CritChance, stats.CritPower, stats.DodgeChance, stats.BlockChance)
 		if execErr != nil {
 			log.Printf("Failed to create artifact %s: %v", af.name, execErr)
 			return nil
@@ -855,6 +1051,24 @@ func seedArtifactItems(db *sqlx.DB) error {
 	return nil
 }
 
+// artifactCombatStats gives artifacts secondary stats by slot, growing with
+// the level they require: weapons and rings crit, boots dodge, shields block.
+func artifactCombatStats(categoryType string, requiredLevel uint) domain.CombatStats {
+	var stats domain.CombatStats
+	switch categoryType {
+	case "weapon":
+		stats.CritChance = 5 + requiredLevel
+		stats.CritPower = 10 + 5*requiredLevel
+	case "ring":
+		stats.CritChance = 2 + requiredLevel/2
+	case "feet":
+		stats.DodgeChance = 3 + requiredLevel/2
+	case "shield":
+		stats.BlockChance = 10 + requiredLevel
+	}
+	return stats
+}
+
 func parseEquipmentFileName(filename string, info *equipmentFileInfo) bool {
 	ext := filepath.Ext(filename)
 	base := strings.TrimSuffix(filename, ext)
@@ -14,7 +14,9 @@ import (
 
 	"moonshine/cmd/server/docs"
 	"moonshine/internal/api"
+	"moonshine/internal/api/ws"
 	"moonshine/internal/config"
+	"moonshine/internal/domain"
 	"moonshine/internal/metrics"
 	"moonshine/internal/repository"
 	"moonshine/internal/worker"
@@ -50,6 +52,23 @@ func main() {
 
 	cfg := config.Load()
 
+	progression, err := domain.LoadProgression(cfg.ProgressionFile)
+	if err != nil {
+		log.Fatalf("failed to load progression: %v", err)
+	}
+	if err := domain.SetProgression(progression); err != nil {
+		log.Fatalf("failed to apply progression: %v", err)
+	}
+
+	if err := domain.SetDefeatPolicy(domain.DefeatPolicy{
+		GoldPenaltyPercent: cfg.Fight.DefeatGoldPenaltyPercent,
+		ExpPenaltyPercent:  cfg.Fight.DefeatExpPenaltyPercent,
+		WeakenedFor:        cfg.Fight.WeakenedFor,
+		WeakenedPercent:    cfg.Fight.WeakenedPercent,
+	}); err != nil {
+		log.Fatalf("failed to apply defeat policy: %v", err)
+	}
+
 	db, err := repository.New()
 	if err != nil {
 		log.Fatalf("failed to initialize database: %v", err)
@@ -71,7 +90,14 @@ func main() {
 
 	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
 
-	api.SetupRoutes(e, db.DB(), cfg)
+	cellsMovingWorker := worker.NewCellsMovingWorker(
+		db.DB(),
+		repository.NewLocationRepository(db.DB()),
+		repository.NewUserRepository(db.DB()),
+		5*time.Second,
+	)
+
+	api.SetupRoutes(e, db.DB(), cfg, cellsMovingWorker)
 
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
@@ -84,8 +110,24 @@ func main() {
 		}
 	}()
 
-	hpWorker := worker.NewHpWorker(db.DB(), 3*time.Second)
-	go hpWorker.StartWorker(ctx)
+	if cfg.WSBroker == "postgres" {
+		broker := ws.NewPostgresBroker(db.DB(), cfg.Database.DSN())
+		go func() {
+			if err := ws.GetHub().UseBroker(ctx, broker); err != nil {
+				log.Printf("ws broker stopped: %v", err)
+			}
+		}()
+	}
+
+	go cellsMovingWorker.StartWorker(ctx)
+	go worker.NewProgressionReloader(cfg.ProgressionFile).Start(ctx)
+	go worker.NewHpWorker(db.DB()).Start(ctx, 3*time.Second)
+
+	scheduler := worker.NewScheduler(db.DB())
+	scheduler.Register(worker.NewDuelTimeoutWorker(db.DB()).Job(5 * time.Second))
+	scheduler.Register(worker.NewGroupFightTimeoutWorker(db.DB()).Job(5 * time.Second))
+	scheduler.Register(worker.NewFightTimeoutWorker(db.DB(), cfg.Fight.RoundTimeout, cfg.Fight.MaxMissedRounds).Job(5 * time.Second))
+	go scheduler.Start(ctx)
 
 	<-ctx.Done()
 
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"moonshine/internal/api/services"
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+// simulate runs many fights between a player profile and a bot and prints
+// balance numbers. Bots and items can come from the database by slug or be
+// described entirely by flags, so new bots can be tuned before they exist.
+func main() {
+	fights := flag.Int("fights", 10000, "Number of fights to simulate")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "Random seed, reuse it to reproduce a run")
+	roundSeconds := flag.Float64("round-seconds", 5, "Seconds a player spends per round")
+	progressionFile := flag.String("progression", "", "Progression JSON to evaluate (default: built-in curve)")
+
+	level := flag.Uint("level", 1, "Player level")
+	exp := flag.Uint("exp", 0, "Player experience")
+	attack := flag.Uint("attack", 1, "Player attack without items")
+	defense := flag.Uint("defense", 1, "Player defense without items")
+	hp := flag.Uint("hp", 20, "Player HP without items")
+	items := flag.String("items", "", "Comma-separated equipment item slugs to wear (needs the database)")
+
+	botSlug := flag.String("bot", "", "Bot slug to load from the database")
+	botLevel := flag.Uint("bot-level", 1, "Bot level, when -bot is not set")
+	botAttack := flag.Uint("bot-attack", 2, "Bot attack, when -bot is not set")
+	botDefense := flag.Uint("bot-defense", 10, "Bot defense, when -bot is not set")
+	botHp := flag.Uint("bot-hp", 20, "Bot HP, when -bot is not set")
+	botStrategy := flag.String("bot-strategy", string(domain.BotStrategyRandom), "Bot strategy, when -bot is not set")
+	flag.Parse()
+
+	progression, err := domain.LoadProgression(*progressionFile)
+	if err != nil {
+		log.Fatalf("failed to load progression: %v", err)
+	}
+
+	player := &domain.User{
+		Level:   *level,
+		Exp:     *exp,
+		Attack:  *attack,
+		Defense: *defense,
+		Hp:      *hp,
+	}
+
+	bot := &domain.Bot{
+		Name:     "custom",
+		Level:    *botLevel,
+		Attack:   *botAttack,
+		Defense:  *botDefense,
+		Hp:       *botHp,
+		Strategy: domain.BotStrategy(strings.ToUpper(*botStrategy)),
+	}
+
+	var equipment []*domain.EquipmentItem
+	if *botSlug != "" || *items != "" {
+		if err := godotenv.Load(); err != nil {
+			log.Println(".env not loaded, relying on environment")
+		}
+
+		db, err := repository.New()
+		if err != nil {
+			log.Fatalf("failed to initialize database: %v", err)
+		}
+		defer db.Close()
+
+		if *botSlug != "" {
+			bot, err = repository.NewBotRepository(db.DB()).FindBySlug(*botSlug)
+			if err != nil {
+				log.Fatalf("failed to load bot %q: %v", *botSlug, err)
+			}
+			if bot.IsBoss {
+				if bot.Phases, err = repository.NewBotRepository(db.DB()).FindPhasesByBotID(bot.ID); err != nil {
+					log.Fatalf("failed to load phases of %q: %v", *botSlug, err)
+				}
+			}
+		}
+
+		itemRepo := repository.NewEquipmentItemRepository(db.DB())
+		for _, slug := range strings.Split(*items, ",") {
+			slug = strings.TrimSpace(slug)
+			if slug == "" {
+				continue
+			}
+			item, err := itemRepo.FindBySlug(slug)
+			if err != nil {
+				log.Fatalf("failed to load item %q: %v", slug, err)
+			}
+			equipment = append(equipment, item)
+		}
+	}
+
+	report, err := services.SimulateFights(player, equipment, bot, services.SimulationConfig{
+		Fights:       *fights,
+		RoundSeconds: *roundSeconds,
+		Rand:         services.NewRand(*seed),
+		Progression:  progression,
+	})
+	if err != nil {
+		log.Fatalf("simulation failed: %v", err)
+	}
+
+	printReport(player, equipment, bot, *seed, report)
+}
+
+func printReport(player *domain.User, items []*domain.EquipmentItem, bot *domain.Bot, seed int64, r *services.SimulationReport) {
+	attack, defense, hp := player.Attack, player.Defense, player.Hp
+	for _, item := range items {
+		attack += item.Attack
+		defense += item.Defense
+		hp += item.Hp
+	}
+
+	w := os.Stdout
+	fmt.Fprintf(w, "seed:            %d\n", seed)
+	fmt.Fprintf(w, "player:          level %d, attack %d, defense %d, hp %d (%d items)\n",
+		player.Level, attack, defense, hp, len(items))
+	fmt.Fprintf(w, "bot:             %s, level %d, attack %d, defense %d, hp %d, strategy %s\n",
+		bot.Name, bot.Level, bot.Attack, bot.Defense, bot.Hp, bot.Strategy)
+	fmt.Fprintf(w, "fights:          %d (%d stalemates)\n", r.Fights, r.Stalemates)
+	fmt.Fprintf(w, "win rate:        %.1f%%\n", r.WinRate*100)
+	fmt.Fprintf(w, "avg rounds:      %.2f\n", r.AvgRounds)
+	fmt.Fprintf(w, "avg exp:         %.2f\n", r.AvgExp)
+	fmt.Fprintf(w, "avg gold:        %.2f\n", r.AvgGold)
+	fmt.Fprintf(w, "exp per hour:    %.0f\n", r.ExpPerHour)
+	fmt.Fprintf(w, "gold per hour:   %.0f\n", r.GoldPerHour)
+
+	if r.ExpToLevel == 0 {
+		fmt.Fprintln(w, "time to level:   max level reached")
+		return
+	}
+	fmt.Fprintf(w, "exp to level:    %d\n", r.ExpToLevel)
+	fmt.Fprintf(w, "fights to level: %.1f (designed for %d)\n", r.FightsToLevel, r.BotsToLevel)
+	if r.HoursToLevel > 0 {
+		fmt.Fprintf(w, "time to level:   %.2fh\n", r.HoursToLevel)
+	} else {
+		fmt.Fprintln(w, "time to level:   never")
+	}
+}
@@ -0,0 +1,41 @@
+import { useEffect, useState } from 'react'
+import { innAPI } from '../../lib/api'
+
+export default function Inn() {
+  const [quote, setQuote] = useState(null)
+  const [healing, setHealing] = useState(false)
+
+  const loadQuote = () => {
+    innAPI.getQuote()
+      .then(setQuote)
+      .catch((error) => console.error('[Inn] Failed to load quote:', error))
+  }
+
+  useEffect(loadQuote, [])
+
+  const handleHeal = async () => {
+    setHealing(true)
+    try {
+      await innAPI.heal()
+      loadQuote()
+    } catch (error) {
+      alert(`Ошибка лечения: ${error.message}`)
+    } finally {
+      setHealing(false)
+    }
+  }
+
+  return (
+    <div className="location-inner-content">
+      <h2>Таверна</h2>
+      {quote && quote.missingHp > 0 ? (
+        <>
+          <p>Не хватает {quote.missingHp} HP. Полное лечение стоит {quote.price} золота.</p>
+          <button onClick={handleHeal} disabled={healing}>Вылечиться</button>
+        </>
+      ) : (
+        <p>Вы полностью здоровы.</p>
+      )}
+    </div>
+  )
+}
@@ -45,6 +45,7 @@ export default function LocationView({ slug, children }) {
   const cityLinks = [
     { to: '/locations/weapon_shop', label: 'Оружейная' },
     { to: '/locations/shop_of_artifacts', label: 'Артефакты' },
+    { to: '/locations/inn', label: 'Таверна' },
     { to: '/locations/wayward_pines', label: 'Выйти из города' },
   ]
 
@@ -193,6 +193,88 @@ export const equipmentAPI = {
   },
 }
 
+export const consumableAPI = {
+  getAll: async () => {
+    const response = await fetch(`${API_BASE_URL}/consumable_items`, {
+      method: 'GET',
+      headers: getAuthHeaders(),
+    })
+
+    const data = await parseResponse(response)
+    if (!response.ok) {
+      throw new Error(data?.error || 'Failed to fetch consumables')
+    }
+    return data || []
+  },
+
+  getMine: async () => {
+    const response = await fetch(`${API_BASE_URL}/users/me/consumables`, {
+      method: 'GET',
+      headers: getAuthHeaders(),
+    })
+
+    const data = await parseResponse(response)
+    if (!response.ok) {
+      throw new Error(data?.error || 'Failed to fetch consumables')
+    }
+    return data || []
+  },
+
+  buy: async (itemSlug) => {
+    const response = await fetch(`${API_BASE_URL}/consumable_items/${itemSlug}/buy`, {
+      method: 'POST',
+      headers: getAuthHeaders(),
+    })
+
+    const data = await parseResponse(response)
+    if (!response.ok) {
+      throw new Error(data?.error || 'Failed to buy item')
+    }
+    return data
+  },
+
+  use: async (inventoryId) => {
+    const response = await fetch(`${API_BASE_URL}/inventory/${inventoryId}/use`, {
+      method: 'POST',
+      headers: getAuthHeaders(),
+    })
+
+    const data = await parseResponse(response)
+    if (!response.ok) {
+      throw new Error(data?.error || 'Failed to use item')
+    }
+    return data
+  },
+}
+
+export const innAPI = {
+  getQuote: async () => {
+    const response = await fetch(`${API_BASE_URL}/inn`, {
+      method: 'GET',
+      headers: getAuthHeaders(),
+    })
+
+    const data = await parseResponse(response)
+    if (!response.ok) {
+      throw new Error(data?.error || 'Failed to fetch inn price')
+    }
+    return data
+  },
+
+  heal: async () => {
+    const response = await fetch(`${API_BASE_URL}/inn/heal`, {
+      method: 'POST',
+      headers: getAuthHeaders(),
+    })
+
+    const data = await parseResponse(response)
+    if (!response.ok) {
+      throw new Error(data?.error || 'Failed to heal')
+    }
+    return data
+  },
+}
+
 export const avatarAPI = {
   getAll: async () => {
     const response = await fetch(`${API_BASE_URL}/avatars`, {
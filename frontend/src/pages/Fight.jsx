@@ -453,6 +453,28 @@ export default function Fight() {
                   }
                 }
                 
+                if (round.usedItemId) {
+                  parts.push(`${playerName} использовал предмет`)
+                }
+                if (round.critical) {
+                  parts.push(`${playerName} нанес критический удар`)
+                }
+                if (round.dodged) {
+                  parts.push(`${playerName} увернулся от удара`)
+                }
+                if (round.shieldBlocked) {
+                  parts.push(`${playerName} принял удар на щит`)
+                }
+                if (round.botCritical) {
+                  parts.push(`${botName} нанес критический удар`)
+                }
+                if (round.botDodged) {
+                  parts.push(`${botName} увернулся от удара`)
+                }
+                if (round.botShieldBlocked) {
+                  parts.push(`${botName} принял удар на щит`)
+                }
+                
                 const roundText = parts.join('. ') + '.'
                 
                 const renderRoundText = (text) => {
@@ -3,6 +3,7 @@ import LocationView from '../components/locations/LocationView'
 import MoonshineCity from '../components/locations/MoonshineCity'
 import WeaponShop from '../components/locations/WeaponShop'
 import ArtifactsShop from '../components/locations/ArtifactsShop'
+import Inn from '../components/locations/Inn'
 import WaywardPines from '../components/locations/WaywardPines'
 
 export default function Location() {
@@ -16,6 +17,8 @@ export default function Location() {
         return <WeaponShop />
       case 'shop_of_artifacts':
         return <ArtifactsShop />
+      case 'inn':
+        return <Inn />
       case 'wayward_pines':
         return <WaywardPines />
       default:
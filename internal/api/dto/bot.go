@@ -7,16 +7,20 @@ import (
 )
 
 type Bot struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Slug      string    `json:"slug"`
-	Attack    int       `json:"attack"`
-	Defense   int       `json:"defense"`
-	Hp        int       `json:"hp"`
-	CurrentHp int       `json:"currentHp"`
-	Level     int       `json:"level"`
-	Avatar    string    `json:"avatar"`
-	CreatedAt time.Time `json:"createdAt"`
+	ID         string     `json:"id"`
+	InstanceID string     `json:"instanceId,omitempty"`
+	Name       string     `json:"name"`
+	Slug       string     `json:"slug"`
+	Attack     int        `json:"attack"`
+	Defense    int        `json:"defense"`
+	Hp         int        `json:"hp"`
+	CurrentHp  int        `json:"currentHp"`
+	Level      int        `json:"level"`
+	Avatar     string     `json:"avatar"`
+	IsBoss     bool       `json:"isBoss"`
+	State      string     `json:"state,omitempty"`
+	RespawnAt  *time.Time `json:"respawnAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
 }
 
 func BotFromDomain(bot *domain.Bot) *Bot {
@@ -34,6 +38,7 @@ func BotFromDomain(bot *domain.Bot) *Bot {
 		CurrentHp: int(bot.Hp),
 		Level:     int(bot.Level),
 		Avatar:    bot.Avatar,
+		IsBoss:    bot.IsBoss,
 		CreatedAt: bot.CreatedAt,
 	}
 }
@@ -45,3 +50,28 @@ func BotsFromDomain(bots []*domain.Bot) []*Bot {
 	}
 	return result
 }
+
+// BotInstanceFromDomain describes a spawned bot: its stats come from the bot,
+// the HP and state from the instance.
+func BotInstanceFromDomain(instance *domain.BotInstance) *Bot {
+	if instance == nil || instance.Bot == nil {
+		return nil
+	}
+
+	bot := BotFromDomain(instance.Bot)
+	bot.InstanceID = instance.ID.String()
+	bot.CurrentHp = int(instance.CurrentHp)
+	bot.State = string(instance.State)
+	bot.RespawnAt = instance.RespawnAt
+	return bot
+}
+
+func BotInstancesFromDomain(instances []*domain.BotInstance) []*Bot {
+	result := make([]*Bot, 0, len(instances))
+	for _, instance := range instances {
+		if bot := BotInstanceFromDomain(instance); bot != nil {
+			result = append(result, bot)
+		}
+	}
+	return result
+}
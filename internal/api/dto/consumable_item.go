@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"time"
+
+	"moonshine/internal/domain"
+)
+
+type ConsumableItem struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	Slug            string    `json:"slug"`
+	Image           string    `json:"image"`
+	Price           int       `json:"price"`
+	RequiredLevel   int       `json:"requiredLevel"`
+	HealHp          int       `json:"healHp"`
+	BuffAttack      int       `json:"buffAttack"`
+	BuffDefense     int       `json:"buffDefense"`
+	BuffCritChance  int       `json:"buffCritChance"`
+	BuffDodgeChance int       `json:"buffDodgeChance"`
+	BuffSeconds     int       `json:"buffSeconds"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// InventoryConsumable is one consumable in the inventory; ID is what
+// POST /api/inventory/:id/use takes.
+type InventoryConsumable struct {
+	ID   string          `json:"id"`
+	Item *ConsumableItem `json:"item"`
+}
+
+type Buff struct {
+	ID               string    `json:"id"`
+	ConsumableItemID string    `json:"consumableItemId"`
+	Attack           int       `json:"attack"`
+	Defense          int       `json:"defense"`
+	CritChance       int       `json:"critChance"`
+	DodgeChance      int       `json:"dodgeChance"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+}
+
+// UseConsumableResponse says what using a consumable did. Fight is set when
+// it was used in a fight and the turn went to the bot.
+type UseConsumableResponse struct {
+	Item      *ConsumableItem `json:"item"`
+	CurrentHp int             `json:"currentHp"`
+	Hp        int             `json:"hp"`
+	Buff      *Buff           `json:"buff,omitempty"`
+	Fight     *Fight          `json:"fight,omitempty"`
+}
+
+func ConsumableItemFromDomain(item *domain.ConsumableItem) *ConsumableItem {
+	if item == nil {
+		return nil
+	}
+
+	return &ConsumableItem{
+		ID:              item.ID.String(),
+		Name:            item.Name,
+		Slug:            item.Slug,
+		Image:           item.Image,
+		Price:           int(item.Price),
+		RequiredLevel:   int(item.RequiredLevel),
+		HealHp:          int(item.HealHp),
+		BuffAttack:      int(item.BuffAttack),
+		BuffDefense:     int(item.BuffDefense),
+		BuffCritChance:  int(item.BuffCritChance),
+		BuffDodgeChance: int(item.BuffDodgeChance),
+		BuffSeconds:     int(item.BuffSeconds),
+		CreatedAt:       item.CreatedAt,
+	}
+}
+
+func ConsumableItemsFromDomain(items []*domain.ConsumableItem) []*ConsumableItem {
+	result := make([]*ConsumableItem, len(items))
+	for i, item := range items {
+		result[i] = ConsumableItemFromDomain(item)
+	}
+	return result
+}
+
+func InventoryConsumablesFromDomain(consumables []*domain.InventoryConsumable) []*InventoryConsumable {
+	result := make([]*InventoryConsumable, len(consumables))
+	for i, c := range consumables {
+		result[i] = &InventoryConsumable{
+			ID:   c.ID.String(),
+			Item: ConsumableItemFromDomain(c.Item),
+		}
+	}
+	return result
+}
+
+func BuffFromDomain(buff *domain.UserBuff) *Buff {
+	if buff == nil {
+		return nil
+	}
+
+	return &Buff{
+		ID:               buff.ID.String(),
+		ConsumableItemID: buff.ConsumableItemID.String(),
+		Attack:           int(buff.Attack),
+		Defense:          int(buff.Defense),
+		CritChance:       int(buff.CritChance),
+		DodgeChance:      int(buff.DodgeChance),
+		ExpiresAt:        buff.ExpiresAt,
+	}
+}
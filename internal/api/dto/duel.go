@@ -0,0 +1,139 @@
+package dto
+
+import (
+	"time"
+
+	"moonshine/internal/domain"
+)
+
+type DuelRound struct {
+	ID                      string    `json:"id"`
+	DuelID                  string    `json:"duelId"`
+	Status                  string    `json:"status"`
+	DeadlineAt              time.Time `json:"deadlineAt"`
+	ChallengerHp            int       `json:"challengerHp"`
+	OpponentHp              int       `json:"opponentHp"`
+	ChallengerDamage        int       `json:"challengerDamage"`
+	OpponentDamage          int       `json:"opponentDamage"`
+	ChallengerMoved         bool      `json:"challengerMoved"`
+	OpponentMoved           bool      `json:"opponentMoved"`
+	ChallengerAttackPoint   *string   `json:"challengerAttackPoint,omitempty"`
+	ChallengerDefensePoint  *string   `json:"challengerDefensePoint,omitempty"`
+	OpponentAttackPoint     *string   `json:"opponentAttackPoint,omitempty"`
+	OpponentDefensePoint    *string   `json:"opponentDefensePoint,omitempty"`
+	ChallengerCritical      bool      `json:"challengerCritical"`
+	ChallengerDodged        bool      `json:"challengerDodged"`
+	ChallengerShieldBlocked bool      `json:"challengerShieldBlocked"`
+	OpponentCritical        bool      `json:"opponentCritical"`
+	OpponentDodged          bool      `json:"opponentDodged"`
+	OpponentShieldBlocked   bool      `json:"opponentShieldBlocked"`
+	CreatedAt               time.Time `json:"createdAt"`
+}
+
+type Duel struct {
+	ID           string  `json:"id"`
+	ChallengerID string  `json:"challengerId"`
+	OpponentID   string  `json:"opponentId"`
+	LocationID   string  `json:"locationId"`
+	Status       string  `json:"status"`
+	WinnerID     *string `json:"winnerId,omitempty"`
+	// ChallengerDefeat and OpponentDefeat are set for whoever lost the duel.
+	ChallengerDefeat *FightDefeat `json:"challengerDefeat,omitempty"`
+	OpponentDefeat   *FightDefeat `json:"opponentDefeat,omitempty"`
+	Rounds           []*DuelRound `json:"rounds"`
+	CreatedAt        time.Time    `json:"createdAt"`
+}
+
+// DuelRoundFromDomain hides the moves of a round that is still in progress,
+// so neither participant can see what the other one picked.
+func DuelRoundFromDomain(round *domain.DuelRound) *DuelRound {
+	if round == nil {
+		return nil
+	}
+
+	result := &DuelRound{
+		ID:               round.ID.String(),
+		DuelID:           round.DuelID.String(),
+		Status:           string(round.Status),
+		DeadlineAt:       round.DeadlineAt,
+		ChallengerHp:     int(round.ChallengerHp),
+		OpponentHp:       int(round.OpponentHp),
+		ChallengerDamage: int(round.ChallengerDamage),
+		OpponentDamage:   int(round.OpponentDamage),
+		ChallengerMoved:  round.ChallengerChose(),
+		OpponentMoved:    round.OpponentChose(),
+		CreatedAt:        round.CreatedAt,
+	}
+
+	if round.Status != domain.RoundStatusFinished {
+		return result
+	}
+
+	result.ChallengerCritical = round.ChallengerCritical
+	result.ChallengerDodged = round.ChallengerDodged
+	result.ChallengerShieldBlocked = round.ChallengerShieldBlocked
+	result.OpponentCritical = round.OpponentCritical
+	result.OpponentDodged = round.OpponentDodged
+	result.OpponentShieldBlocked = round.OpponentShieldBlocked
+
+	if round.ChallengerAttackPoint != nil {
+		part := string(*round.ChallengerAttackPoint)
+		result.ChallengerAttackPoint = &part
+	}
+	if round.ChallengerDefensePoint != nil {
+		part := string(*round.ChallengerDefensePoint)
+		result.ChallengerDefensePoint = &part
+	}
+	if round.OpponentAttackPoint != nil {
+		part := string(*round.OpponentAttackPoint)
+		result.OpponentAttackPoint = &part
+	}
+	if round.OpponentDefensePoint != nil {
+		part := string(*round.OpponentDefensePoint)
+		result.OpponentDefensePoint = &part
+	}
+
+	return result
+}
+
+func DuelRoundsFromDomain(rounds []*domain.DuelRound) []*DuelRound {
+	result := make([]*DuelRound, len(rounds))
+	for i, round := range rounds {
+		result[i] = DuelRoundFromDomain(round)
+	}
+	return result
+}
+
+func DuelFromDomain(duel *domain.Duel) *Duel {
+	if duel == nil {
+		return nil
+	}
+
+	result := &Duel{
+		ID:           duel.ID.String(),
+		ChallengerID: duel.ChallengerID.String(),
+		OpponentID:   duel.OpponentID.String(),
+		LocationID:   duel.LocationID.String(),
+		Status:       string(duel.Status),
+		CreatedAt:    duel.CreatedAt,
+		Rounds:       []*DuelRound{},
+	}
+
+	if duel.Rounds != nil {
+		result.Rounds = DuelRoundsFromDomain(duel.Rounds)
+	}
+
+	if duel.WinnerID != nil {
+		id := duel.WinnerID.String()
+		result.WinnerID = &id
+	}
+
+	result.ChallengerDefeat = defeatFromDomain(0, 0, duel.ChallengerRespawnLocationID, duel.ChallengerWeakenedUntil)
+	result.OpponentDefeat = defeatFromDomain(0, 0, duel.OpponentRespawnLocationID, duel.OpponentWeakenedUntil)
+
+	return result
+}
+
+type ChallengeDuelRequest struct {
+	Username string `json:"username" validate:"required"`
+}
@@ -13,6 +13,10 @@ type EquipmentItem struct {
 	Attack        int       `json:"attack"`
 	Defense       int       `json:"defense"`
 	Hp            int       `json:"hp"`
+	CritChance    int       `json:"critChance"`
+	CritPower     int       `json:"critPower"`
+	DodgeChance   int       `json:"dodgeChance"`
+	BlockChance   int       `json:"blockChance"`
 	RequiredLevel int       `json:"requiredLevel"`
 	Price         int       `json:"price"`
 	Artifact      bool      `json:"artifact"`
@@ -33,6 +37,10 @@ func EquipmentItemFromDomain(item *domain.EquipmentItem) *EquipmentItem {
 		Attack:        int(item.Attack),
 		Defense:       int(item.Defense),
 		Hp:            int(item.Hp),
+		CritChance:    int(item.CritChance),
+		CritPower:     int(item.CritPower),
+		DodgeChance:   int(item.DodgeChance),
+		BlockChance:   int(item.BlockChance),
 		RequiredLevel: int(item.RequiredLevel),
 		Price:         int(item.Price),
 		Artifact:      item.Artifact,
@@ -3,6 +3,8 @@ package dto
 import (
 	"time"
 
+	"github.com/google/uuid"
+
 	"moonshine/internal/domain"
 )
 
@@ -18,19 +20,73 @@ type Round struct {
 	PlayerDefensePoint *string   `json:"playerDefensePoint,omitempty"`
 	BotAttackPoint     *string   `json:"botAttackPoint,omitempty"`
 	BotDefensePoint    *string   `json:"botDefensePoint,omitempty"`
+	TimedOut           bool      `json:"timedOut"`
+	BotAbility         *string   `json:"botAbility,omitempty"`
+	Critical           bool      `json:"critical"`
+	Dodged             bool      `json:"dodged"`
+	ShieldBlocked      bool      `json:"shieldBlocked"`
+	BotCritical        bool      `json:"botCritical"`
+	BotDodged          bool      `json:"botDodged"`
+	BotShieldBlocked   bool      `json:"botShieldBlocked"`
+	UsedItemID         *string   `json:"usedItemId,omitempty"`
 	CreatedAt          time.Time `json:"createdAt"`
 }
 
 type Fight struct {
-	ID            string    `json:"id"`
-	UserID        string    `json:"userId"`
-	BotID         string    `json:"botId"`
-	Status        string    `json:"status"`
-	DroppedGold   int       `json:"droppedGold"`
-	Exp           int       `json:"exp"`
-	DroppedItemID *string   `json:"droppedItemId,omitempty"`
-	Rounds        []*Round  `json:"rounds"`
-	CreatedAt     time.Time `json:"createdAt"`
+	ID                string          `json:"id"`
+	UserID            string          `json:"userId"`
+	BotID             string          `json:"botId"`
+	Status            string          `json:"status"`
+	Outcome           string          `json:"outcome,omitempty"`
+	Bot               *Bot            `json:"bot,omitempty"`
+	DroppedGold       int             `json:"droppedGold"`
+	Exp               int             `json:"exp"`
+	DroppedItemID     *string         `json:"droppedItemId,omitempty"`
+	DroppedItem       *EquipmentItem  `json:"droppedItem,omitempty"`
+	DroppedConsumable *ConsumableItem `json:"droppedConsumable,omitempty"`
+	Defeat            *FightDefeat    `json:"defeat,omitempty"`
+	PlayerArmour      *Armour         `json:"playerArmour,omitempty"`
+	Rounds            []*Round        `json:"rounds"`
+	CreatedAt         time.Time       `json:"createdAt"`
+}
+
+// Armour is the defense the player's equipment gives each body part.
+type Armour struct {
+	Head  int `json:"head"`
+	Neck  int `json:"neck"`
+	Chest int `json:"chest"`
+	Belt  int `json:"belt"`
+	Legs  int `json:"legs"`
+}
+
+func ArmourFromDomain(armour *domain.Armour) *Armour {
+	if armour == nil {
+		return nil
+	}
+
+	return &Armour{
+		Head:  int(armour.Head),
+		Neck:  int(armour.Neck),
+		Chest: int(armour.Chest),
+		Belt:  int(armour.Belt),
+		Legs:  int(armour.Legs),
+	}
+}
+
+// FightDefeat is what losing the fight cost the player.
+type FightDefeat struct {
+	LostGold            int        `json:"lostGold"`
+	LostExp             int        `json:"lostExp"`
+	RespawnLocationID   string     `json:"respawnLocationId"`
+	RespawnLocationSlug string     `json:"respawnLocationSlug,omitempty"`
+	WeakenedUntil       *time.Time `json:"weakenedUntil,omitempty"`
+}
+
+type FightsResponse struct {
+	Fights []*Fight `json:"fights"`
+	Total  int      `json:"total"`
+	Limit  int      `json:"limit"`
+	Offset int      `json:"offset"`
 }
 
 func RoundFromDomain(round *domain.Round) *Round {
@@ -39,14 +95,21 @@ func RoundFromDomain(round *domain.Round) *Round {
 	}
 
 	result := &Round{
-		ID:           round.ID.String(),
-		FightID:      round.FightID.String(),
-		PlayerDamage: int(round.PlayerDamage),
-		BotDamage:    int(round.BotDamage),
-		Status:       string(round.Status),
-		PlayerHp:     int(round.PlayerHp),
-		BotHp:        int(round.BotHp),
-		CreatedAt:    round.CreatedAt,
+		ID:               round.ID.String(),
+		FightID:          round.FightID.String(),
+		PlayerDamage:     int(round.PlayerDamage),
+		BotDamage:        int(round.BotDamage),
+		Status:           string(round.Status),
+		PlayerHp:         int(round.PlayerHp),
+		BotHp:            int(round.BotHp),
+		TimedOut:         round.TimedOut,
+		Critical:         round.Critical,
+		Dodged:           round.Dodged,
+		ShieldBlocked:    round.ShieldBlocked,
+		BotCritical:      round.BotCritical,
+		BotDodged:        round.BotDodged,
+		BotShieldBlocked: round.BotShieldBlocked,
+		CreatedAt:        round.CreatedAt,
 	}
 
 	if round.PlayerAttackPoint != nil {
@@ -65,6 +128,14 @@ func RoundFromDomain(round *domain.Round) *Round {
 		part := string(*round.BotDefensePoint)
 		result.BotDefensePoint = &part
 	}
+	if round.BotAbility != nil {
+		ability := string(*round.BotAbility)
+		result.BotAbility = &ability
+	}
+	if round.ConsumableItemID != nil {
+		id := round.ConsumableItemID.String()
+		result.UsedItemID = &id
+	}
 
 	return result
 }
@@ -83,14 +154,17 @@ func FightFromDomain(fight *domain.Fight) *Fight {
 	}
 
 	result := &Fight{
-		ID:          fight.ID.String(),
-		UserID:      fight.UserID.String(),
-		BotID:       fight.BotID.String(),
-		Status:      string(fight.Status),
-		DroppedGold: int(fight.DroppedGold),
-		Exp:         int(fight.Exp),
-		CreatedAt:   fight.CreatedAt,
-		Rounds:      []*Round{},
+		ID:           fight.ID.String(),
+		UserID:       fight.UserID.String(),
+		BotID:        fight.BotID.String(),
+		Status:       string(fight.Status),
+		Outcome:      string(fight.Outcome),
+		Bot:          BotFromDomain(fight.Bot),
+		DroppedGold:  int(fight.DroppedGold),
+		Exp:          int(fight.Exp),
+		PlayerArmour: ArmourFromDomain(fight.PlayerArmour),
+		CreatedAt:    fight.CreatedAt,
+		Rounds:       []*Round{},
 	}
 
 	if fight.Rounds != nil {
@@ -102,5 +176,41 @@ func FightFromDomain(fight *domain.Fight) *Fight {
 		result.DroppedItemID = &id
 	}
 
+	if fight.DroppedItem != nil {
+		result.DroppedItem = EquipmentItemFromDomain(fight.DroppedItem)
+	}
+
+	if fight.DroppedConsumable != nil {
+		result.DroppedConsumable = ConsumableItemFromDomain(fight.DroppedConsumable)
+	}
+
+	result.Defeat = defeatFromDomain(fight.LostGold, fight.LostExp, fight.RespawnLocationID, fight.WeakenedUntil)
+	if result.Defeat != nil && fight.RespawnLocation != nil {
+		result.Defeat.RespawnLocationSlug = fight.RespawnLocation.Slug
+	}
+
+	return result
+}
+
+// defeatFromDomain returns nil unless the player was carried to a respawn
+// location.
+func defeatFromDomain(lostGold, lostExp uint, respawnLocationID *uuid.UUID, weakenedUntil *time.Time) *FightDefeat {
+	if respawnLocationID == nil {
+		return nil
+	}
+
+	return &FightDefeat{
+		LostGold:          int(lostGold),
+		LostExp:           int(lostExp),
+		RespawnLocationID: respawnLocationID.String(),
+		WeakenedUntil:     weakenedUntil,
+	}
+}
+
+func FightsFromDomain(fights []*domain.Fight) []*Fight {
+	result := make([]*Fight, len(fights))
+	for i, fight := range fights {
+		result[i] = FightFromDomain(fight)
+	}
 	return result
 }
@@ -0,0 +1,172 @@
+package dto
+
+import (
+	"time"
+
+	"moonshine/internal/domain"
+)
+
+type GroupFightMember struct {
+	UserID   string `json:"userId"`
+	Username string `json:"username"`
+	Hp       int    `json:"hp"`
+	Damage   int    `json:"damage"`
+	Exp      int    `json:"exp"`
+	Gold     int    `json:"gold"`
+	// Defeat is set once the bot knocked the member out.
+	Defeat *FightDefeat `json:"defeat,omitempty"`
+}
+
+type GroupFightMove struct {
+	UserID           string  `json:"userId"`
+	AttackPoint      *string `json:"attackPoint,omitempty"`
+	DefensePoint     *string `json:"defensePoint,omitempty"`
+	Damage           int     `json:"damage"`
+	Critical         bool    `json:"critical"`
+	BotDodged        bool    `json:"botDodged"`
+	BotShieldBlocked bool    `json:"botShieldBlocked"`
+}
+
+type GroupFightRound struct {
+	ID                  string            `json:"id"`
+	Status              string            `json:"status"`
+	DeadlineAt          time.Time         `json:"deadlineAt"`
+	BotHp               int               `json:"botHp"`
+	TargetID            *string           `json:"targetId,omitempty"`
+	BotAttackPoint      *string           `json:"botAttackPoint,omitempty"`
+	BotDefensePoint     *string           `json:"botDefensePoint,omitempty"`
+	BotDamage           int               `json:"botDamage"`
+	BotAbility          *string           `json:"botAbility,omitempty"`
+	BotCritical         bool              `json:"botCritical"`
+	TargetDodged        bool              `json:"targetDodged"`
+	TargetShieldBlocked bool              `json:"targetShieldBlocked"`
+	Moves               []*GroupFightMove `json:"moves"`
+	CreatedAt           time.Time         `json:"createdAt"`
+}
+
+type GroupFight struct {
+	ID            string              `json:"id"`
+	LeaderID      string              `json:"leaderId"`
+	BotID         string              `json:"botId"`
+	LocationID    string              `json:"locationId"`
+	Status        string              `json:"status"`
+	MaxMembers    int                 `json:"maxMembers"`
+	Bot           *Bot                `json:"bot,omitempty"`
+	DroppedItemID *string             `json:"droppedItemId,omitempty"`
+	Members       []*GroupFightMember `json:"members"`
+	Rounds        []*GroupFightRound  `json:"rounds"`
+	CreatedAt     time.Time           `json:"createdAt"`
+}
+
+type GroupFightsResponse struct {
+	GroupFights []*GroupFight `json:"groupFights"`
+}
+
+type StartGroupFightRequest struct {
+	BotSlug string `json:"botSlug" validate:"required"`
+}
+
+// GroupFightRoundFromDomain hides the points of a round that is still in
+// progress; members only see who has already moved.
+func GroupFightRoundFromDomain(round *domain.GroupFightRound) *GroupFightRound {
+	if round == nil {
+		return nil
+	}
+
+	result := &GroupFightRound{
+		ID:         round.ID.String(),
+		Status:     string(round.Status),
+		DeadlineAt: round.DeadlineAt,
+		BotHp:      int(round.BotHp),
+		BotDamage:  int(round.BotDamage),
+		Moves:      make([]*GroupFightMove, len(round.Moves)),
+		CreatedAt:  round.CreatedAt,
+	}
+
+	finished := round.Status == domain.RoundStatusFinished
+	for i, move := range round.Moves {
+		result.Moves[i] = &GroupFightMove{UserID: move.UserID.String(), Damage: int(move.Damage)}
+		if finished {
+			attack, defense := string(move.AttackPoint), string(move.DefensePoint)
+			result.Moves[i].AttackPoint, result.Moves[i].DefensePoint = &attack, &defense
+			result.Moves[i].Critical = move.Critical
+			result.Moves[i].BotDodged, result.Moves[i].BotShieldBlocked = move.BotDodged, move.BotShieldBlocked
+		}
+	}
+
+	if !finished {
+		return result
+	}
+
+	result.BotCritical = round.BotCritical
+	result.TargetDodged, result.TargetShieldBlocked = round.TargetDodged, round.TargetShieldBlocked
+
+	if round.TargetID != nil {
+		id := round.TargetID.String()
+		result.TargetID = &id
+	}
+	if round.BotAttackPoint != nil {
+		part := string(*round.BotAttackPoint)
+		result.BotAttackPoint = &part
+	}
+	if round.BotDefensePoint != nil {
+		part := string(*round.BotDefensePoint)
+		result.BotDefensePoint = &part
+	}
+	if round.BotAbility != nil {
+		ability := string(*round.BotAbility)
+		result.BotAbility = &ability
+	}
+
+	return result
+}
+
+func GroupFightFromDomain(fight *domain.GroupFight) *GroupFight {
+	if fight == nil {
+		return nil
+	}
+
+	result := &GroupFight{
+		ID:         fight.ID.String(),
+		LeaderID:   fight.LeaderID.String(),
+		BotID:      fight.BotID.String(),
+		LocationID: fight.LocationID.String(),
+		Status:     string(fight.Status),
+		MaxMembers: fight.MaxMembers,
+		Bot:        BotFromDomain(fight.Bot),
+		Members:    make([]*GroupFightMember, len(fight.Members)),
+		Rounds:     make([]*GroupFightRound, len(fight.Rounds)),
+		CreatedAt:  fight.CreatedAt,
+	}
+
+	for i, member := range fight.Members {
+		result.Members[i] = &GroupFightMember{
+			UserID:   member.UserID.String(),
+			Username: member.Username,
+			Hp:       int(member.Hp),
+			Damage:   int(member.Damage),
+			Exp:      int(member.Exp),
+			Gold:     int(member.Gold),
+			Defeat:   defeatFromDomain(member.LostGold, member.LostExp, member.RespawnLocationID, member.WeakenedUntil),
+		}
+	}
+
+	for i, round := range fight.Rounds {
+		result.Rounds[i] = GroupFightRoundFromDomain(round)
+	}
+
+	if fight.DroppedItemID != nil {
+		id := fight.DroppedItemID.String()
+		result.DroppedItemID = &id
+	}
+
+	return result
+}
+
+func GroupFightsFromDomain(fights []*domain.GroupFight) []*GroupFight {
+	result := make([]*GroupFight, len(fights))
+	for i, fight := range fights {
+		result[i] = GroupFightFromDomain(fight)
+	}
+	return result
+}
@@ -0,0 +1,14 @@
+package dto
+
+// InnQuote is what a full heal at the inn costs right now.
+type InnQuote struct {
+	MissingHp int `json:"missingHp"`
+	Price     int `json:"price"`
+}
+
+type InnHealResponse struct {
+	CurrentHp int `json:"currentHp"`
+	Hp        int `json:"hp"`
+	Gold      int `json:"gold"`
+	Price     int `json:"price"`
+}
@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"time"
+
+	"moonshine/internal/domain"
+)
+
+type MovementLog struct {
+	ID        string    `json:"id"`
+	FromCell  *string   `json:"fromCell,omitempty"`
+	ToCell    string    `json:"toCell"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type MovementLogsResponse struct {
+	Movements []*MovementLog `json:"movements"`
+	Total     int            `json:"total"`
+	Limit     int            `json:"limit"`
+	Offset    int            `json:"offset"`
+}
+
+func MovementLogFromDomain(movementLog *domain.MovementLog) *MovementLog {
+	if movementLog == nil {
+		return nil
+	}
+
+	return &MovementLog{
+		ID:        movementLog.ID.String(),
+		FromCell:  movementLog.FromCell,
+		ToCell:    movementLog.ToCell,
+		CreatedAt: movementLog.CreatedAt,
+	}
+}
+
+func MovementLogsFromDomain(logs []*domain.MovementLog) []*MovementLog {
+	result := make([]*MovementLog, len(logs))
+	for i, movementLog := range logs {
+		result[i] = MovementLogFromDomain(movementLog)
+	}
+	return result
+}
@@ -20,6 +20,7 @@ type User struct {
 	Gold                  int       `json:"gold"`
 	Exp                   int       `json:"exp"`
 	FreeStats             int       `json:"freeStats"`
+	StatsResetPrice       int       `json:"statsResetPrice"`
 	CreatedAt             time.Time `json:"createdAt"`
 	Avatar                string    `json:"avatar"`
 	ChestEquipmentItemID  *string   `json:"chestEquipmentItemId,omitempty"`
@@ -60,20 +61,21 @@ func UserFromDomain(user *domain.User, location *domain.Location, bots []*domain
 	}
 
 	result := &User{
-		ID:        user.ID.String(),
-		Username:  user.Username,
-		Email:     user.Email,
-		Hp:        int(user.Hp),
-		CurrentHp: int(user.CurrentHp),
-		Attack:    int(user.Attack),
-		Defense:   int(user.Defense),
-		Level:     int(user.Level),
-		Gold:      int(user.Gold),
-		Exp:       int(user.Exp),
-		FreeStats: int(user.FreeStats),
-		CreatedAt: user.CreatedAt,
-		InFight:   inFight,
-		Avatar:    user.Avatar,
+		ID:              user.ID.String(),
+		Username:        user.Username,
+		Email:           user.Email,
+		Hp:              int(user.Hp),
+		CurrentHp:       int(user.CurrentHp),
+		Attack:          int(user.Attack),
+		Defense:         int(user.Defense),
+		Level:           int(user.Level),
+		Gold:            int(user.Gold),
+		Exp:             int(user.Exp),
+		FreeStats:       int(user.FreeStats),
+		StatsResetPrice: int(user.StatsResetPrice()),
+		CreatedAt:       user.CreatedAt,
+		InFight:         inFight,
+		Avatar:          user.Avatar,
 	}
 
 	equipmentItemFields := []struct {
@@ -120,6 +122,12 @@ type UpdateUserRequest struct {
 	AvatarID *string `json:"avatarId,omitempty"`
 }
 
+type SpendStatsRequest struct {
+	Attack  uint `json:"attack"`
+	Defense uint `json:"defense"`
+	Hp      uint `json:"hp"`
+}
+
 func AvatarFromDomain(avatar *domain.Avatar) *Avatar {
 	if avatar == nil {
 		return nil
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"moonshine/internal/api/middleware"
 	"net/http"
 
@@ -33,7 +34,7 @@ func NewBotHandler(db *sqlx.DB) *BotHandler {
 
 // GetBots godoc
 // @Summary Get bots by location
-// @Description Get list of bots in a specific location
+// @Description Get the bot instances spawned in a location with their HP, state and respawn time
 // @Tags bots
 // @Accept json
 // @Produce json
@@ -58,13 +59,13 @@ func (h *BotHandler) GetBots(c echo.Context) error {
 		return err
 	}
 
-	bots, err := h.botService.GetBotsByLocationSlug(locationSlug)
+	instances, err := h.botService.GetBotsByLocationSlug(locationSlug)
 	if err != nil {
 		return ErrInternalServerError(c)
 	}
 
 	return c.JSON(http.StatusOK, &BotResponse{
-		Bots: dto.BotsFromDomain(bots),
+		Bots: dto.BotInstancesFromDomain(instances),
 	})
 }
 
@@ -80,6 +81,7 @@ func (h *BotHandler) GetBots(c echo.Context) error {
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Router /api/bots/{slug}/attack [post]
 func (h *BotHandler) Attack(c echo.Context) error {
 	botSlug := c.Param("slug")
@@ -100,6 +102,9 @@ func (h *BotHandler) Attack(c echo.Context) error {
 		if err == repository.ErrUserNotFound {
 			return ErrNotFound(c, "user not found")
 		}
+		if err == services.ErrNoBotAvailable || err == services.ErrUserInFight || errors.Is(err, services.ErrBossOnCooldown) {
+			return ErrConflict(c, err.Error())
+		}
 		return ErrBadRequest(c, err.Error())
 	}
 
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/middleware"
+	"moonshine/internal/api/services"
+	"moonshine/internal/repository"
+)
+
+type ConsumableItemHandler struct {
+	consumableItemService *services.ConsumableItemService
+	userRepo              *repository.UserRepository
+}
+
+func NewConsumableItemHandler(db *sqlx.DB) *ConsumableItemHandler {
+	return &ConsumableItemHandler{
+		consumableItemService: services.NewConsumableItemService(db, services.NewFightService(db)),
+		userRepo:              repository.NewUserRepository(db),
+	}
+}
+
+func handleConsumableItemError(c echo.Context, err error) error {
+	switch err {
+	case services.ErrConsumableItemNotFound:
+		return ErrNotFound(c, "consumable item not found")
+	case services.ErrItemNotInInventory:
+		return ErrNotFound(c, "item not in inventory")
+	case services.ErrInsufficientGold:
+		return ErrBadRequest(c, "insufficient gold")
+	case services.ErrInsufficientLevel:
+		return ErrBadRequest(c, "insufficient level")
+	case services.ErrUserInFight:
+		return ErrBadRequest(c, "user is in fight")
+	default:
+		return handleFightError(c, err)
+	}
+}
+
+// GetConsumableItems godoc
+// @Summary Get consumable items
+// @Description Get the potions and elixirs sold in shops
+// @Tags consumables
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} dto.ConsumableItem
+// @Failure 401 {object} map[string]string
+// @Router /api/consumable_items [get]
+func (h *ConsumableItemHandler) GetConsumableItems(c echo.Context) error {
+	items, err := h.consumableItemService.GetAll(c.Request().Context())
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, dto.ConsumableItemsFromDomain(items))
+}
+
+// BuyConsumableItem godoc
+// @Summary Buy consumable item
+// @Description Purchase one consumable item into the inventory
+// @Tags consumables
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param slug path string true "Item slug"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/consumable_items/{slug}/buy [post]
+func (h *ConsumableItemHandler) BuyConsumableItem(c echo.Context) error {
+	itemSlug := c.Param("slug")
+	if itemSlug == "" {
+		return ErrBadRequest(c, "item slug is required")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	if err := h.consumableItemService.Buy(c.Request().Context(), userID, itemSlug); err != nil {
+		return handleConsumableItemError(c, err)
+	}
+
+	return SuccessResponse(c, "item purchased successfully")
+}
+
+// GetUserConsumables godoc
+// @Summary Get user consumables
+// @Description Get the consumables in the current user's inventory
+// @Tags consumables
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {array} dto.InventoryConsumable
+// @Failure 401 {object} map[string]string
+// @Router /api/users/me/consumables [get]
+func (h *ConsumableItemHandler) GetUserConsumables(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	consumables, err := h.consumableItemService.GetUserConsumables(c.Request().Context(), userID)
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, dto.InventoryConsumablesFromDomain(consumables))
+}
+
+// UseInventoryItem godoc
+// @Summary Use consumable
+// @Description Consume one item from the inventory. In a fight against a bot it costs the player's turn and the bot gets a free hit
+// @Tags consumables
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Inventory item ID"
+// @Success 200 {object} dto.UseConsumableResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/inventory/{id}/use [post]
+func (h *ConsumableItemHandler) UseInventoryItem(c echo.Context) error {
+	inventoryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid inventory item id")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	result, err := h.consumableItemService.Use(c.Request().Context(), userID, inventoryID)
+	if err != nil {
+		return handleConsumableItemError(c, err)
+	}
+
+	response := &dto.UseConsumableResponse{
+		Item:      dto.ConsumableItemFromDomain(result.Item),
+		CurrentHp: int(result.User.CurrentHp),
+		Hp:        int(result.User.Hp),
+		Buff:      dto.BuffFromDomain(result.Buff),
+	}
+	if result.Fight != nil {
+		response.Fight = dto.FightFromDomain(result.Fight.Fight)
+		if rounds := result.Fight.Fight.Rounds; len(rounds) > 0 {
+			response.CurrentHp = int(rounds[0].PlayerHp)
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/middleware"
+	"moonshine/internal/api/services"
+)
+
+type DuelHandler struct {
+	duelService *services.DuelService
+}
+
+func NewDuelHandler(db *sqlx.DB) *DuelHandler {
+	return &DuelHandler{
+		duelService: services.NewDuelService(db),
+	}
+}
+
+func handleDuelError(c echo.Context, err error) error {
+	switch err {
+	case services.ErrDuelNotFound:
+		return ErrNotFound(c, "duel not found")
+	case services.ErrUserNotFound:
+		return ErrNotFound(c, "user not found")
+	case services.ErrOpponentNotFound:
+		return ErrNotFound(c, "opponent not found")
+	case services.ErrCannotDuelSelf:
+		return ErrBadRequest(c, "cannot challenge yourself")
+	case services.ErrOpponentNotInLocation:
+		return ErrBadRequest(c, "opponent is not in your location")
+	case services.ErrAlreadyInDuel:
+		return ErrBadRequest(c, "user already has an active duel")
+	case services.ErrOpponentBusy:
+		return ErrBadRequest(c, "opponent is busy")
+	case services.ErrUserInFight:
+		return ErrBadRequest(c, "user is in fight")
+	case services.ErrDuelNotPending:
+		return ErrBadRequest(c, "duel is not pending")
+	case services.ErrAlreadyMoved:
+		return ErrBadRequest(c, "move already made in this round")
+	case services.ErrInvalidBodyPart:
+		return ErrBadRequest(c, "invalid body part")
+	default:
+		return ErrInternalServerError(c)
+	}
+}
+
+// Challenge godoc
+// @Summary Challenge a player
+// @Description Challenge another player in the same location to a duel
+// @Tags duels
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body dto.ChallengeDuelRequest true "Opponent username"
+// @Success 200 {object} dto.Duel
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/duels [post]
+func (h *DuelHandler) Challenge(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var req dto.ChallengeDuelRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+	if err := c.Validate(&req); err != nil {
+		return ErrBadRequest(c, err.Error())
+	}
+
+	duel, err := h.duelService.Challenge(c.Request().Context(), userID, req.Username)
+	if err != nil {
+		return handleDuelError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.DuelFromDomain(duel))
+}
+
+// GetCurrentDuel godoc
+// @Summary Get current duel
+// @Description Get the pending or running duel of the current user
+// @Tags duels
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} dto.Duel
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/duels/current [get]
+func (h *DuelHandler) GetCurrentDuel(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	duel, err := h.duelService.GetCurrentDuel(c.Request().Context(), userID)
+	if err != nil {
+		return handleDuelError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.DuelFromDomain(duel))
+}
+
+// Accept godoc
+// @Summary Accept a duel
+// @Description Accept a pending duel challenge and start the first round
+// @Tags duels
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Duel ID"
+// @Success 200 {object} dto.Duel
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/duels/{id}/accept [post]
+func (h *DuelHandler) Accept(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	duelID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid duel ID")
+	}
+
+	duel, err := h.duelService.Accept(c.Request().Context(), userID, duelID)
+	if err != nil {
+		return handleDuelError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.DuelFromDomain(duel))
+}
+
+// Decline godoc
+// @Summary Decline a duel
+// @Description Decline a pending duel challenge, or withdraw it as the challenger
+// @Tags duels
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Duel ID"
+// @Success 200 {object} dto.Duel
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/duels/{id}/decline [post]
+func (h *DuelHandler) Decline(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	duelID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid duel ID")
+	}
+
+	duel, err := h.duelService.Decline(c.Request().Context(), userID, duelID)
+	if err != nil {
+		return handleDuelError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.DuelFromDomain(duel))
+}
+
+// Hit godoc
+// @Summary Hit in duel
+// @Description Submit attack and defense points for the current duel round
+// @Tags duels
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body HitRequest true "Hit request"
+// @Success 200 {object} dto.Duel
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/duels/current/hit [post]
+func (h *DuelHandler) Hit(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var req HitRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+	if err := c.Validate(&req); err != nil {
+		return ErrBadRequest(c, err.Error())
+	}
+
+	duel, err := h.duelService.Hit(c.Request().Context(), userID, req.Attack, req.Defense)
+	if err != nil {
+		return handleDuelError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.DuelFromDomain(duel))
+}
@@ -39,6 +39,12 @@ func handleFightError(c echo.Context, err error) error {
 		return ErrNotFound(c, "bot not found")
 	case services.ErrInvalidBodyPart:
 		return ErrBadRequest(c, "invalid body part")
+	case services.ErrFightNotFound:
+		return ErrNotFound(c, "fight not found")
+	case services.ErrInvalidFightOutcome:
+		return ErrBadRequest(c, "invalid outcome")
+	case services.ErrRoundAlreadyResolved:
+		return ErrConflict(c, "round already resolved")
 	default:
 		return ErrInternalServerError(c)
 	}
@@ -50,6 +56,32 @@ type GetCurrentFightResponse struct {
 	Fight dto.Fight `json:"fight"`
 }
 
+// buildResponse is shared by the REST endpoints and the websocket commands.
+func (h *FightHandler) buildResponse(result *services.GetCurrentFightResult) *GetCurrentFightResponse {
+	if result == nil {
+		return nil
+	}
+
+	var location *domain.Location
+	if result.User != nil && result.User.LocationID != uuid.Nil {
+		location, _ = h.locationRepo.FindByID(result.User.LocationID)
+	}
+
+	userDTO := dto.UserFromDomain(result.User, location, nil, true)
+	botDTO := dto.BotFromDomain(result.Bot)
+	fightDTO := dto.FightFromDomain(result.Fight)
+
+	if userDTO == nil || botDTO == nil || fightDTO == nil {
+		return nil
+	}
+
+	return &GetCurrentFightResponse{
+		User:  *userDTO,
+		Bot:   *botDTO,
+		Fight: *fightDTO,
+	}
+}
+
 // GetCurrentFight godoc
 // @Summary Get current fight
 // @Description Get information about current active fight
@@ -72,30 +104,87 @@ func (h *FightHandler) GetCurrentFight(c echo.Context) error {
 		return handleFightError(c, err)
 	}
 
-	if result == nil {
+	response := h.buildResponse(result)
+	if response == nil {
 		return ErrInternalServerError(c)
 	}
 
-	var location *domain.Location
-	if result.User != nil && result.User.LocationID != uuid.Nil {
-		location, _ = h.locationRepo.FindByID(result.User.LocationID)
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetFights godoc
+// @Summary Get fight history
+// @Description Get paginated list of user's finished fights, newest first
+// @Tags fights
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Param bot query string false "Bot slug"
+// @Param outcome query string false "Fight outcome (won, lost or fled)"
+// @Success 200 {object} dto.FightsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/fights [get]
+func (h *FightHandler) GetFights(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
 	}
 
-	userDTO := dto.UserFromDomain(result.User, location, nil, true)
-	botDTO := dto.BotFromDomain(result.Bot)
-	fightDTO := dto.FightFromDomain(result.Fight)
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		return ErrBadRequest(c, "invalid pagination")
+	}
 
-	if userDTO == nil || botDTO == nil || fightDTO == nil {
-		return ErrInternalServerError(c)
+	fights, total, err := h.fightService.GetFightHistory(c.Request().Context(), userID,
+		c.QueryParam("bot"), c.QueryParam("outcome"), limit, offset)
+	if err != nil {
+		return handleFightError(c, err)
 	}
 
-	return c.JSON(http.StatusOK, &GetCurrentFightResponse{
-		User:  *userDTO,
-		Bot:   *botDTO,
-		Fight: *fightDTO,
+	return c.JSON(http.StatusOK, &dto.FightsResponse{
+		Fights: dto.FightsFromDomain(fights),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
 	})
 }
 
+// GetFight godoc
+// @Summary Get fight replay
+// @Description Get a finished fight with every round for replay
+// @Tags fights
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Fight ID"
+// @Success 200 {object} dto.Fight
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/fights/{id} [get]
+func (h *FightHandler) GetFight(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	fightID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid fight ID")
+	}
+
+	fight, err := h.fightService.GetFight(c.Request().Context(), userID, fightID)
+	if err != nil {
+		return handleFightError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.FightFromDomain(fight))
+}
+
 type HitRequest struct {
 	Attack  string `json:"attack" validate:"required"`
 	Defense string `json:"defense" validate:"required"`
@@ -133,27 +222,49 @@ func (h *FightHandler) Hit(c echo.Context) error {
 		return handleFightError(c, err)
 	}
 
-	if result == nil {
+	response := h.buildResponse(result)
+	if response == nil {
 		println("ERROR: Hit result is nil")
 		return ErrInternalServerError(c)
 	}
 
-	var location *domain.Location
-	if result.User != nil && result.User.LocationID != uuid.Nil {
-		location, _ = h.locationRepo.FindByID(result.User.LocationID)
+	return c.JSON(http.StatusOK, response)
+}
+
+type FleeResponse struct {
+	GetCurrentFightResponse
+	Fled bool `json:"fled"`
+}
+
+// Flee godoc
+// @Summary Flee from fight
+// @Description Try to escape the current fight. The chance depends on the level difference; on failure the bot gets a free hit
+// @Tags fights
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} FleeResponse
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/fights/current/flee [post]
+func (h *FightHandler) Flee(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
 	}
 
-	userDTO := dto.UserFromDomain(result.User, location, nil, true)
-	botDTO := dto.BotFromDomain(result.Bot)
-	fightDTO := dto.FightFromDomain(result.Fight)
+	result, err := h.fightService.Flee(c.Request().Context(), userID)
+	if err != nil {
+		return handleFightError(c, err)
+	}
 
-	if userDTO == nil || botDTO == nil || fightDTO == nil {
+	response := h.buildResponse(result.GetCurrentFightResult)
+	if response == nil {
 		return ErrInternalServerError(c)
 	}
 
-	return c.JSON(http.StatusOK, &GetCurrentFightResponse{
-		User:  *userDTO,
-		Bot:   *botDTO,
-		Fight: *fightDTO,
+	return c.JSON(http.StatusOK, &FleeResponse{
+		GetCurrentFightResponse: *response,
+		Fled:                    result.Fled,
 	})
 }
@@ -333,3 +333,123 @@ func TestFightHandler_Hit(t *testing.T) {
 		assert.Contains(t, response["error"], "no active fight")
 	})
 }
+
+func finishFight(t *testing.T, db *sqlx.DB, fight *domain.Fight, playerHp, botHp uint) {
+	rounds, err := repository.NewRoundRepository(db).FindByFightID(fight.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, rounds)
+
+	err = repository.NewRoundRepository(db).FinishRound(rounds[0].ID, "HEAD", "LEGS", "CHEST", "HEAD",
+		rounds[0].BotHp-botHp, rounds[0].PlayerHp-playerHp, playerHp, botHp)
+	require.NoError(t, err)
+
+	_, err = repository.NewFightRepository(db).Finish(fight.ID, 0, 0, nil)
+	require.NoError(t, err)
+}
+
+func TestFightHandler_GetFights(t *testing.T) {
+	handler, db, user := setupFightHandlerTest(t)
+
+	wonBot, wonFight, err := setupFightWithBot(t, db, user)
+	require.NoError(t, err)
+	finishFight(t, db, wonFight, 50, 0)
+
+	_, lostFight, err := setupFightWithBot(t, db, user)
+	require.NoError(t, err)
+	finishFight(t, db, lostFight, 0, 10)
+
+	_, _, err = setupFightWithBot(t, db, user)
+	require.NoError(t, err)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/fights?"+query, nil)
+		rec := httptest.NewRecorder()
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, user.ID))
+		c := echo.New().NewContext(req, rec)
+
+		require.NoError(t, handler.GetFights(c))
+		return rec
+	}
+
+	t.Run("lists finished fights only", func(t *testing.T) {
+		rec := get("")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response dto.FightsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, 2, response.Total)
+		require.Len(t, response.Fights, 2)
+		assert.Equal(t, lostFight.ID.String(), response.Fights[0].ID)
+		assert.Equal(t, string(domain.FightOutcomeLost), response.Fights[0].Outcome)
+		assert.Equal(t, string(domain.FightOutcomeWon), response.Fights[1].Outcome)
+	})
+
+	t.Run("filters by outcome and bot", func(t *testing.T) {
+		var response dto.FightsResponse
+		rec := get("outcome=won")
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.Fights, 1)
+		assert.Equal(t, wonFight.ID.String(), response.Fights[0].ID)
+
+		rec = get("bot=" + wonBot.Slug)
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		require.Len(t, response.Fights, 1)
+		require.NotNil(t, response.Fights[0].Bot)
+		assert.Equal(t, wonBot.Name, response.Fights[0].Bot.Name)
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get("outcome=draw").Code)
+		assert.Equal(t, http.StatusNotFound, get("bot=unknown-bot").Code)
+		assert.Equal(t, http.StatusBadRequest, get("limit=0").Code)
+	})
+}
+
+func TestFightHandler_GetFight(t *testing.T) {
+	handler, db, user := setupFightHandlerTest(t)
+
+	get := func(userID uuid.UUID, id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/fights/"+id, nil)
+		rec := httptest.NewRecorder()
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, userID))
+		c := echo.New().NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+
+		require.NoError(t, handler.GetFight(c))
+		return rec
+	}
+
+	t.Run("returns every round for replay", func(t *testing.T) {
+		bot, fight, err := setupFightWithBot(t, db, user)
+		require.NoError(t, err)
+		finishFight(t, db, fight, 40, 0)
+
+		rec := get(user.ID, fight.ID.String())
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response dto.Fight
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, string(domain.FightOutcomeWon), response.Outcome)
+		require.NotNil(t, response.Bot)
+		assert.Equal(t, bot.ID.String(), response.Bot.ID)
+		require.Len(t, response.Rounds, 1)
+		assert.Equal(t, 40, response.Rounds[0].PlayerHp)
+		assert.Equal(t, 0, response.Rounds[0].BotHp)
+		require.NotNil(t, response.Rounds[0].PlayerAttackPoint)
+		assert.Equal(t, "CHEST", *response.Rounds[0].PlayerAttackPoint)
+	})
+
+	t.Run("fight in progress or of another user returns 404", func(t *testing.T) {
+		_, fight, err := setupFightWithBot(t, db, user)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, get(user.ID, fight.ID.String()).Code)
+
+		finishFight(t, db, fight, 0, 10)
+		assert.Equal(t, http.StatusNotFound, get(uuid.New(), fight.ID.String()).Code)
+	})
+
+	t.Run("invalid id returns 400", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get(user.ID, "not-a-uuid").Code)
+	})
+}
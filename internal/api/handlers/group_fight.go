@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/middleware"
+	"moonshine/internal/api/services"
+)
+
+type GroupFightHandler struct {
+	groupFightService *services.GroupFightService
+}
+
+func NewGroupFightHandler(db *sqlx.DB) *GroupFightHandler {
+	return &GroupFightHandler{
+		groupFightService: services.NewGroupFightService(db),
+	}
+}
+
+func handleGroupFightError(c echo.Context, err error) error {
+	if errors.Is(err, services.ErrBossOnCooldown) {
+		return ErrConflict(c, err.Error())
+	}
+
+	switch err {
+	case services.ErrGroupFightNotFound:
+		return ErrNotFound(c, "group fight not found")
+	case services.ErrUserNotFound:
+		return ErrNotFound(c, "user not found")
+	case services.ErrBotNotFound:
+		return ErrNotFound(c, "bot not found")
+	case services.ErrBotNotInLocation:
+		return ErrBadRequest(c, "bot is not in the same location as user")
+	case services.ErrGroupFightElsewhere:
+		return ErrBadRequest(c, "group fight is in another location")
+	case services.ErrUserInFight:
+		return ErrBadRequest(c, "user is in fight")
+	case services.ErrOutOfGroupFight:
+		return ErrBadRequest(c, "you are out of the fight")
+	case services.ErrAlreadyMoved:
+		return ErrBadRequest(c, "move already made in this round")
+	case services.ErrInvalidBodyPart:
+		return ErrBadRequest(c, "invalid body part")
+	case services.ErrGroupFightFull:
+		return ErrConflict(c, "group fight is full")
+	case services.ErrNoBotAvailable:
+		return ErrConflict(c, services.ErrNoBotAvailable.Error())
+	default:
+		return ErrInternalServerError(c)
+	}
+}
+
+// Start godoc
+// @Summary Start a group fight
+// @Description Start a fight against a bot in the current location that other players here can join
+// @Tags group_fights
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body dto.StartGroupFightRequest true "Bot slug"
+// @Success 200 {object} dto.GroupFight
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/group_fights [post]
+func (h *GroupFightHandler) Start(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var req dto.StartGroupFightRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+	if err := c.Validate(&req); err != nil {
+		return ErrBadRequest(c, err.Error())
+	}
+
+	fight, err := h.groupFightService.Start(c.Request().Context(), userID, req.BotSlug)
+	if err != nil {
+		return handleGroupFightError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.GroupFightFromDomain(fight))
+}
+
+// GetJoinable godoc
+// @Summary List joinable group fights
+// @Description List running group fights in the current location that have a free place
+// @Tags group_fights
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} dto.GroupFightsResponse
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/group_fights [get]
+func (h *GroupFightHandler) GetJoinable(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	fights, err := h.groupFightService.GetJoinable(c.Request().Context(), userID)
+	if err != nil {
+		return handleGroupFightError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, &dto.GroupFightsResponse{
+		GroupFights: dto.GroupFightsFromDomain(fights),
+	})
+}
+
+// GetCurrent godoc
+// @Summary Get current group fight
+// @Description Get the running group fight of the current user
+// @Tags group_fights
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} dto.GroupFight
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/group_fights/current [get]
+func (h *GroupFightHandler) GetCurrent(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	fight, err := h.groupFightService.GetCurrent(c.Request().Context(), userID)
+	if err != nil {
+		return handleGroupFightError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.GroupFightFromDomain(fight))
+}
+
+// Join godoc
+// @Summary Join a group fight
+// @Description Join a running group fight in the current location
+// @Tags group_fights
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Group fight ID"
+// @Success 200 {object} dto.GroupFight
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /api/group_fights/{id}/join [post]
+func (h *GroupFightHandler) Join(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	fightID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return ErrBadRequest(c, "invalid group fight ID")
+	}
+
+	fight, err := h.groupFightService.Join(c.Request().Context(), userID, fightID)
+	if err != nil {
+		return handleGroupFightError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.GroupFightFromDomain(fight))
+}
+
+// Hit godoc
+// @Summary Hit in group fight
+// @Description Submit attack and defense points for the current group fight round
+// @Tags group_fights
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body HitRequest true "Hit request"
+// @Success 200 {object} dto.GroupFight
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/group_fights/current/hit [post]
+func (h *GroupFightHandler) Hit(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	var req HitRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+	if err := c.Validate(&req); err != nil {
+		return ErrBadRequest(c, err.Error())
+	}
+
+	fight, err := h.groupFightService.Hit(c.Request().Context(), userID, req.Attack, req.Defense)
+	if err != nil {
+		return handleGroupFightError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.GroupFightFromDomain(fight))
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/middleware"
+	"moonshine/internal/api/services"
+)
+
+type InnHandler struct {
+	innService *services.InnService
+}
+
+func NewInnHandler(db *sqlx.DB) *InnHandler {
+	return &InnHandler{
+		innService: services.NewInnService(db),
+	}
+}
+
+// GetQuote godoc
+// @Summary Get inn heal price
+// @Description Get how much HP the current user is missing and what a full heal at the inn costs
+// @Tags inn
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} dto.InnQuote
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/inn [get]
+func (h *InnHandler) GetQuote(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	quote, err := h.innService.Quote(c.Request().Context(), userID)
+	if err != nil {
+		if err == services.ErrUserNotFound {
+			return ErrNotFound(c, "user not found")
+		}
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, &dto.InnQuote{
+		MissingHp: int(quote.MissingHp),
+		Price:     int(quote.Price),
+	})
+}
+
+// Heal godoc
+// @Summary Heal at the inn
+// @Description Restore the current user to full HP for gold. The price grows with missing HP and level; the user must be in the inn
+// @Tags inn
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} dto.InnHealResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/inn/heal [post]
+func (h *InnHandler) Heal(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	user, quote, err := h.innService.Heal(c.Request().Context(), userID)
+	if err != nil {
+		switch err {
+		case services.ErrUserNotFound:
+			return ErrNotFound(c, "user not found")
+		case services.ErrNotInInn:
+			return ErrBadRequest(c, "user is not in the inn")
+		case services.ErrUserInFight:
+			return ErrBadRequest(c, "user is in fight")
+		case services.ErrNothingToHeal:
+			return ErrBadRequest(c, "nothing to heal")
+		case services.ErrInsufficientGold:
+			return ErrBadRequest(c, "insufficient gold")
+		default:
+			return ErrInternalServerError(c)
+		}
+	}
+
+	return c.JSON(http.StatusOK, &dto.InnHealResponse{
+		CurrentHp: int(user.CurrentHp),
+		Hp:        int(user.Hp),
+		Gold:      int(user.Gold),
+		Price:     int(quote.Price),
+	})
+}
@@ -13,7 +13,6 @@ import (
 	"moonshine/internal/api/middleware"
 	"moonshine/internal/api/services"
 	"moonshine/internal/repository"
-	"moonshine/internal/worker"
 )
 
 type LocationHandler struct {
@@ -28,10 +27,21 @@ type LocationCellsResponse struct {
 }
 
 type MoveToCellResponse struct {
-	Message      string `json:"message"`
-	PathLength   int    `json:"path_length"`
-	TargetCell   string `json:"target_cell"`
-	TimePerCell  int    `json:"time_per_cell"`
+	Message     string    `json:"message"`
+	PathLength  int       `json:"path_length"`
+	TargetCell  string    `json:"target_cell"`
+	TimePerCell int       `json:"time_per_cell"`
+	ArrivesAt   time.Time `json:"arrives_at"`
+}
+
+type MovementResponse struct {
+	CurrentCell   string    `json:"current_cell"`
+	TargetCell    string    `json:"target_cell"`
+	RemainingPath []string  `json:"remaining_path"`
+	NextStepAt    time.Time `json:"next_step_at"`
+	NextStepIn    int       `json:"next_step_in"`
+	ArrivesAt     time.Time `json:"arrives_at"`
+	EtaSeconds    int       `json:"eta_seconds"`
 }
 
 type locationCell struct {
@@ -43,10 +53,9 @@ type locationCell struct {
 }
 
 
-func NewLocationHandler(db *sqlx.DB) *LocationHandler {
+func NewLocationHandler(db *sqlx.DB, movingWorker services.MovingWorker) *LocationHandler {
 	locationRepo := repository.NewLocationRepository(db)
 	userRepo := repository.NewUserRepository(db)
-	movingWorker := worker.NewCellsMovingWorker(locationRepo, userRepo, 5*time.Second)
 	locationService, err := services.NewLocationService(db, locationRepo, userRepo, movingWorker)
 	if err != nil {
 		log.Fatalf("Failed to create LocationService: %v", err)
@@ -160,7 +169,8 @@ func (h *LocationHandler) MoveToCell(c echo.Context) error {
 		}
 	}
 
-	if err := h.locationService.StartCellMovement(userID, path); err != nil {
+	movement, err := h.locationService.StartCellMovement(userID, path)
+	if err != nil {
 		return ErrBadRequest(c, "")
 	}
 
@@ -176,10 +186,89 @@ func (h *LocationHandler) MoveToCell(c echo.Context) error {
 		Message:     "movement started",
 		PathLength:  len(path),
 		TargetCell:  targetName,
-		TimePerCell: 5,
+		TimePerCell: movement.StepSeconds,
+		ArrivesAt:   movement.ArrivesAt(),
 	})
 }
 
+// GetMovement godoc
+// @Summary Get current movement
+// @Description Get the cell walk in progress: current cell, remaining path and timings
+// @Tags locations
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} MovementResponse
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/locations/movement [get]
+func (h *LocationHandler) GetMovement(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	movement, err := h.locationService.GetMovement(userID)
+	if err != nil {
+		if errors.Is(err, services.ErrNoActiveMovement) {
+			return ErrNotFound(c, "no active movement")
+		}
+		return ErrInternalServerError(c)
+	}
+
+	var currentCell string
+	if user, err := h.userRepo.FindByID(userID); err == nil {
+		if location, err := h.locationRepo.FindByID(user.LocationID); err == nil {
+			currentCell = location.Slug
+		}
+	}
+
+	return c.JSON(http.StatusOK, &MovementResponse{
+		CurrentCell:   currentCell,
+		TargetCell:    movement.Path[len(movement.Path)-1],
+		RemainingPath: movement.RemainingPath(),
+		NextStepAt:    movement.NextStepAt,
+		NextStepIn:    secondsUntil(movement.NextStepAt),
+		ArrivesAt:     movement.ArrivesAt(),
+		EtaSeconds:    secondsUntil(movement.ArrivesAt()),
+	})
+}
+
+// CancelMovement godoc
+// @Summary Cancel movement
+// @Description Stop the cell walk in progress. The user stays in the current cell
+// @Tags locations
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/locations/movement [delete]
+func (h *LocationHandler) CancelMovement(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := h.locationService.CancelMovement(userID); err != nil {
+		if errors.Is(err, services.ErrNoActiveMovement) {
+			return ErrNotFound(c, "no active movement")
+		}
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, nil)
+}
+
+func secondsUntil(t time.Time) int {
+	d := time.Until(t)
+	if d < 0 {
+		return 0
+	}
+	return int(d.Round(time.Second) / time.Second)
+}
+
 // GetLocationCells godoc
 // @Summary Get location cells
 // @Description Get list of cells in a location
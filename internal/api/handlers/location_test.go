@@ -17,6 +17,7 @@ import (
 	"moonshine/internal/api/middleware"
 	"moonshine/internal/domain"
 	"moonshine/internal/repository"
+	"moonshine/internal/worker"
 )
 
 func setupLocationHandlerTest(t *testing.T) (*LocationHandler, *sqlx.DB, *domain.User, *domain.Location, echo.Echo) {
@@ -24,14 +25,15 @@ func setupLocationHandlerTest(t *testing.T) (*LocationHandler, *sqlx.DB, *domain
 		t.Skip("Test database not initialized")
 	}
 	db := testDB.DB()
-	handler := NewLocationHandler(db)
+	locRepo := repository.NewLocationRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	handler := NewLocationHandler(db, worker.NewCellsMovingWorker(db, locRepo, userRepo, 5*time.Second))
 	loc := &domain.Location{
 		Name:     fmt.Sprintf("Loc %d", time.Now().UnixNano()),
 		Slug:     fmt.Sprintf("loc-%d", time.Now().UnixNano()),
 		Cell:     false,
 		Inactive: false,
 	}
-	locRepo := repository.NewLocationRepository(db)
 	require.NoError(t, locRepo.Create(loc))
 	user := &domain.User{
 		Username:   fmt.Sprintf("u%d", time.Now().UnixNano()),
@@ -41,7 +43,6 @@ func setupLocationHandlerTest(t *testing.T) (*LocationHandler, *sqlx.DB, *domain
 		LocationID: loc.ID,
 		Attack:     1, Defense: 1, Hp: 20, CurrentHp: 20, Level: 1, Gold: 0, Exp: 0, FreeStats: 0,
 	}
-	userRepo := repository.NewUserRepository(db)
 	require.NoError(t, userRepo.Create(user))
 	e := echo.New()
 	return handler, db, user, loc, *e
@@ -204,3 +205,72 @@ func TestLocationHandler_MoveToCell(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, rec.Code)
 	})
 }
+
+func TestLocationHandler_GetMovement(t *testing.T) {
+	handler, db, user, loc, e := setupLocationHandlerTest(t)
+
+	t.Run("no active movement returns 404", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/locations/movement", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, user.ID))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetMovement(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("returns eta of the walk", func(t *testing.T) {
+		movement := &domain.Movement{UserID: user.ID, Path: []string{"1cell", "2cell", "3cell"}, StepSeconds: 5}
+		require.NoError(t, repository.NewMovementRepository(db).Upsert(movement))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/locations/movement", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, user.ID))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetMovement(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp MovementResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "3cell", resp.TargetCell)
+		assert.Equal(t, []string{"1cell", "2cell", "3cell"}, resp.RemainingPath)
+		assert.Equal(t, loc.Slug, resp.CurrentCell)
+		assert.Equal(t, 10*time.Second, resp.ArrivesAt.Sub(resp.NextStepAt))
+	})
+}
+
+func TestLocationHandler_CancelMovement(t *testing.T) {
+	handler, db, user, _, e := setupLocationHandlerTest(t)
+
+	t.Run("no active movement returns 404", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api/locations/movement", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, user.ID))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.CancelMovement(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("cancels the walk", func(t *testing.T) {
+		movementRepo := repository.NewMovementRepository(db)
+		movement := &domain.Movement{UserID: user.ID, Path: []string{"1cell", "2cell"}, StepSeconds: 5}
+		require.NoError(t, movementRepo.Upsert(movement))
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/locations/movement", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, user.ID))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.CancelMovement(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		_, err = movementRepo.FindByUserID(user.ID)
+		assert.Equal(t, repository.ErrMovementNotFound, err)
+	})
+}
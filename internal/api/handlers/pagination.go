@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+var errInvalidPagination = errors.New("invalid pagination")
+
+func parsePagination(c echo.Context) (int, int, error) {
+	limit := defaultPageLimit
+	offset := 0
+
+	if v := c.QueryParam("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return 0, 0, errInvalidPagination
+		}
+		limit = min(n, maxPageLimit)
+	}
+
+	if v := c.QueryParam("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, 0, errInvalidPagination
+		}
+		offset = n
+	}
+
+	return limit, offset, nil
+}
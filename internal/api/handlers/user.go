@@ -16,7 +16,9 @@ import (
 type UserHandler struct {
 	db               *sqlx.DB
 	userService      *services.UserService
+	userStatsService *services.UserStatsService
 	inventoryService *services.InventoryService
+	movementService  *services.MovementLogService
 	userRepo         *repository.UserRepository
 }
 
@@ -25,14 +27,20 @@ func NewUserHandler(db *sqlx.DB) *UserHandler {
 	avatarRepo := repository.NewAvatarRepository(db)
 	locationRepo := repository.NewLocationRepository(db)
 	userService := services.NewUserService(userRepo, avatarRepo, locationRepo)
+	userStatsService := services.NewUserStatsService(db, userRepo)
 
 	inventoryRepo := repository.NewInventoryRepository(db)
 	inventoryService := services.NewInventoryService(inventoryRepo)
 
+	movementLogRepo := repository.NewMovementLogRepository(db)
+	movementService := services.NewMovementLogService(movementLogRepo)
+
 	return &UserHandler{
 		db:               db,
 		userService:      userService,
+		userStatsService: userStatsService,
 		inventoryService: inventoryService,
+		movementService:  movementService,
 		userRepo:         userRepo,
 	}
 }
@@ -89,6 +97,43 @@ func (h *UserHandler) GetUserInventory(c echo.Context) error {
 	return c.JSON(http.StatusOK, dto.EquipmentItemsFromDomain(items))
 }
 
+// GetUserMovements godoc
+// @Summary Get travel history
+// @Description Get paginated list of user's cell movements, newest first
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} dto.MovementLogsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/users/me/movements [get]
+func (h *UserHandler) GetUserMovements(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	limit, offset, err := parsePagination(c)
+	if err != nil {
+		return ErrBadRequest(c, "invalid pagination")
+	}
+
+	logs, total, err := h.movementService.GetUserMovements(c.Request().Context(), userID, limit, offset)
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, &dto.MovementLogsResponse{
+		Movements: dto.MovementLogsFromDomain(logs),
+		Total:     total,
+		Limit:     limit,
+		Offset:    offset,
+	})
+}
+
 // GetUserEquippedItems godoc
 // @Summary Get equipped items
 // @Description Get list of currently equipped items
@@ -216,3 +261,100 @@ func (h *UserHandler) UpdateCurrentUser(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, dto.UserFromDomain(user, location, nil, inFight))
 }
+
+// SpendStats godoc
+// @Summary Spend free stats
+// @Description Spend free stat points on attack, defense or hp
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body dto.SpendStatsRequest true "Spend stats request"
+// @Success 200 {object} dto.User
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/user/me/stats [post]
+func (h *UserHandler) SpendStats(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	var req dto.SpendStatsRequest
+	if err := c.Bind(&req); err != nil {
+		return ErrBadRequest(c, "invalid request")
+	}
+
+	_, err = h.userStatsService.SpendStats(c.Request().Context(), userID, services.SpendStatsInput{
+		Attack:  req.Attack,
+		Defense: req.Defense,
+		Hp:      req.Hp,
+	})
+	if err != nil {
+		return handleUserStatsError(c, err)
+	}
+
+	user, location, inFight, err := h.userService.GetCurrentUserWithRelations(c.Request().Context(), userID)
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, dto.UserFromDomain(user, location, nil, inFight))
+}
+
+// ResetStats godoc
+// @Summary Reset spent stats
+// @Description Return all spent stat points to free stats for gold
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} dto.User
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/user/me/stats/reset [post]
+func (h *UserHandler) ResetStats(c echo.Context) error {
+	userID, err := middleware.GetUserIDFromContext(c.Request().Context())
+	if err != nil {
+		return ErrUnauthorized(c)
+	}
+
+	if err := checkNotInFight(c, h.userRepo, userID); err != nil {
+		return err
+	}
+
+	_, err = h.userStatsService.ResetStats(c.Request().Context(), userID)
+	if err != nil {
+		return handleUserStatsError(c, err)
+	}
+
+	user, location, inFight, err := h.userService.GetCurrentUserWithRelations(c.Request().Context(), userID)
+	if err != nil {
+		return ErrInternalServerError(c)
+	}
+
+	return c.JSON(http.StatusOK, dto.UserFromDomain(user, location, nil, inFight))
+}
+
+func handleUserStatsError(c echo.Context, err error) error {
+	switch err {
+	case repository.ErrUserNotFound:
+		return ErrNotFound(c, "user not found")
+	case services.ErrNoStatsToSpend:
+		return ErrBadRequest(c, "no stats to spend")
+	case services.ErrNotEnoughFreeStats:
+		return ErrBadRequest(c, "not enough free stats")
+	case services.ErrNothingToReset:
+		return ErrBadRequest(c, "nothing to reset")
+	case services.ErrInsufficientGold:
+		return ErrBadRequest(c, "insufficient gold")
+	default:
+		return ErrInternalServerError(c)
+	}
+}
@@ -224,3 +224,154 @@ func TestUserHandler_UpdateCurrentUser(t *testing.T) {
 		assert.Equal(t, http.StatusOK, rec.Code)
 	})
 }
+
+func TestUserHandler_SpendStats(t *testing.T) {
+	handler, db, user, e := setupUserHandlerTest(t)
+
+	_, err := db.Exec(`UPDATE users SET free_stats = 5 WHERE id = $1`, user.ID)
+	require.NoError(t, err)
+
+	t.Run("unauthorized when no userID", func(t *testing.T) {
+		b, _ := json.Marshal(dto.SpendStatsRequest{Attack: 1})
+		req := httptest.NewRequest(http.MethodPost, "/api/user/me/stats", bytes.NewBuffer(b))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.SpendStats(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("empty request returns 400", func(t *testing.T) {
+		b, _ := json.Marshal(dto.SpendStatsRequest{})
+		req := httptest.NewRequest(http.MethodPost, "/api/user/me/stats", bytes.NewBuffer(b))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(ctxWithUserID(user.ID))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.SpendStats(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("more than free stats returns 400", func(t *testing.T) {
+		b, _ := json.Marshal(dto.SpendStatsRequest{Attack: 3, Defense: 3})
+		req := httptest.NewRequest(http.MethodPost, "/api/user/me/stats", bytes.NewBuffer(b))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(ctxWithUserID(user.ID))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.SpendStats(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("success spends points", func(t *testing.T) {
+		b, _ := json.Marshal(dto.SpendStatsRequest{Attack: 2, Defense: 1, Hp: 1})
+		req := httptest.NewRequest(http.MethodPost, "/api/user/me/stats", bytes.NewBuffer(b))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(ctxWithUserID(user.ID))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.SpendStats(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var u dto.User
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &u))
+		assert.Equal(t, 1, u.FreeStats)
+		assert.Equal(t, int(user.Attack)+2, u.Attack)
+		assert.Equal(t, int(user.Defense)+1, u.Defense)
+		assert.Equal(t, int(user.Hp+domain.HpPerStatPoint), u.Hp)
+	})
+}
+
+func TestUserHandler_ResetStats(t *testing.T) {
+	handler, db, user, e := setupUserHandlerTest(t)
+
+	t.Run("nothing to reset returns 400", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/user/me/stats/reset", nil)
+		req = req.WithContext(ctxWithUserID(user.ID))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.ResetStats(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("success returns points and charges gold", func(t *testing.T) {
+		_, err := db.Exec(`UPDATE users SET free_stats = 3 WHERE id = $1`, user.ID)
+		require.NoError(t, err)
+		userRepo := repository.NewUserRepository(db)
+		require.NoError(t, userRepo.SpendStatsWithExt(db, user.ID, 1, 1, 1))
+
+		req := httptest.NewRequest(http.MethodPost, "/api/user/me/stats/reset", nil)
+		req = req.WithContext(ctxWithUserID(user.ID))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err = handler.ResetStats(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var u dto.User
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &u))
+		assert.Equal(t, 3, u.FreeStats)
+		assert.Equal(t, int(user.Attack), u.Attack)
+		assert.Equal(t, int(user.Defense), u.Defense)
+		assert.Equal(t, int(user.Hp), u.Hp)
+		assert.Equal(t, int(user.Gold-user.StatsResetPrice()), u.Gold)
+	})
+}
+
+func TestUserHandler_GetUserMovements(t *testing.T) {
+	handler, db, user, e := setupUserHandlerTest(t)
+
+	movementLogRepo := repository.NewMovementLogRepository(db)
+	for _, cell := range []string{"1cell", "2cell", "3cell"} {
+		require.NoError(t, movementLogRepo.Create(&domain.MovementLog{UserID: user.ID, ToCell: cell}))
+	}
+
+	t.Run("unauthorized when no userID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/users/me/movements", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetUserMovements(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("invalid limit returns 400", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/users/me/movements?limit=abc", nil)
+		req = req.WithContext(ctxWithUserID(user.ID))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetUserMovements(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("success returns paginated movements", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/users/me/movements?limit=2", nil)
+		req = req.WithContext(ctxWithUserID(user.ID))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		err := handler.GetUserMovements(c)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var resp dto.MovementLogsResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, 3, resp.Total)
+		assert.Equal(t, 2, resp.Limit)
+		assert.Len(t, resp.Movements, 2)
+	})
+}
@@ -3,11 +3,11 @@ package handlers
 import (
 	"fmt"
 	"net/http"
-	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
 
 	"moonshine/internal/api/ws"
@@ -24,12 +24,17 @@ var upgrader = websocket.Upgrader{
 
 type WebSocketHandler struct {
 	hub    *ws.Hub
+	router *ws.Router
 	config *config.Config
 }
 
-func NewWebSocketHandler(cfg *config.Config) *WebSocketHandler {
+func NewWebSocketHandler(cfg *config.Config, db *sqlx.DB) *WebSocketHandler {
+	router := ws.NewRouter()
+	registerFightCommands(router, NewFightHandler(db))
+
 	return &WebSocketHandler{
 		hub:    ws.GetHub(),
+		router: router,
 		config: cfg,
 	}
 }
@@ -54,53 +59,13 @@ func (h *WebSocketHandler) HandleConnection(c echo.Context) error {
 	}
 
 	fmt.Printf("[WS] Connection upgraded for user %s\n", userID)
-	h.hub.Register(userID, conn)
+	client := h.hub.Register(userID, conn)
 
-	go h.handleConnection(userID, conn)
+	go client.Run(h.router)
 
 	return nil
 }
 
-func (h *WebSocketHandler) handleConnection(userID uuid.UUID, conn *websocket.Conn) {
-	defer func() {
-		h.hub.Unregister(userID)
-		conn.Close()
-	}()
-
-	conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-	conn.SetPongHandler(func(string) error {
-		conn.SetReadDeadline(time.Now().Add(60 * time.Second))
-		return nil
-	})
-
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	done := make(chan struct{})
-
-	go func() {
-		defer close(done)
-		for {
-			_, _, err := conn.ReadMessage()
-			if err != nil {
-				return
-			}
-		}
-	}()
-
-	for {
-		select {
-		case <-done:
-			return
-		case <-ticker.C:
-			conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
-				return
-			}
-		}
-	}
-}
-
 func (h *WebSocketHandler) validateToken(tokenString string) (uuid.UUID, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/api/services"
+	"moonshine/internal/api/ws"
+)
+
+const (
+	CommandFightHit        = "fight.hit"
+	CommandFightGetCurrent = "fight.get_current"
+	CommandFightFlee       = "fight.flee"
+	CommandFightUseItem    = "fight.use_item"
+)
+
+// registerFightCommands exposes the fight endpoints over the socket. Replies
+// carry the same payload as GET /api/fights/current.
+func registerFightCommands(router *ws.Router, h *FightHandler) {
+	router.Handle(CommandFightGetCurrent, func(ctx context.Context, userID uuid.UUID, _ json.RawMessage) (interface{}, error) {
+		result, err := h.fightService.GetCurrentFight(ctx, userID)
+		if err != nil {
+			return nil, fightCommandError(err)
+		}
+		return fightCommandResponse(h, result)
+	})
+
+	router.Handle(CommandFightHit, func(ctx context.Context, userID uuid.UUID, data json.RawMessage) (interface{}, error) {
+		var req HitRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, ws.NewCommandError(ws.ErrorCodeBadRequest, "invalid request")
+		}
+		if req.Attack == "" || req.Defense == "" {
+			return nil, ws.NewCommandError(ws.ErrorCodeBadRequest, "attack and defense are required")
+		}
+
+		result, err := h.fightService.Hit(ctx, userID, req.Attack, req.Defense)
+		if err != nil {
+			return nil, fightCommandError(err)
+		}
+		return fightCommandResponse(h, result)
+	})
+
+	router.Handle(CommandFightFlee, func(ctx context.Context, userID uuid.UUID, _ json.RawMessage) (interface{}, error) {
+		result, err := h.fightService.Flee(ctx, userID)
+		if err != nil {
+			return nil, fightCommandError(err)
+		}
+
+		response := h.buildResponse(result.GetCurrentFightResult)
+		if response == nil {
+			return nil, ws.NewCommandError(ws.ErrorCodeInternal, "internal server error")
+		}
+		return &FleeResponse{GetCurrentFightResponse: *response, Fled: result.Fled}, nil
+	})
+
+	router.Handle(CommandFightUseItem, func(ctx context.Context, userID uuid.UUID, data json.RawMessage) (interface{}, error) {
+		var req UseItemRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, ws.NewCommandError(ws.ErrorCodeBadRequest, "invalid request")
+		}
+		inventoryID, err := uuid.Parse(req.InventoryID)
+		if err != nil {
+			return nil, ws.NewCommandError(ws.ErrorCodeBadRequest, "invalid inventory item id")
+		}
+
+		result, err := h.fightService.UseItem(ctx, userID, inventoryID)
+		if err != nil {
+			return nil, fightCommandError(err)
+		}
+		return fightCommandResponse(h, result.GetCurrentFightResult)
+	})
+}
+
+type UseItemRequest struct {
+	InventoryID string `json:"inventoryId"`
+}
+
+func fightCommandResponse(h *FightHandler, result *services.GetCurrentFightResult) (interface{}, error) {
+	response := h.buildResponse(result)
+	if response == nil {
+		return nil, ws.NewCommandError(ws.ErrorCodeInternal, "internal server error")
+	}
+	return response, nil
+}
+
+func fightCommandError(err error) error {
+	switch err {
+	case services.ErrNoActiveFight:
+		return ws.NewCommandError(ws.ErrorCodeNotFound, "no active fight")
+	case services.ErrUserNotFound:
+		return ws.NewCommandError(ws.ErrorCodeNotFound, "user not found")
+	case services.ErrBotNotFound:
+		return ws.NewCommandError(ws.ErrorCodeNotFound, "bot not found")
+	case services.ErrInvalidBodyPart:
+		return ws.NewCommandError(ws.ErrorCodeBadRequest, "invalid body part")
+	case services.ErrRoundAlreadyResolved:
+		return ws.NewCommandError(ws.ErrorCodeBadRequest, "round already resolved")
+	case services.ErrItemNotInInventory:
+		return ws.NewCommandError(ws.ErrorCodeNotFound, "item not in inventory")
+	case services.ErrConsumableItemNotFound:
+		return ws.NewCommandError(ws.ErrorCodeNotFound, "consumable item not found")
+	case services.ErrInsufficientLevel:
+		return ws.NewCommandError(ws.ErrorCodeBadRequest, "insufficient level")
+	default:
+		return err
+	}
+}
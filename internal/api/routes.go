@@ -12,6 +12,7 @@ import (
 
 	"moonshine/internal/api/handlers"
 	jwtMiddleware "moonshine/internal/api/middleware"
+	"moonshine/internal/api/services"
 	"moonshine/internal/config"
 )
 
@@ -22,10 +23,10 @@ import (
 // @Produce json
 // @Success 200 {string} string "ok"
 // @Router /health [get]
-func SetupRoutes(e *echo.Echo, db *sqlx.DB, cfg *config.Config) {
+func SetupRoutes(e *echo.Echo, db *sqlx.DB, cfg *config.Config, movingWorker services.MovingWorker) {
 	e.GET("/health", healthCheck)
 
-	wsHandler := handlers.NewWebSocketHandler(cfg)
+	wsHandler := handlers.NewWebSocketHandler(cfg, db)
 	e.GET("/api/ws", wsHandler.HandleConnection)
 
 	if !cfg.IsProduction() {
@@ -103,13 +104,18 @@ func SetupRoutes(e *echo.Echo, db *sqlx.DB, cfg *config.Config) {
 	userHandler := handlers.NewUserHandler(db)
 	apiGroup.GET("/user/me", userHandler.GetCurrentUser)
 	apiGroup.PUT("/user/me", userHandler.UpdateCurrentUser)
+	apiGroup.POST("/user/me/stats", userHandler.SpendStats)
+	apiGroup.POST("/user/me/stats/reset", userHandler.ResetStats)
 	apiGroup.GET("/users/me/inventory", userHandler.GetUserInventory)
 	apiGroup.GET("/users/me/equipped", userHandler.GetUserEquippedItems)
+	apiGroup.GET("/users/me/movements", userHandler.GetUserMovements)
 
 	avatarHandler := handlers.NewAvatarHandler(db)
 	apiGroup.GET("/avatars", avatarHandler.GetAllAvatars)
 
-	locationHandler := handlers.NewLocationHandler(db)
+	locationHandler := handlers.NewLocationHandler(db, movingWorker)
+	apiGroup.GET("/locations/movement", locationHandler.GetMovement)
+	apiGroup.DELETE("/locations/movement", locationHandler.CancelMovement)
 	apiGroup.POST("/locations/:slug/move", locationHandler.MoveToLocation)
 	apiGroup.POST("/locations/:slug/cells/:cell_slug/move", locationHandler.MoveToCell)
 	apiGroup.GET("/locations/:slug/cells", locationHandler.GetLocationCells)
@@ -121,13 +127,40 @@ func SetupRoutes(e *echo.Echo, db *sqlx.DB, cfg *config.Config) {
 	apiGroup.POST("/equipment_items/:slug/sell", equipmentItemHandler.SellEquipmentItem)
 	apiGroup.POST("/equipment_items/:slug/take_on", equipmentItemHandler.TakeOnEquipmentItem)
 
+	consumableItemHandler := handlers.NewConsumableItemHandler(db)
+	apiGroup.GET("/consumable_items", consumableItemHandler.GetConsumableItems)
+	apiGroup.POST("/consumable_items/:slug/buy", consumableItemHandler.BuyConsumableItem)
+	apiGroup.GET("/users/me/consumables", consumableItemHandler.GetUserConsumables)
+	apiGroup.POST("/inventory/:id/use", consumableItemHandler.UseInventoryItem)
+
+	innHandler := handlers.NewInnHandler(db)
+	apiGroup.GET("/inn", innHandler.GetQuote)
+	apiGroup.POST("/inn/heal", innHandler.Heal)
+
 	botHandler := handlers.NewBotHandler(db)
 	apiGroup.GET("/bots/:location_slug", botHandler.GetBots)
 	apiGroup.POST("/bots/:slug/attack", botHandler.Attack)
 
 	fightHandler := handlers.NewFightHandler(db)
+	apiGroup.GET("/fights", fightHandler.GetFights)
 	apiGroup.GET("/fights/current", fightHandler.GetCurrentFight)
 	apiGroup.POST("/fights/current/hit", fightHandler.Hit)
+	apiGroup.POST("/fights/current/flee", fightHandler.Flee)
+	apiGroup.GET("/fights/:id", fightHandler.GetFight)
+
+	duelHandler := handlers.NewDuelHandler(db)
+	apiGroup.POST("/duels", duelHandler.Challenge)
+	apiGroup.GET("/duels/current", duelHandler.GetCurrentDuel)
+	apiGroup.POST("/duels/current/hit", duelHandler.Hit)
+	apiGroup.POST("/duels/:id/accept", duelHandler.Accept)
+	apiGroup.POST("/duels/:id/decline", duelHandler.Decline)
+
+	groupFightHandler := handlers.NewGroupFightHandler(db)
+	apiGroup.POST("/group_fights", groupFightHandler.Start)
+	apiGroup.GET("/group_fights", groupFightHandler.GetJoinable)
+	apiGroup.GET("/group_fights/current", groupFightHandler.GetCurrent)
+	apiGroup.POST("/group_fights/current/hit", groupFightHandler.Hit)
+	apiGroup.POST("/group_fights/:id/join", groupFightHandler.Join)
 }
 
 // healthCheck godoc
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+// findFighter loads a user about to trade blows together with the armour and
+// combat stats of the items they wear and the buffs running on them.
+func findFighter(userRepo *repository.UserRepository, id uuid.UUID) (*domain.User, error) {
+	user, err := userRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Armour, err = userRepo.FindArmour(id); err != nil {
+		return nil, err
+	}
+
+	if user.CombatStats, err = userRepo.FindCombatStats(id); err != nil {
+		return nil, err
+	}
+
+	if user.Buffs, err = userRepo.FindActiveBuffs(id); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+// findBot loads the bot of a fight together with its phases when it is a
+// boss.
+func findBot(botRepo *repository.BotRepository, id uuid.UUID) (*domain.Bot, error) {
+	bot, err := botRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if bot.IsBoss {
+		if bot.Phases, err = botRepo.FindPhasesByBotID(bot.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return bot, nil
+}
+
+// roundResult is the outcome of one exchange of blows between a player and
+// a bot.
+type roundResult struct {
+	BotAttack  domain.BodyPart
+	BotDefense domain.BodyPart
+	PlayerDmg  uint
+	BotDmg     uint
+	PlayerHp   uint
+	BotHp      uint
+	Ability    *domain.BotAbility
+	// Critical, Dodged, ShieldBlocked and their bot counterparts are the
+	// combat stat rolls of the round, see Round.
+	Critical         bool
+	Dodged           bool
+	ShieldBlocked    bool
+	BotCritical      bool
+	BotDodged        bool
+	BotShieldBlocked bool
+}
+
+// playRound resolves a round: the bot fights with the stats and strategy of
+// the phase its HP has reached, and a boss phase may fire its ability.
+// rounds is the fight history, newest first.
+func playRound(rng Rand, user *domain.User, bot *domain.Bot, rounds []*domain.Round,
+	playerHp, botHp uint, playerAttackPoint, playerDefensePoint string) roundResult {
+	phase := bot.PhaseAt(botHp)
+	fighter := bot.WithPhase(phase)
+
+	var res roundResult
+	res.BotAttack, res.BotDefense = botStrategyFor(fighter).Choose(rounds, rng.Intn)
+	res.Ability = rollAbility(rng, phase)
+
+	playerHit := defaultDamagePipeline.Resolve(rng, Hit{
+		Attack:       user.Attack,
+		Defense:      fighter.Defense,
+		AttackPoint:  playerAttackPoint,
+		DefensePoint: string(res.BotDefense),
+		Attacker:     user.CombatStats,
+		Defender:     fighter.CombatStats,
+	})
+	res.PlayerDmg, res.Critical = playerHit.Damage, playerHit.Critical
+	res.BotDodged, res.BotShieldBlocked = playerHit.Dodged, playerHit.ShieldBlocked
+
+	var botHit Hit
+	res.BotAttack, botHit = botStrike(rng, fighter, user, res.Ability, res.BotAttack, playerDefensePoint)
+	res.BotDmg, res.BotCritical = botHit.Damage, botHit.Critical
+	res.Dodged, res.ShieldBlocked = botHit.Dodged, botHit.ShieldBlocked
+
+	res.PlayerHp = calculateFinalHp(playerHp, res.BotDmg)
+	res.BotHp = healBot(bot, phase, res.Ability, calculateFinalHp(botHp, res.PlayerDmg))
+
+	return res
+}
+
+// botStrike returns where the bot actually hit and how the hit went, once
+// the ability fired this round is taken into account.
+func botStrike(rng Rand, fighter *domain.Bot, user *domain.User, ability *domain.BotAbility,
+	attackPoint domain.BodyPart, playerDefensePoint string) (domain.BodyPart, Hit) {
+	if ability != nil && *ability == domain.BotAbilityUnblockableHead {
+		attackPoint, playerDefensePoint = domain.BodyPartHead, ""
+	}
+
+	hit := Hit{
+		Attack:       fighter.Attack,
+		Defense:      user.BlockDefense(),
+		Armour:       user.Armour.At(attackPoint),
+		AttackPoint:  string(attackPoint),
+		DefensePoint: playerDefensePoint,
+		Attacker:     fighter.CombatStats,
+		Defender:     user.CombatStats,
+	}
+
+	if ability != nil && *ability == domain.BotAbilityDoubleStrike {
+		first := defaultDamagePipeline.Resolve(rng, hit)
+		second := defaultDamagePipeline.Resolve(rng, hit)
+		first.Damage += second.Damage
+		first.Critical = first.Critical || second.Critical
+		first.Dodged = first.Dodged && second.Dodged
+		first.ShieldBlocked = first.ShieldBlocked || second.ShieldBlocked
+		return attackPoint, first
+	}
+
+	return attackPoint, defaultDamagePipeline.Resolve(rng, hit)
+}
+
+// healBot applies a HEAL ability to what the bot has left after the
+// players' hits. A dead bot stays dead.
+func healBot(bot *domain.Bot, phase *domain.BotPhase, ability *domain.BotAbility, botHp uint) uint {
+	if ability == nil || *ability != domain.BotAbilityHeal || botHp == 0 {
+		return botHp
+	}
+	return min(bot.Hp, botHp+phase.AbilityPower)
+}
+
+// rollAbility fires the phase's ability with its chance.
+func rollAbility(rng Rand, phase *domain.BotPhase) *domain.BotAbility {
+	if phase == nil || phase.Ability == nil || phase.AbilityChance == 0 {
+		return nil
+	}
+	if uint(rng.Intn(100)) >= phase.AbilityChance {
+		return nil
+	}
+
+	ability := *phase.Ability
+	return &ability
+}
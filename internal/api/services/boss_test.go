@@ -0,0 +1,148 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+)
+
+// fixedRand always rolls the same values, so abilities fire on a zero chance
+// roll and damage has no spread.
+type fixedRand struct {
+	n int
+}
+
+func (r fixedRand) Intn(n int) int {
+	return min(r.n, n-1)
+}
+
+func (r fixedRand) Float64() float64 {
+	return 0.5
+}
+
+func bossWithPhase(ability domain.BotAbility, power uint) *domain.Bot {
+	attack := uint(10)
+	return &domain.Bot{
+		Attack:  5,
+		Defense: 0,
+		Hp:      100,
+		IsBoss:  true,
+		Phases: []*domain.BotPhase{{
+			HpPercent:     50,
+			Attack:        &attack,
+			Ability:       &ability,
+			AbilityChance: 100,
+			AbilityPower:  power,
+		}},
+	}
+}
+
+func TestBotPhaseAt(t *testing.T) {
+	defense := uint(20)
+	bot := &domain.Bot{
+		Attack:  5,
+		Defense: 5,
+		Hp:      100,
+		Phases: []*domain.BotPhase{
+			{HpPercent: 60},
+			{HpPercent: 25, Defense: &defense},
+		},
+	}
+
+	assert.Nil(t, bot.PhaseAt(61))
+	assert.Equal(t, uint(60), bot.PhaseAt(60).HpPercent)
+	assert.Equal(t, uint(25), bot.PhaseAt(10).HpPercent)
+
+	fighter := bot.WithPhase(bot.PhaseAt(10))
+	assert.Equal(t, uint(20), fighter.Defense)
+	assert.Equal(t, uint(5), fighter.Attack)
+	assert.Equal(t, uint(5), bot.Defense, "the bot itself must not change")
+}
+
+func TestPlayRound(t *testing.T) {
+	user := &domain.User{Attack: 10, Defense: 0}
+
+	t.Run("no ability above the phase threshold", func(t *testing.T) {
+		res := playRound(fixedRand{}, user, bossWithPhase(domain.BotAbilityDoubleStrike, 0), nil, 100, 100, "HEAD", "HEAD")
+		assert.Nil(t, res.Ability)
+		assert.Equal(t, uint(5), res.BotDmg)
+	})
+
+	t.Run("double strike hits twice with phase attack", func(t *testing.T) {
+		res := playRound(fixedRand{n: 1}, user, bossWithPhase(domain.BotAbilityDoubleStrike, 0), nil, 100, 50, "HEAD", "HEAD")
+		assert.Equal(t, domain.BotAbilityDoubleStrike, *res.Ability)
+		assert.Equal(t, uint(20), res.BotDmg)
+		assert.Equal(t, uint(80), res.PlayerHp)
+	})
+
+	t.Run("unblockable head ignores defense", func(t *testing.T) {
+		user := &domain.User{Attack: 10, Defense: 8}
+		res := playRound(fixedRand{}, user, bossWithPhase(domain.BotAbilityUnblockableHead, 0), nil, 100, 50, "HEAD", "HEAD")
+		assert.Equal(t, domain.BodyPartHead, res.BotAttack)
+		assert.Equal(t, uint(10), res.BotDmg)
+	})
+
+	t.Run("heal restores hp up to the maximum", func(t *testing.T) {
+		res := playRound(fixedRand{n: 1}, user, bossWithPhase(domain.BotAbilityHeal, 15), nil, 100, 50, "HEAD", "NECK")
+		assert.Equal(t, uint(10), res.PlayerDmg)
+		assert.Equal(t, uint(55), res.BotHp)
+
+		res = playRound(fixedRand{n: 1}, user, bossWithPhase(domain.BotAbilityHeal, 500), nil, 100, 50, "HEAD", "NECK")
+		assert.Equal(t, uint(100), res.BotHp)
+	})
+
+	t.Run("heal does not revive a killed boss", func(t *testing.T) {
+		res := playRound(fixedRand{n: 1}, user, bossWithPhase(domain.BotAbilityHeal, 15), nil, 100, 5, "HEAD", "NECK")
+		assert.Equal(t, uint(0), res.BotHp)
+	})
+
+	t.Run("bot combat stats go through the same stages", func(t *testing.T) {
+		bot := &domain.Bot{
+			Attack:      10,
+			Hp:          100,
+			CombatStats: domain.CombatStats{CritChance: 50, DodgeChance: 50},
+		}
+		res := playRound(fixedRand{}, user, bot, nil, 100, 100, "HEAD", "NECK")
+		assert.True(t, res.BotDodged)
+		assert.Equal(t, uint(0), res.PlayerDmg)
+		assert.True(t, res.BotCritical)
+		assert.Equal(t, uint(15), res.BotDmg)
+	})
+}
+
+// countingRand rolls zero and counts how often it was asked.
+type countingRand struct {
+	calls int
+}
+
+func (r *countingRand) Intn(int) int {
+	r.calls++
+	return 0
+}
+
+func (r *countingRand) Float64() float64 {
+	r.calls++
+	return 0
+}
+
+func TestRollLoot(t *testing.T) {
+	loot := []*domain.BotLoot{{EquipmentItemID: uuid.New(), Weight: 1, MinLevel: 1}}
+
+	t.Run("bosses always drop with a single roll", func(t *testing.T) {
+		rng := &countingRand{}
+		id := rollLoot(rng, &domain.Bot{IsBoss: true}, loot, 1)
+		require.NotNil(t, id)
+		assert.Equal(t, loot[0].EquipmentItemID, *id)
+		assert.Equal(t, 1, rng.calls)
+	})
+
+	t.Run("other bots roll for the drop first", func(t *testing.T) {
+		rng := &countingRand{}
+		assert.Nil(t, rollLoot(rng, &domain.Bot{}, loot, 1), "a zero roll misses the one in four chance")
+		assert.Equal(t, 1, rng.calls)
+	})
+}
@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -11,27 +13,30 @@ import (
 	"moonshine/internal/repository"
 )
 
+var ErrNoBotAvailable = errors.New("all bots of this kind are busy or dead")
+var ErrBossOnCooldown = errors.New("boss was killed recently")
+
 type BotService struct {
 	locationRepo *repository.LocationRepository
+	instanceRepo *repository.BotInstanceRepository
 	botRepo      *repository.BotRepository
 	userRepo     *repository.UserRepository
-	fightRepo    *repository.FightRepository
-	roundRepo    *repository.RoundRepository
 	db           *sqlx.DB
 }
 
 func NewBotService(db *sqlx.DB) *BotService {
 	return &BotService{
 		locationRepo: repository.NewLocationRepository(db),
+		instanceRepo: repository.NewBotInstanceRepository(db),
 		botRepo:      repository.NewBotRepository(db),
 		userRepo:     repository.NewUserRepository(db),
-		fightRepo:    repository.NewFightRepository(db),
-		roundRepo:    repository.NewRoundRepository(db),
 		db:           db,
 	}
 }
 
-func (s *BotService) GetBotsByLocationSlug(locationSlug string) ([]*domain.Bot, error) {
+// GetBotsByLocationSlug spawns what is due in the location and returns its
+// instances, dead ones included so players can see when they come back.
+func (s *BotService) GetBotsByLocationSlug(locationSlug string) ([]*domain.BotInstance, error) {
 	if locationSlug == "" {
 		return nil, errors.New("location slug is required")
 	}
@@ -41,17 +46,35 @@ func (s *BotService) GetBotsByLocationSlug(locationSlug string) ([]*domain.Bot,
 		return nil, err
 	}
 
+	if err := s.instanceRepo.Spawn(location.ID); err != nil {
+		return nil, err
+	}
+
+	instances, err := s.instanceRepo.FindByLocationID(location.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	bots, err := s.botRepo.FindBotsByLocationID(location.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	return bots, nil
+	botsByID := make(map[uuid.UUID]*domain.Bot, len(bots))
+	for _, bot := range bots {
+		botsByID[bot.ID] = bot
+	}
+	for _, instance := range instances {
+		instance.Bot = botsByID[instance.BotID]
+	}
+
+	return instances, nil
 }
 
 type AttackResult struct {
-	User *domain.User
-	Bot  *domain.Bot
+	User     *domain.User
+	Bot      *domain.Bot
+	Instance *domain.BotInstance
 }
 
 func (s *BotService) Attack(ctx context.Context, botSlug string, userID uuid.UUID) (*AttackResult, error) {
@@ -64,21 +87,54 @@ func (s *BotService) Attack(ctx context.Context, botSlug string, userID uuid.UUI
 		return nil, err
 	}
 
-	inFight, err := s.userRepo.InFight(userID)
-	if err != nil || inFight {
+	bot, err := s.botRepo.FindBySlug(botSlug)
+	if err != nil {
 		return nil, err
 	}
 
-	bot, err := s.botRepo.FindBySlug(botSlug)
+	if bot.IsBoss {
+		until, err := repository.NewBossKillRepository(s.db).CooldownUntil(user.ID, bot.ID)
+		if err != nil {
+			return nil, err
+		}
+		if until != nil {
+			return nil, fmt.Errorf("%w, try again after %s", ErrBossOnCooldown, until.UTC().Format(time.RFC3339))
+		}
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
-	if exists, err := s.locationRepo.HasBot(user.LocationID, bot.ID); err != nil || !exists {
-		return nil, errors.New("bot is not in the same location as user")
+	if err := s.userRepo.LockWithExt(tx, userID); err != nil {
+		return nil, err
+	}
+	if inFight, err := s.userRepo.InFightWithExt(tx, userID); err != nil {
+		return nil, err
+	} else if inFight {
+		return nil, ErrUserInFight
 	}
 
-	fightID, err := s.fightRepo.Create(&domain.Fight{
+	instanceRepoTx := repository.NewBotInstanceRepository(tx)
+
+	if err := instanceRepoTx.Spawn(user.LocationID); err != nil {
+		return nil, err
+	}
+
+	instance, err := instanceRepoTx.FindAvailableForUpdate(user.LocationID, bot.ID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrBotInstanceNotFound) {
+			return nil, err
+		}
+		if exists, err := s.locationRepo.HasBot(user.LocationID, bot.ID); err != nil || !exists {
+			return nil, errors.New("bot is not in the same location as user")
+		}
+		return nil, ErrNoBotAvailable
+	}
+
+	fightID, err := repository.NewFightRepository(tx).Create(&domain.Fight{
 		UserID: user.ID,
 		BotID:  bot.ID,
 	})
@@ -86,13 +142,30 @@ func (s *BotService) Attack(ctx context.Context, botSlug string, userID uuid.UUI
 		return nil, err
 	}
 
-	err = s.roundRepo.Create(fightID, user.CurrentHp, bot.Hp)
+	if user.CurrentHp, err = s.userRepo.FreezeHpWithExt(tx, user.ID); err != nil {
+		return nil, err
+	}
+	if err := cancelMovementWithExt(tx, user.ID); err != nil {
+		return nil, err
+	}
+
+	err = repository.NewRoundRepository(tx).Create(fightID, user.CurrentHp, instance.CurrentHp)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := instanceRepoTx.Engage(instance.ID, fightID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	instance.Bot = bot
 	return &AttackResult{
-		User: user,
-		Bot:  bot,
+		User:     user,
+		Bot:      bot,
+		Instance: instance,
 	}, nil
 }
@@ -0,0 +1,112 @@
+package services
+
+import "moonshine/internal/domain"
+
+// BotStrategy picks where a bot attacks and defends in the next round. rounds
+// is the fight history as returned by RoundRepository.FindByFightID, newest
+// first; rounds the player has not moved in yet are ignored.
+type BotStrategy interface {
+	Choose(rounds []*domain.Round, intn func(int) int) (attack, defense domain.BodyPart)
+}
+
+func botStrategyFor(bot *domain.Bot) BotStrategy {
+	switch bot.Strategy {
+	case domain.BotStrategyFavoursHead:
+		return favoursHeadStrategy{}
+	case domain.BotStrategyMirror:
+		return mirrorStrategy{}
+	case domain.BotStrategyWeighted:
+		return weightedStrategy{}
+	default:
+		return randomStrategy{}
+	}
+}
+
+func randomBotBodyPart(intn func(int) int) domain.BodyPart {
+	return domain.BodyParts[intn(len(domain.BodyParts))]
+}
+
+// playerMoves returns the rounds where the player already chose both points.
+func playerMoves(rounds []*domain.Round) []*domain.Round {
+	var moves []*domain.Round
+	for _, r := range rounds {
+		if r.PlayerAttackPoint != nil && r.PlayerDefensePoint != nil {
+			moves = append(moves, r)
+		}
+	}
+	return moves
+}
+
+type randomStrategy struct{}
+
+func (randomStrategy) Choose(_ []*domain.Round, intn func(int) int) (domain.BodyPart, domain.BodyPart) {
+	return randomBotBodyPart(intn), randomBotBodyPart(intn)
+}
+
+// favoursHeadStrategy goes for the head half of the time and defends at random.
+type favoursHeadStrategy struct{}
+
+func (favoursHeadStrategy) Choose(_ []*domain.Round, intn func(int) int) (domain.BodyPart, domain.BodyPart) {
+	attack := domain.BodyPartHead
+	if intn(2) == 0 {
+		attack = randomBotBodyPart(intn)
+	}
+	return attack, randomBotBodyPart(intn)
+}
+
+// mirrorStrategy repeats the player's previous round back at them.
+type mirrorStrategy struct{}
+
+func (mirrorStrategy) Choose(rounds []*domain.Round, intn func(int) int) (domain.BodyPart, domain.BodyPart) {
+	moves := playerMoves(rounds)
+	if len(moves) == 0 {
+		return randomStrategy{}.Choose(rounds, intn)
+	}
+	last := moves[0]
+	return *last.PlayerAttackPoint, *last.PlayerDefensePoint
+}
+
+// weightedStrategy learns from the player's habits: it defends where the
+// player attacks most and attacks where the player defends least.
+type weightedStrategy struct{}
+
+func (weightedStrategy) Choose(rounds []*domain.Round, intn func(int) int) (domain.BodyPart, domain.BodyPart) {
+	attacked := make(map[domain.BodyPart]int, len(domain.BodyParts))
+	defended := make(map[domain.BodyPart]int, len(domain.BodyParts))
+	for _, r := range playerMoves(rounds) {
+		attacked[*r.PlayerAttackPoint]++
+		defended[*r.PlayerDefensePoint]++
+	}
+
+	attackWeights := make([]int, len(domain.BodyParts))
+	defenseWeights := make([]int, len(domain.BodyParts))
+	maxDefended := 0
+	for _, part := range domain.BodyParts {
+		if defended[part] > maxDefended {
+			maxDefended = defended[part]
+		}
+	}
+	for i, part := range domain.BodyParts {
+		attackWeights[i] = maxDefended - defended[part] + 1
+		defenseWeights[i] = attacked[part] + 1
+	}
+
+	return domain.BodyParts[pickWeighted(attackWeights, intn)], domain.BodyParts[pickWeighted(defenseWeights, intn)]
+}
+
+func pickWeighted(weights []int, intn func(int) int) int {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+
+	roll := intn(total)
+	for i, w := range weights {
+		roll -= w
+		if roll < 0 {
+			return i
+		}
+	}
+
+	return len(weights) - 1
+}
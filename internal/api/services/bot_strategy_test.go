@@ -0,0 +1,70 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"moonshine/internal/domain"
+)
+
+func playedRound(attack, defense domain.BodyPart) *domain.Round {
+	return &domain.Round{PlayerAttackPoint: &attack, PlayerDefensePoint: &defense}
+}
+
+func TestBotStrategyFor(t *testing.T) {
+	assert.IsType(t, randomStrategy{}, botStrategyFor(&domain.Bot{}))
+	assert.IsType(t, favoursHeadStrategy{}, botStrategyFor(&domain.Bot{Strategy: domain.BotStrategyFavoursHead}))
+	assert.IsType(t, mirrorStrategy{}, botStrategyFor(&domain.Bot{Strategy: domain.BotStrategyMirror}))
+	assert.IsType(t, weightedStrategy{}, botStrategyFor(&domain.Bot{Strategy: domain.BotStrategyWeighted}))
+}
+
+func TestBotStrategyChoose(t *testing.T) {
+	first := func(int) int { return 0 }
+	last := func(n int) int { return n - 1 }
+
+	t.Run("favours head", func(t *testing.T) {
+		attack, _ := favoursHeadStrategy{}.Choose(nil, last)
+		assert.Equal(t, domain.BodyPartHead, attack)
+	})
+
+	t.Run("mirror repeats the last finished round", func(t *testing.T) {
+		rounds := []*domain.Round{
+			{},
+			playedRound(domain.BodyPartLegs, domain.BodyPartChest),
+			playedRound(domain.BodyPartHead, domain.BodyPartHead),
+		}
+
+		attack, defense := mirrorStrategy{}.Choose(rounds, first)
+		assert.Equal(t, domain.BodyPartLegs, attack)
+		assert.Equal(t, domain.BodyPartChest, defense)
+	})
+
+	t.Run("mirror falls back to random", func(t *testing.T) {
+		attack, defense := mirrorStrategy{}.Choose([]*domain.Round{{}}, first)
+		assert.Equal(t, domain.BodyParts[0], attack)
+		assert.Equal(t, domain.BodyParts[0], defense)
+	})
+
+	t.Run("weighted follows the player's habits", func(t *testing.T) {
+		var rounds []*domain.Round
+		for i := 0; i < 10; i++ {
+			rounds = append(rounds, playedRound(domain.BodyPartLegs, domain.BodyPartHead))
+		}
+
+		attack, defense := weightedStrategy{}.Choose(rounds, last)
+		assert.Equal(t, domain.BodyPartLegs, attack)
+		assert.Equal(t, domain.BodyPartLegs, defense)
+
+		attack, _ = weightedStrategy{}.Choose(rounds, func(int) int { return 1 })
+		assert.Equal(t, domain.BodyPartNeck, attack)
+	})
+}
+
+func TestPickWeighted(t *testing.T) {
+	weights := []int{1, 0, 3}
+
+	assert.Equal(t, 0, pickWeighted(weights, func(int) int { return 0 }))
+	assert.Equal(t, 2, pickWeighted(weights, func(int) int { return 1 }))
+	assert.Equal(t, 2, pickWeighted(weights, func(int) int { return 3 }))
+}
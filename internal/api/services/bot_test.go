@@ -70,8 +70,9 @@ func TestBotService_GetBotsByLocationSlug(t *testing.T) {
 		bots, err := service.GetBotsByLocationSlug(location.Slug)
 		require.NoError(t, err)
 		assert.NotEmpty(t, bots)
-		assert.Equal(t, bot.Slug, bots[0].Slug)
-		assert.Equal(t, bot.Name, bots[0].Name)
+		assert.Equal(t, bot.Slug, bots[0].Bot.Slug)
+		assert.Equal(t, bot.Name, bots[0].Bot.Name)
+		assert.Equal(t, domain.BotInstanceStateAlive, bots[0].State)
 	})
 
 	t.Run("empty location slug returns error", func(t *testing.T) {
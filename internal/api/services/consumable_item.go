@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+var (
+	ErrConsumableItemNotFound = errors.New("consumable item not found")
+)
+
+type ConsumableItemService struct {
+	db                 *sqlx.DB
+	consumableItemRepo *repository.ConsumableItemRepository
+	inventoryRepo      *repository.InventoryRepository
+	userRepo           *repository.UserRepository
+	fightRepo          *repository.FightRepository
+	fightService       *FightService
+	publisher          ws.Publisher
+}
+
+func NewConsumableItemService(db *sqlx.DB, fightService *FightService) *ConsumableItemService {
+	return &ConsumableItemService{
+		db:                 db,
+		consumableItemRepo: repository.NewConsumableItemRepository(db),
+		inventoryRepo:      repository.NewInventoryRepository(db),
+		userRepo:           repository.NewUserRepository(db),
+		fightRepo:          repository.NewFightRepository(db),
+		fightService:       fightService,
+		publisher:          ws.GetHub(),
+	}
+}
+
+// UseConsumableResult is what using a consumable did. Buff is nil for items
+// that only heal; Fight is set when the item was used in a fight against a
+// bot and holds the round that followed.
+type UseConsumableResult struct {
+	Item  *domain.ConsumableItem
+	User  *domain.User
+	Buff  *domain.UserBuff
+	Fight *GetCurrentFightResult
+}
+
+func (s *ConsumableItemService) GetAll(ctx context.Context) ([]*domain.ConsumableItem, error) {
+	return s.consumableItemRepo.FindAll()
+}
+
+func (s *ConsumableItemService) Buy(ctx context.Context, userID uuid.UUID, slug string) error {
+	item, err := s.consumableItemRepo.FindBySlug(slug)
+	if err != nil {
+		if errors.Is(err, repository.ErrConsumableItemNotFound) {
+			return ErrConsumableItemNotFound
+		}
+		return err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE users SET gold = gold - $1 WHERE id = $2 AND gold >= $1 AND deleted_at IS NULL`,
+		item.Price, userID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrInsufficientGold
+	}
+
+	inventory := &domain.InventoryConsumable{
+		UserID:           userID,
+		ConsumableItemID: item.ID,
+	}
+	if err := repository.NewInventoryRepository(tx).CreateConsumable(inventory); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetUserConsumables lists the consumables the user carries with their items
+// filled in.
+func (s *ConsumableItemService) GetUserConsumables(ctx context.Context, userID uuid.UUID) ([]*domain.InventoryConsumable, error) {
+	consumables, err := s.inventoryRepo.FindConsumablesByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.consumableItemRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*domain.ConsumableItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	for _, c := range consumables {
+		c.Item = byID[c.ConsumableItemID]
+	}
+
+	return consumables, nil
+}
+
+// Use consumes one item from the user's inventory. In a fight against a bot
+// it costs the player's turn, see FightService.UseItem; duels and group
+// fights do not allow it.
+func (s *ConsumableItemService) Use(ctx context.Context, userID, inventoryID uuid.UUID) (*UseConsumableResult, error) {
+	if _, err := s.fightRepo.FindActiveByUserID(userID); err == nil {
+		used, err := s.fightService.UseItem(ctx, userID, inventoryID)
+		if err != nil {
+			return nil, err
+		}
+		return &UseConsumableResult{
+			Item:  used.Item,
+			User:  used.User,
+			Buff:  used.Buff,
+			Fight: used.GetCurrentFightResult,
+		}, nil
+	}
+
+	inFight, err := s.userRepo.InFight(userID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if inFight {
+		return nil, ErrUserInFight
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	defer tx.Rollback()
+
+	item, err := consume(tx, user, inventoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.CurrentHp, err = s.userRepo.HealWithExt(tx, userID, item.HealHp); err != nil {
+		return nil, ErrInternalError
+	}
+
+	var buff *domain.UserBuff
+	if item.HasBuff() {
+		if buff, err = s.userRepo.AddBuffWithExt(tx, userID, item); err != nil {
+			return nil, ErrInternalError
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, ErrInternalError
+	}
+
+	s.publisher.Publish(userID, ws.HPUpdateEvent(user.CurrentHp, user.Hp))
+
+	return &UseConsumableResult{
+		Item: item,
+		User: user,
+		Buff: buff,
+	}, nil
+}
+
+// consume takes one consumable out of the user's inventory within tx and
+// returns its item. The user must be of the item's level.
+func consume(tx repository.ExtHandle, user *domain.User, inventoryID uuid.UUID) (*domain.ConsumableItem, error) {
+	itemID, err := repository.NewInventoryRepository(tx).TakeConsumable(user.ID, inventoryID)
+	if err != nil {
+		if errors.Is(err, repository.ErrInventoryNotFound) {
+			return nil, ErrItemNotInInventory
+		}
+		return nil, ErrInternalError
+	}
+
+	item, err := repository.NewConsumableItemRepository(tx).FindByID(itemID)
+	if err != nil {
+		if errors.Is(err, repository.ErrConsumableItemNotFound) {
+			return nil, ErrConsumableItemNotFound
+		}
+		return nil, ErrInternalError
+	}
+
+	if user.Level < item.RequiredLevel {
+		return nil, ErrInsufficientLevel
+	}
+
+	return item, nil
+}
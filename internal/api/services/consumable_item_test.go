@@ -0,0 +1,57 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"moonshine/internal/domain"
+)
+
+func TestRollConsumableDrop(t *testing.T) {
+	potion, elixir := uuid.New(), uuid.New()
+	drops := []*domain.BotConsumableDrop{
+		{ConsumableItemID: potion, Chance: 20},
+		{ConsumableItemID: elixir, Chance: 50},
+	}
+
+	t.Run("first drop that comes up wins", func(t *testing.T) {
+		id := rollConsumableDrop(fixedRand{n: 10}, drops)
+		if assert.NotNil(t, id) {
+			assert.Equal(t, potion, *id)
+		}
+	})
+
+	t.Run("later drops get their own roll", func(t *testing.T) {
+		id := rollConsumableDrop(fixedRand{n: 30}, drops)
+		if assert.NotNil(t, id) {
+			assert.Equal(t, elixir, *id)
+		}
+	})
+
+	t.Run("nothing comes up", func(t *testing.T) {
+		assert.Nil(t, rollConsumableDrop(fixedRand{n: 60}, drops))
+		assert.Nil(t, rollConsumableDrop(fixedRand{n: 0}, nil))
+	})
+}
+
+func TestFighterOf_Buffs(t *testing.T) {
+	now := time.Now()
+	user := &domain.User{
+		Attack:  10,
+		Defense: 4,
+		Buffs: []*domain.UserBuff{
+			{Attack: 5, DodgeChance: 10, ExpiresAt: now.Add(time.Minute)},
+			{Defense: 100, ExpiresAt: now.Add(-time.Second)},
+		},
+	}
+
+	fighter := fighterOf(user)
+
+	assert.Equal(t, uint(15), fighter.Attack)
+	assert.Equal(t, uint(4), fighter.Defense, "expired buffs are ignored")
+	assert.Equal(t, uint(10), fighter.CombatStats.DodgeChance)
+	assert.Equal(t, uint(10), user.Attack, "the user itself is left untouched")
+}
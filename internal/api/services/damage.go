@@ -0,0 +1,94 @@
+package services
+
+import "moonshine/internal/domain"
+
+// Hit is one blow going through a DamagePipeline: what the attacker swings
+// with, what stands in the way and, once resolved, what came of it.
+type Hit struct {
+	Attack uint
+	// Defense only counts when DefensePoint is the attacked part; Armour
+	// always does.
+	Defense      uint
+	Armour       uint
+	AttackPoint  string
+	DefensePoint string
+	Attacker     domain.CombatStats
+	Defender     domain.CombatStats
+
+	Damage        uint
+	Critical      bool
+	Dodged        bool
+	ShieldBlocked bool
+}
+
+// DamageStage is one step of resolving a hit.
+type DamageStage interface {
+	Apply(rng Rand, hit *Hit)
+}
+
+// DamagePipeline runs its stages in order; a dodged hit skips the rest.
+type DamagePipeline []DamageStage
+
+// defaultDamagePipeline is used by every fight: the defender may dodge, then
+// the blow is reduced by armour and block, may turn critical and may be
+// partly stopped by a shield.
+var defaultDamagePipeline = DamagePipeline{
+	dodgeStage{},
+	baseDamageStage{},
+	critStage{},
+	shieldStage{},
+}
+
+func (p DamagePipeline) Resolve(rng Rand, hit Hit) Hit {
+	for _, stage := range p {
+		if hit.Dodged {
+			break
+		}
+		stage.Apply(rng, &hit)
+	}
+	return hit
+}
+
+// rollChance rolls a percent chance, capped at domain.MaxCombatChance. A
+// zero chance never touches rng.
+func rollChance(rng Rand, chance uint) bool {
+	if chance == 0 {
+		return false
+	}
+	return uint(rng.Intn(100)) < min(chance, domain.MaxCombatChance)
+}
+
+type dodgeStage struct{}
+
+func (dodgeStage) Apply(rng Rand, hit *Hit) {
+	if rollChance(rng, hit.Defender.DodgeChance) {
+		hit.Dodged = true
+		hit.Damage = 0
+	}
+}
+
+type baseDamageStage struct{}
+
+func (baseDamageStage) Apply(rng Rand, hit *Hit) {
+	hit.Damage = calculateDamage(rng, hit.Attack, hit.Defense, hit.Armour, hit.AttackPoint, hit.DefensePoint)
+}
+
+type critStage struct{}
+
+func (critStage) Apply(rng Rand, hit *Hit) {
+	if hit.Damage == 0 || !rollChance(rng, hit.Attacker.CritChance) {
+		return
+	}
+	hit.Critical = true
+	hit.Damage = hit.Damage * (100 + domain.BaseCritPower + hit.Attacker.CritPower) / 100
+}
+
+type shieldStage struct{}
+
+func (shieldStage) Apply(rng Rand, hit *Hit) {
+	if hit.Damage == 0 || !rollChance(rng, hit.Defender.BlockChance) {
+		return
+	}
+	hit.ShieldBlocked = true
+	hit.Damage -= hit.Damage * domain.ShieldBlockPercent / 100
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"moonshine/internal/domain"
+)
+
+func TestDamagePipeline_Resolve(t *testing.T) {
+	base := Hit{
+		Attack:       20,
+		Defense:      5,
+		Armour:       2,
+		AttackPoint:  string(domain.BodyPartChest),
+		DefensePoint: string(domain.BodyPartHead),
+	}
+
+	t.Run("no combat stats leaves the base damage", func(t *testing.T) {
+		hit := defaultDamagePipeline.Resolve(fixedRand{}, base)
+		assert.Equal(t, uint(18), hit.Damage)
+		assert.False(t, hit.Critical || hit.Dodged || hit.ShieldBlocked)
+	})
+
+	t.Run("a dodge stops the hit", func(t *testing.T) {
+		hit := base
+		hit.Attacker.CritChance = 100
+		hit.Defender.DodgeChance = 10
+
+		hit = defaultDamagePipeline.Resolve(fixedRand{}, hit)
+		assert.True(t, hit.Dodged)
+		assert.False(t, hit.Critical, "a dodged hit cannot crit")
+		assert.Equal(t, uint(0), hit.Damage)
+	})
+
+	t.Run("a crit adds base and extra power", func(t *testing.T) {
+		hit := base
+		hit.Attacker = domain.CombatStats{CritChance: 10, CritPower: 50}
+
+		hit = defaultDamagePipeline.Resolve(fixedRand{}, hit)
+		assert.True(t, hit.Critical)
+		assert.Equal(t, uint(36), hit.Damage)
+	})
+
+	t.Run("a shield block halves the hit", func(t *testing.T) {
+		hit := base
+		hit.Defender.BlockChance = 10
+
+		hit = defaultDamagePipeline.Resolve(fixedRand{}, hit)
+		assert.True(t, hit.ShieldBlocked)
+		assert.Equal(t, uint(9), hit.Damage)
+	})
+}
+
+func TestRollChance(t *testing.T) {
+	assert.False(t, rollChance(fixedRand{}, 0))
+	assert.True(t, rollChance(fixedRand{n: 74}, 100))
+	assert.False(t, rollChance(fixedRand{n: 75}, 100), "chances are capped")
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"time"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+// defeat carries a beaten player back to the start location with policy
+// applied. It runs in the transaction that finished the fight; user must
+// already hold the gold and exp the fight paid out.
+func defeat(tx repository.ExtHandle, userRepo *repository.UserRepository, locationRepo *repository.LocationRepository,
+	user *domain.User, policy domain.DefeatPolicy) (*domain.Defeat, error) {
+	location, err := locationRepo.FindStartLocation()
+	if err != nil {
+		return nil, err
+	}
+
+	lostGold := policy.GoldPenalty(user.Gold)
+	lostExp := policy.ExpPenalty(domain.CurrentProgression(), user.Level, user.Exp)
+
+	weakenedUntil, err := userRepo.DefeatWithExt(tx, user.ID, location.ID, lostGold, lostExp, policy.WeakenedFor)
+	if err != nil {
+		return nil, err
+	}
+
+	user.LocationID = location.ID
+	user.Gold -= lostGold
+	user.Exp -= lostExp
+	user.WeakenedUntil = weakenedUntil
+
+	return &domain.Defeat{
+		LostGold:        lostGold,
+		LostExp:         lostExp,
+		RespawnLocation: location,
+		WeakenedUntil:   weakenedUntil,
+	}, nil
+}
+
+// defeatInFight is defeat by the bot of a 1v1 fight, with the cost recorded
+// on the fight.
+func defeatInFight(tx repository.ExtHandle, userRepo *repository.UserRepository, locationRepo *repository.LocationRepository,
+	fight *domain.Fight, user *domain.User) error {
+	d, err := defeat(tx, userRepo, locationRepo, user, domain.CurrentDefeatPolicy())
+	if err != nil {
+		return err
+	}
+
+	fight.LostGold = d.LostGold
+	fight.LostExp = d.LostExp
+	fight.RespawnLocationID = &d.RespawnLocation.ID
+	fight.RespawnLocation = d.RespawnLocation
+	fight.WeakenedUntil = d.WeakenedUntil
+
+	return repository.NewFightRepository(tx).RecordDefeat(fight)
+}
+
+// fighterOf is the user as they fight right now: buffed by the elixirs they
+// drank and, still weakened after a recent defeat, hitting and blocking for
+// less.
+func fighterOf(user *domain.User) *domain.User {
+	now := time.Now()
+	fighter := user
+	if len(user.Buffs) > 0 {
+		buffed := *user
+		for _, buff := range user.Buffs {
+			if buff.IsActive(now) {
+				buff.ApplyTo(&buffed)
+			}
+		}
+		fighter = &buffed
+	}
+
+	if !fighter.IsWeakened(now) {
+		return fighter
+	}
+	return domain.CurrentDefeatPolicy().Weaken(fighter)
+}
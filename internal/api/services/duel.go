@@ -0,0 +1,524 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+const (
+	DuelTurnTimeout      = 30 * time.Second
+	DuelChallengeTimeout = 2 * time.Minute
+)
+
+var (
+	ErrDuelNotFound          = errors.New("duel not found")
+	ErrCannotDuelSelf        = errors.New("cannot challenge yourself")
+	ErrOpponentNotFound      = errors.New("opponent not found")
+	ErrOpponentNotInLocation = errors.New("opponent is not in your location")
+	ErrAlreadyInDuel         = errors.New("user already has an active duel")
+	ErrOpponentBusy          = errors.New("opponent is busy")
+	ErrUserInFight           = errors.New("user is in fight")
+	ErrDuelNotPending        = errors.New("duel is not pending")
+	ErrAlreadyMoved          = errors.New("move already made in this round")
+)
+
+type DuelService struct {
+	duelRepo      *repository.DuelRepository
+	duelRoundRepo *repository.DuelRoundRepository
+	userRepo      *repository.UserRepository
+	locationRepo  *repository.LocationRepository
+	publisher     ws.Publisher
+	rng           Rand
+	db            *sqlx.DB
+}
+
+func NewDuelService(db *sqlx.DB) *DuelService {
+	return NewDuelServiceWithRand(db, globalRand{})
+}
+
+// NewDuelServiceWithRand builds a DuelService whose damage rolls and default
+// choices for idle players come from rng.
+func NewDuelServiceWithRand(db *sqlx.DB, rng Rand) *DuelService {
+	return &DuelService{
+		duelRepo:      repository.NewDuelRepository(db),
+		duelRoundRepo: repository.NewDuelRoundRepository(db),
+		userRepo:      repository.NewUserRepository(db),
+		locationRepo:  repository.NewLocationRepository(db),
+		publisher:     ws.GetHub(),
+		rng:           rng,
+		db:            db,
+	}
+}
+
+func (s *DuelService) Challenge(ctx context.Context, userID uuid.UUID, opponentUsername string) (*domain.Duel, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	opponent, err := s.userRepo.FindByUsername(opponentUsername)
+	if err != nil {
+		return nil, ErrOpponentNotFound
+	}
+
+	if opponent.ID == user.ID {
+		return nil, ErrCannotDuelSelf
+	}
+
+	if opponent.LocationID != user.LocationID {
+		return nil, ErrOpponentNotInLocation
+	}
+
+	if err := s.checkAvailable(user.ID, ErrAlreadyInDuel); err != nil {
+		return nil, err
+	}
+	if err := s.checkAvailable(opponent.ID, ErrOpponentBusy); err != nil {
+		return nil, err
+	}
+
+	duel := &domain.Duel{
+		ChallengerID: user.ID,
+		OpponentID:   opponent.ID,
+		LocationID:   user.LocationID,
+	}
+	if err := s.duelRepo.Create(duel); err != nil {
+		return nil, ErrInternalError
+	}
+
+	s.notify(duel)
+
+	return duel, nil
+}
+
+func (s *DuelService) Accept(ctx context.Context, userID, duelID uuid.UUID) (*domain.Duel, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	defer tx.Rollback()
+
+	duelRepoTx := repository.NewDuelRepository(tx)
+
+	duel, err := duelRepoTx.FindByIDForUpdate(duelID)
+	if err != nil || duel.OpponentID != userID {
+		return nil, ErrDuelNotFound
+	}
+
+	if duel.Status != domain.DuelStatusPending {
+		return nil, ErrDuelNotPending
+	}
+
+	// Both players are locked in id order, so accepts that share a player
+	// cannot deadlock.
+	first, second := duel.ChallengerID, duel.OpponentID
+	if first.String() > second.String() {
+		first, second = second, first
+	}
+	for _, id := range []uuid.UUID{first, second} {
+		if err := s.userRepo.LockWithExt(tx, id); err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) && id == duel.ChallengerID {
+				return nil, ErrOpponentNotFound
+			} else if errors.Is(err, repository.ErrUserNotFound) {
+				return nil, ErrUserNotFound
+			}
+			return nil, ErrInternalError
+		}
+	}
+
+	challenger, err := s.userRepo.FindByID(duel.ChallengerID)
+	if err != nil {
+		return nil, ErrOpponentNotFound
+	}
+	opponent, err := s.userRepo.FindByID(duel.OpponentID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if challenger.LocationID != opponent.LocationID {
+		return nil, ErrOpponentNotInLocation
+	}
+
+	if inFight, err := s.userRepo.InFightWithExt(tx, opponent.ID); err != nil {
+		return nil, ErrInternalError
+	} else if inFight {
+		return nil, ErrUserInFight
+	}
+	if inFight, err := s.userRepo.InFightWithExt(tx, challenger.ID); err != nil {
+		return nil, ErrInternalError
+	} else if inFight {
+		return nil, ErrOpponentBusy
+	}
+
+	if err := duelRepoTx.UpdateStatus(duel.ID, domain.DuelStatusInProgress); err != nil {
+		return nil, ErrInternalError
+	}
+
+	if challenger.CurrentHp, err = s.userRepo.FreezeHpWithExt(tx, challenger.ID); err != nil {
+		return nil, ErrInternalError
+	}
+	if opponent.CurrentHp, err = s.userRepo.FreezeHpWithExt(tx, opponent.ID); err != nil {
+		return nil, ErrInternalError
+	}
+	for _, userID := range []uuid.UUID{challenger.ID, opponent.ID} {
+		if err := cancelMovementWithExt(tx, userID); err != nil {
+			return nil, ErrInternalError
+		}
+	}
+
+	duelRoundRepoTx := repository.NewDuelRoundRepository(tx)
+	if err := duelRoundRepoTx.Create(duel.ID, challenger.CurrentHp, opponent.CurrentHp, DuelTurnTimeout); err != nil {
+		return nil, ErrInternalError
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ErrInternalError
+	}
+
+	return s.reloadAndNotify(duel.ID)
+}
+
+// Decline rejects a pending challenge. The challenger declining withdraws it.
+func (s *DuelService) Decline(ctx context.Context, userID, duelID uuid.UUID) (*domain.Duel, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	defer tx.Rollback()
+
+	duelRepoTx := repository.NewDuelRepository(tx)
+
+	duel, err := duelRepoTx.FindByIDForUpdate(duelID)
+	if err != nil || !duel.IsParticipant(userID) {
+		return nil, ErrDuelNotFound
+	}
+
+	if duel.Status != domain.DuelStatusPending {
+		return nil, ErrDuelNotPending
+	}
+
+	if err := duelRepoTx.UpdateStatus(duel.ID, domain.DuelStatusDeclined); err != nil {
+		return nil, ErrInternalError
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ErrInternalError
+	}
+
+	return s.reloadAndNotify(duel.ID)
+}
+
+func (s *DuelService) GetCurrentDuel(ctx context.Context, userID uuid.UUID) (*domain.Duel, error) {
+	duel, err := s.duelRepo.FindActiveByUserID(userID)
+	if err != nil {
+		return nil, ErrDuelNotFound
+	}
+
+	rounds, err := s.duelRoundRepo.FindByDuelID(duel.ID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	duel.Rounds = rounds
+
+	return duel, nil
+}
+
+// Hit records the user's move for the current round. The round is resolved
+// once both participants have moved.
+func (s *DuelService) Hit(ctx context.Context, userID uuid.UUID, attackPoint, defensePoint string) (*domain.Duel, error) {
+	if !isValidBodyPart(attackPoint) || !isValidBodyPart(defensePoint) {
+		return nil, ErrInvalidBodyPart
+	}
+
+	active, err := s.duelRepo.FindActiveByUserID(userID)
+	if err != nil || active.Status != domain.DuelStatusInProgress {
+		return nil, ErrDuelNotFound
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	defer tx.Rollback()
+
+	duel, err := repository.NewDuelRepository(tx).FindByIDForUpdate(active.ID)
+	if err != nil || duel.Status != domain.DuelStatusInProgress {
+		return nil, ErrDuelNotFound
+	}
+
+	duelRoundRepoTx := repository.NewDuelRoundRepository(tx)
+	round, err := duelRoundRepoTx.FindCurrentForUpdate(duel.ID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	attack := domain.BodyPart(attackPoint)
+	defense := domain.BodyPart(defensePoint)
+
+	if duel.IsChallenger(userID) {
+		if round.ChallengerChose() {
+			return nil, ErrAlreadyMoved
+		}
+		round.ChallengerAttackPoint, round.ChallengerDefensePoint = &attack, &defense
+	} else {
+		if round.OpponentChose() {
+			return nil, ErrAlreadyMoved
+		}
+		round.OpponentAttackPoint, round.OpponentDefensePoint = &attack, &defense
+	}
+
+	if round.ChallengerChose() && round.OpponentChose() {
+		if err := s.resolveRound(tx, duel, round); err != nil {
+			return nil, ErrInternalError
+		}
+	} else if duel.IsChallenger(userID) {
+		if err := duelRoundRepoTx.SetChallengerChoice(round.ID, attack, defense); err != nil {
+			return nil, ErrInternalError
+		}
+	} else {
+		if err := duelRoundRepoTx.SetOpponentChoice(round.ID, attack, defense); err != nil {
+			return nil, ErrInternalError
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ErrInternalError
+	}
+
+	return s.reloadAndNotify(duel.ID)
+}
+
+// ResolveExpiredRounds picks random moves for players who missed their turn
+// and declines challenges nobody answered. It returns the number of duels touched.
+func (s *DuelService) ResolveExpiredRounds(ctx context.Context) (int, error) {
+	declined, err := s.duelRepo.DeclineStalePending(DuelChallengeTimeout)
+	if err != nil {
+		return 0, err
+	}
+	for _, duel := range declined {
+		s.notify(duel)
+	}
+
+	duelIDs, err := s.duelRoundRepo.FindExpiredDuelIDs()
+	if err != nil {
+		return len(declined), err
+	}
+
+	resolved := 0
+	for _, duelID := range duelIDs {
+		ok, err := s.resolveExpiredRound(ctx, duelID)
+		if err != nil {
+			fmt.Printf("[DuelService] Failed to resolve expired round of duel %s: %v\n", duelID, err)
+			continue
+		}
+		if ok {
+			resolved++
+			if _, err := s.reloadAndNotify(duelID); err != nil {
+				fmt.Printf("[DuelService] Failed to notify about duel %s: %v\n", duelID, err)
+			}
+		}
+	}
+
+	return len(declined) + resolved, nil
+}
+
+func (s *DuelService) resolveExpiredRound(ctx context.Context, duelID uuid.UUID) (bool, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	duel, err := repository.NewDuelRepository(tx).FindByIDForUpdate(duelID)
+	if err != nil {
+		return false, err
+	}
+	if duel.Status != domain.DuelStatusInProgress {
+		return false, nil
+	}
+
+	round, err := repository.NewDuelRoundRepository(tx).FindExpiredForUpdate(duel.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if !round.ChallengerChose() {
+		round.ChallengerAttackPoint, round.ChallengerDefensePoint = randomBodyPart(s.rng), randomBodyPart(s.rng)
+	}
+	if !round.OpponentChose() {
+		round.OpponentAttackPoint, round.OpponentDefensePoint = randomBodyPart(s.rng), randomBodyPart(s.rng)
+	}
+
+	if err := s.resolveRound(tx, duel, round); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *DuelService) resolveRound(tx *sqlx.Tx, duel *domain.Duel, round *domain.DuelRound) error {
+	challenger, err := findFighter(s.userRepo, duel.ChallengerID)
+	if err != nil {
+		return err
+	}
+	opponent, err := findFighter(s.userRepo, duel.OpponentID)
+	if err != nil {
+		return err
+	}
+	challenger, opponent = fighterOf(challenger), fighterOf(opponent)
+
+	challengerHit := duelHit(s.rng, challenger, opponent, *round.ChallengerAttackPoint, *round.OpponentDefensePoint)
+	opponentHit := duelHit(s.rng, opponent, challenger, *round.OpponentAttackPoint, *round.ChallengerDefensePoint)
+
+	round.ChallengerDamage, round.ChallengerCritical = challengerHit.Damage, challengerHit.Critical
+	round.OpponentDodged, round.OpponentShieldBlocked = challengerHit.Dodged, challengerHit.ShieldBlocked
+	round.OpponentDamage, round.OpponentCritical = opponentHit.Damage, opponentHit.Critical
+	round.ChallengerDodged, round.ChallengerShieldBlocked = opponentHit.Dodged, opponentHit.ShieldBlocked
+
+	round.ChallengerHp = calculateFinalHp(round.ChallengerHp, round.OpponentDamage)
+	round.OpponentHp = calculateFinalHp(round.OpponentHp, round.ChallengerDamage)
+
+	duelRoundRepoTx := repository.NewDuelRoundRepository(tx)
+	if err := duelRoundRepoTx.FinishRound(round); err != nil {
+		return err
+	}
+
+	ongoing := round.ChallengerHp > 0 && round.OpponentHp > 0
+
+	updateHp := s.userRepo.UpdateCurrentHpWithExt
+	if ongoing {
+		updateHp = s.userRepo.UpdateFightHpWithExt
+	}
+	if err := updateHp(tx, challenger.ID, round.ChallengerHp); err != nil {
+		return err
+	}
+	if err := updateHp(tx, opponent.ID, round.OpponentHp); err != nil {
+		return err
+	}
+
+	if ongoing {
+		return duelRoundRepoTx.Create(duel.ID, round.ChallengerHp, round.OpponentHp, DuelTurnTimeout)
+	}
+
+	if round.ChallengerHp == 0 {
+		d, err := defeat(tx, s.userRepo, s.locationRepo, challenger, duelDefeatPolicy())
+		if err != nil {
+			return err
+		}
+		duel.ChallengerRespawnLocationID, duel.ChallengerWeakenedUntil = &d.RespawnLocation.ID, d.WeakenedUntil
+	}
+	if round.OpponentHp == 0 {
+		d, err := defeat(tx, s.userRepo, s.locationRepo, opponent, duelDefeatPolicy())
+		if err != nil {
+			return err
+		}
+		duel.OpponentRespawnLocationID, duel.OpponentWeakenedUntil = &d.RespawnLocation.ID, d.WeakenedUntil
+	}
+
+	duelRepoTx := repository.NewDuelRepository(tx)
+	if err := duelRepoTx.RecordDefeats(duel); err != nil {
+		return err
+	}
+
+	return duelRepoTx.Finish(duel.ID, duelWinner(duel, round))
+}
+
+// duelDefeatPolicy is how a beaten duellist is treated: carried back and
+// weakened like a player beaten by a bot, but without losing gold or exp.
+func duelDefeatPolicy() domain.DefeatPolicy {
+	policy := domain.CurrentDefeatPolicy()
+	policy.GoldPenaltyPercent, policy.ExpPenaltyPercent = 0, 0
+	return policy
+}
+
+// duelWinner returns nil when both participants dropped to zero in the same round.
+func duelWinner(duel *domain.Duel, round *domain.DuelRound) *uuid.UUID {
+	switch {
+	case round.ChallengerHp > 0 && round.OpponentHp == 0:
+		return &duel.ChallengerID
+	case round.OpponentHp > 0 && round.ChallengerHp == 0:
+		return &duel.OpponentID
+	default:
+		return nil
+	}
+}
+
+func randomBodyPart(rng Rand) *domain.BodyPart {
+	part := domain.BodyParts[rng.Intn(len(domain.BodyParts))]
+	return &part
+}
+
+func (s *DuelService) checkAvailable(userID uuid.UUID, busyErr error) error {
+	if _, err := s.duelRepo.FindActiveByUserID(userID); err == nil {
+		return busyErr
+	} else if !errors.Is(err, repository.ErrDuelNotFound) {
+		return ErrInternalError
+	}
+
+	inFight, err := s.userRepo.InFight(userID)
+	if err != nil {
+		return ErrInternalError
+	}
+	if inFight {
+		if busyErr == ErrAlreadyInDuel {
+			return ErrUserInFight
+		}
+		return busyErr
+	}
+
+	return nil
+}
+
+func (s *DuelService) reloadAndNotify(duelID uuid.UUID) (*domain.Duel, error) {
+	duel, err := s.duelRepo.FindByID(duelID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	rounds, err := s.duelRoundRepo.FindByDuelID(duel.ID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	duel.Rounds = rounds
+
+	s.notify(duel)
+
+	return duel, nil
+}
+
+func (s *DuelService) notify(duel *domain.Duel) {
+	event := ws.DuelUpdateEvent(dto.DuelFromDomain(duel))
+
+	s.publisher.Publish(duel.ChallengerID, event)
+	s.publisher.Publish(duel.OpponentID, event)
+}
+
+// duelHit resolves one player's blow on the other.
+func duelHit(rng Rand, attacker, defender *domain.User, attackPoint, defensePoint domain.BodyPart) Hit {
+	return defaultDamagePipeline.Resolve(rng, Hit{
+		Attack:       attacker.Attack,
+		Defense:      defender.BlockDefense(),
+		Armour:       defender.Armour.At(attackPoint),
+		AttackPoint:  string(attackPoint),
+		DefensePoint: string(defensePoint),
+		Attacker:     attacker.CombatStats,
+		Defender:     defender.CombatStats,
+	})
+}
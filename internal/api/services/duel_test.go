@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+func createDuelTestUser(t *testing.T, db *sqlx.DB, locationID uuid.UUID) *domain.User {
+	user := &domain.User{
+		Username:   fmt.Sprintf("duelist%d", time.Now().UnixNano()),
+		Email:      fmt.Sprintf("duelist%d@example.com", time.Now().UnixNano()),
+		Password:   "password",
+		LocationID: locationID,
+		Attack:     10,
+		Defense:    2,
+		Hp:         100,
+		CurrentHp:  100,
+		Level:      1,
+	}
+	require.NoError(t, repository.NewUserRepository(db).Create(user))
+	return user
+}
+
+func TestDuelService(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Test database not initialized")
+	}
+
+	db := testDB.DB()
+	service := NewDuelService(db)
+	ctx := context.Background()
+
+	location := &domain.Location{
+		Name: fmt.Sprintf("Duel Location %d", time.Now().UnixNano()),
+		Slug: fmt.Sprintf("duel-location-%d", time.Now().UnixNano()),
+	}
+	require.NoError(t, repository.NewLocationRepository(db).Create(location))
+
+	t.Run("cannot challenge yourself", func(t *testing.T) {
+		user := createDuelTestUser(t, db, location.ID)
+
+		_, err := service.Challenge(ctx, user.ID, user.Username)
+		assert.Equal(t, ErrCannotDuelSelf, err)
+	})
+
+	t.Run("opponent must be in the same location", func(t *testing.T) {
+		other := &domain.Location{
+			Name: fmt.Sprintf("Other Location %d", time.Now().UnixNano()),
+			Slug: fmt.Sprintf("other-location-%d", time.Now().UnixNano()),
+		}
+		require.NoError(t, repository.NewLocationRepository(db).Create(other))
+
+		user := createDuelTestUser(t, db, location.ID)
+		opponent := createDuelTestUser(t, db, other.ID)
+
+		_, err := service.Challenge(ctx, user.ID, opponent.Username)
+		assert.Equal(t, ErrOpponentNotInLocation, err)
+	})
+
+	t.Run("only the opponent can accept", func(t *testing.T) {
+		user := createDuelTestUser(t, db, location.ID)
+		opponent := createDuelTestUser(t, db, location.ID)
+
+		duel, err := service.Challenge(ctx, user.ID, opponent.Username)
+		require.NoError(t, err)
+
+		_, err = service.Accept(ctx, user.ID, duel.ID)
+		assert.Equal(t, ErrDuelNotFound, err)
+
+		declined, err := service.Decline(ctx, user.ID, duel.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.DuelStatusDeclined, declined.Status)
+	})
+
+	t.Run("round resolves once both players moved", func(t *testing.T) {
+		user := createDuelTestUser(t, db, location.ID)
+		opponent := createDuelTestUser(t, db, location.ID)
+
+		duel, err := service.Challenge(ctx, user.ID, opponent.Username)
+		require.NoError(t, err)
+		assert.Equal(t, domain.DuelStatusPending, duel.Status)
+
+		_, err = service.Challenge(ctx, user.ID, opponent.Username)
+		assert.Equal(t, ErrAlreadyInDuel, err)
+
+		duel, err = service.Accept(ctx, opponent.ID, duel.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.DuelStatusInProgress, duel.Status)
+		require.Len(t, duel.Rounds, 1)
+
+		inFight, err := repository.NewUserRepository(db).InFight(user.ID)
+		require.NoError(t, err)
+		assert.True(t, inFight)
+
+		duel, err = service.Hit(ctx, user.ID, "HEAD", "CHEST")
+		require.NoError(t, err)
+		require.Len(t, duel.Rounds, 1)
+		assert.True(t, duel.Rounds[0].ChallengerChose())
+		assert.False(t, duel.Rounds[0].OpponentChose())
+
+		_, err = service.Hit(ctx, user.ID, "HEAD", "CHEST")
+		assert.Equal(t, ErrAlreadyMoved, err)
+
+		duel, err = service.Hit(ctx, opponent.ID, "LEGS", "HEAD")
+		require.NoError(t, err)
+		require.Len(t, duel.Rounds, 2)
+		assert.Equal(t, domain.RoundStatusFinished, duel.Rounds[1].Status)
+		assert.Equal(t, domain.RoundStatusInProgress, duel.Rounds[0].Status)
+		assert.Equal(t, duel.Rounds[1].ChallengerHp, duel.Rounds[0].ChallengerHp)
+		assert.Equal(t, duel.Rounds[1].OpponentHp, duel.Rounds[0].OpponentHp)
+	})
+}
+
+func TestDuelWinner(t *testing.T) {
+	duel := &domain.Duel{ChallengerID: uuid.New(), OpponentID: uuid.New()}
+
+	winner := duelWinner(duel, &domain.DuelRound{ChallengerHp: 5, OpponentHp: 0})
+	require.NotNil(t, winner)
+	assert.Equal(t, duel.ChallengerID, *winner)
+
+	winner = duelWinner(duel, &domain.DuelRound{ChallengerHp: 0, OpponentHp: 3})
+	require.NotNil(t, winner)
+	assert.Equal(t, duel.OpponentID, *winner)
+
+	assert.Nil(t, duelWinner(duel, &domain.DuelRound{}))
+}
+
+func TestDuelHit_SeededRandIsReproducible(t *testing.T) {
+	attacker := &domain.User{Attack: 12, CombatStats: domain.CombatStats{CritChance: 30, DodgeChance: 30}}
+	defender := &domain.User{Defense: 3, CombatStats: domain.CombatStats{CritChance: 30, DodgeChance: 30}}
+
+	roll := func(rng Rand) []Hit {
+		var hits []Hit
+		for i := 0; i < 20; i++ {
+			attackPoint, defensePoint := randomBodyPart(rng), randomBodyPart(rng)
+			hits = append(hits, duelHit(rng, attacker, defender, *attackPoint, *defensePoint))
+		}
+		return hits
+	}
+
+	assert.Equal(t, roll(NewRand(42)), roll(NewRand(42)))
+}
+
+func TestDuelDefeatPolicy(t *testing.T) {
+	policy := duelDefeatPolicy()
+	current := domain.CurrentDefeatPolicy()
+
+	assert.Zero(t, policy.GoldPenaltyPercent)
+	assert.Zero(t, policy.ExpPenaltyPercent)
+	assert.Equal(t, current.WeakenedFor, policy.WeakenedFor)
+	assert.Equal(t, current.WeakenedPercent, policy.WeakenedPercent)
+}
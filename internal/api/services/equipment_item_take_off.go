@@ -121,7 +121,8 @@ func (s *EquipmentItemTakeOffService) TakeOffEquipmentItem(ctx context.Context,
 			attack = attack - $2,
 			defense = defense - $3,
 			hp = hp - $4,
-			current_hp = current_hp - $4
+			current_hp = regenerated_hp(current_hp, hp, hp_changed_at, hp_regen_rate) - $4,
+			hp_changed_at = CURRENT_TIMESTAMP
 		WHERE id = $1 AND deleted_at IS NULL
 	`, fieldName)
 	_, err = tx.Exec(clearSlotQuery, userID, item.Attack, item.Defense, item.Hp)
@@ -179,7 +179,8 @@ func (s *EquipmentItemTakeOnService) TakeOnEquipmentItem(ctx context.Context, us
 				attack = attack - $2 + $5,
 				defense = defense - $3 + $6,
 				hp = hp - $4 + $7,
-				current_hp = LEAST(current_hp, hp - $4 + $7)
+				current_hp = LEAST(regenerated_hp(current_hp, hp, hp_changed_at, hp_regen_rate), hp - $4 + $7),
+				hp_changed_at = CURRENT_TIMESTAMP
 			WHERE id = $8 AND deleted_at IS NULL
 		`, fieldName)
 		_, err = tx.Exec(updateStatsQuery, itemID, oldItem.Attack, oldItem.Defense, oldItem.Hp, item.Attack, item.Defense, item.Hp, userID)
@@ -190,7 +191,8 @@ func (s *EquipmentItemTakeOnService) TakeOnEquipmentItem(ctx context.Context, us
 				attack = attack + $2,
 				defense = defense + $3,
 				hp = hp + $4,
-				current_hp = LEAST(current_hp, hp + $4)
+				current_hp = LEAST(regenerated_hp(current_hp, hp, hp_changed_at, hp_regen_rate), hp + $4),
+				hp_changed_at = CURRENT_TIMESTAMP
 			WHERE id = $5 AND deleted_at IS NULL
 		`, fieldName)
 		_, err = tx.Exec(updateStatsQuery, itemID, item.Attack, item.Defense, item.Hp, userID)
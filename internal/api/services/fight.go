@@ -3,31 +3,51 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
-	"math/rand"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/ws"
 	"moonshine/internal/domain"
 	"moonshine/internal/repository"
 )
 
 type FightService struct {
-	fightRepo *repository.FightRepository
-	botRepo   *repository.BotRepository
-	userRepo  *repository.UserRepository
-	roundRepo *repository.RoundRepository
-	db        *sqlx.DB
+	fightRepo         *repository.FightRepository
+	botRepo           *repository.BotRepository
+	userRepo          *repository.UserRepository
+	roundRepo         *repository.RoundRepository
+	equipmentItemRepo *repository.EquipmentItemRepository
+	locationRepo      *repository.LocationRepository
+	consumableRepo    *repository.ConsumableItemRepository
+	publisher         ws.Publisher
+	rng               Rand
+	db                *sqlx.DB
 }
 
 func NewFightService(db *sqlx.DB) *FightService {
+	return NewFightServiceWithRand(db, globalRand{})
+}
+
+// NewFightServiceWithRand builds a FightService whose damage rolls, drops and
+// bot choices all come from rng.
+func NewFightServiceWithRand(db *sqlx.DB, rng Rand) *FightService {
 	return &FightService{
-		fightRepo: repository.NewFightRepository(db),
-		botRepo:   repository.NewBotRepository(db),
-		userRepo:  repository.NewUserRepository(db),
-		roundRepo: repository.NewRoundRepository(db),
-		db:        db,
+		fightRepo:         repository.NewFightRepository(db),
+		botRepo:           repository.NewBotRepository(db),
+		userRepo:          repository.NewUserRepository(db),
+		roundRepo:         repository.NewRoundRepository(db),
+		equipmentItemRepo: repository.NewEquipmentItemRepository(db),
+		locationRepo:      repository.NewLocationRepository(db),
+		consumableRepo:    repository.NewConsumableItemRepository(db),
+		publisher:         ws.GetHub(),
+		rng:               rng,
+		db:                db,
 	}
 }
 
@@ -41,6 +61,9 @@ var ErrNoActiveFight = errors.New("no active fight")
 var ErrUserNotFound = errors.New("user not found")
 var ErrBotNotFound = errors.New("bot not found")
 var ErrInvalidBodyPart = errors.New("invalid body part")
+var ErrFightNotFound = errors.New("fight not found")
+var ErrInvalidFightOutcome = errors.New("invalid fight outcome")
+var ErrRoundAlreadyResolved = errors.New("round already resolved")
 
 func isValidBodyPart(part string) bool {
 	bodyPart := domain.BodyPart(part)
@@ -53,7 +76,7 @@ func isValidBodyPart(part string) bool {
 }
 
 func (s *FightService) GetCurrentFight(ctx context.Context, userID uuid.UUID) (*GetCurrentFightResult, error) {
-	user, err := s.userRepo.FindByID(userID)
+	user, err := findFighter(s.userRepo, userID)
 	if err != nil {
 		return nil, ErrUserNotFound
 	}
@@ -68,6 +91,7 @@ func (s *FightService) GetCurrentFight(ctx context.Context, userID uuid.UUID) (*
 		return nil, ErrNoActiveFight
 	}
 	fight.Rounds = rounds
+	fight.PlayerArmour = &user.Armour
 
 	bot, err := s.botRepo.FindByID(fight.BotID)
 	if err != nil {
@@ -81,6 +105,96 @@ func (s *FightService) GetCurrentFight(ctx context.Context, userID uuid.UUID) (*
 	}, nil
 }
 
+// GetFightHistory lists the user's finished fights, newest first. botSlug and
+// outcome are optional filters; outcome is matched case-insensitively.
+func (s *FightService) GetFightHistory(ctx context.Context, userID uuid.UUID, botSlug, outcome string, limit, offset int) ([]*domain.Fight, int, error) {
+	var filter repository.FightFilter
+
+	if botSlug != "" {
+		bot, err := s.botRepo.FindBySlug(botSlug)
+		if err != nil {
+			if errors.Is(err, repository.ErrBotNotFound) {
+				return nil, 0, ErrBotNotFound
+			}
+			return nil, 0, ErrInternalError
+		}
+		filter.BotID = &bot.ID
+	}
+
+	if outcome != "" {
+		filter.Outcome = domain.FightOutcome(strings.ToUpper(outcome))
+		switch filter.Outcome {
+		case domain.FightOutcomeWon, domain.FightOutcomeLost, domain.FightOutcomeFled:
+		default:
+			return nil, 0, ErrInvalidFightOutcome
+		}
+	}
+
+	fights, err := s.fightRepo.FindFinishedByUserID(userID, filter, limit, offset)
+	if err != nil {
+		return nil, 0, ErrInternalError
+	}
+
+	total, err := s.fightRepo.CountFinishedByUserID(userID, filter)
+	if err != nil {
+		return nil, 0, ErrInternalError
+	}
+
+	bots := make(map[uuid.UUID]*domain.Bot)
+	for _, fight := range fights {
+		bot, ok := bots[fight.BotID]
+		if !ok {
+			bot, err = s.botRepo.FindByID(fight.BotID)
+			if err != nil && !errors.Is(err, repository.ErrBotNotFound) {
+				return nil, 0, ErrInternalError
+			}
+			bots[fight.BotID] = bot
+		}
+		fight.Bot = bot
+	}
+
+	return fights, total, nil
+}
+
+// GetFight loads one of the user's finished fights with every round, so the
+// client can replay it.
+func (s *FightService) GetFight(ctx context.Context, userID, fightID uuid.UUID) (*domain.Fight, error) {
+	fight, err := s.fightRepo.FindFinishedByIDAndUserID(fightID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrFightNotFound) {
+			return nil, ErrFightNotFound
+		}
+		return nil, ErrInternalError
+	}
+
+	rounds, err := s.roundRepo.FindByFightID(fight.ID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	fight.Rounds = rounds
+
+	fight.Bot, err = s.botRepo.FindByID(fight.BotID)
+	if err != nil && !errors.Is(err, repository.ErrBotNotFound) {
+		return nil, ErrInternalError
+	}
+
+	if fight.DroppedItemID != nil {
+		fight.DroppedItem, err = s.equipmentItemRepo.FindByID(*fight.DroppedItemID)
+		if err != nil && !errors.Is(err, repository.ErrEquipmentItemNotFound) {
+			return nil, ErrInternalError
+		}
+	}
+
+	if fight.DroppedConsumableID != nil {
+		fight.DroppedConsumable, err = s.consumableRepo.FindByID(*fight.DroppedConsumableID)
+		if err != nil && !errors.Is(err, repository.ErrConsumableItemNotFound) {
+			return nil, ErrInternalError
+		}
+	}
+
+	return fight, nil
+}
+
 func (s *FightService) Hit(ctx context.Context, userID uuid.UUID, playerAttackPoint, playerDefensePoint string) (*GetCurrentFightResult, error) {
 	if !isValidBodyPart(playerAttackPoint) {
 		return nil, ErrInvalidBodyPart
@@ -94,12 +208,12 @@ func (s *FightService) Hit(ctx context.Context, userID uuid.UUID, playerAttackPo
 		return nil, ErrNoActiveFight
 	}
 
-	user, err := s.userRepo.FindByID(userID)
+	user, err := findFighter(s.userRepo, userID)
 	if err != nil {
 		return nil, ErrUserNotFound
 	}
 
-	bot, err := s.botRepo.FindByID(fight.BotID)
+	bot, err := findBot(s.botRepo, fight.BotID)
 	if err != nil {
 		return nil, ErrBotNotFound
 	}
@@ -113,16 +227,23 @@ func (s *FightService) Hit(ctx context.Context, userID uuid.UUID, playerAttackPo
 		return nil, ErrInternalError
 	}
 
-	currentRound := rounds[0]
-
-	botAttackPoint := string(domain.BodyParts[rand.Intn(len(domain.BodyParts))])
-	botDefensePoint := string(domain.BodyParts[rand.Intn(len(domain.BodyParts))])
+	return s.resolveRound(ctx, fight, user, bot, rounds, playerAttackPoint, playerDefensePoint, false)
+}
 
-	playerDmg := calculateDamage(user.Attack, bot.Defense, playerAttackPoint, botDefensePoint)
-	botDmg := calculateDamage(bot.Attack, user.Defense, botAttackPoint, playerDefensePoint)
+// resolveRound plays out the current round (rounds[0]) with the player's
+// choices and finishes the fight if someone dropped to 0 HP. timedOut marks a
+// round the player never moved in.
+func (s *FightService) resolveRound(ctx context.Context, fight *domain.Fight, user *domain.User, bot *domain.Bot,
+	rounds []*domain.Round, playerAttackPoint, playerDefensePoint string, timedOut bool) (*GetCurrentFightResult, error) {
+	userID := user.ID
+	currentRound := rounds[0]
+	var levelUp *ws.Event
 
-	finalPlayerHp := calculateFinalHp(currentRound.PlayerHp, botDmg)
-	finalBotHp := calculateFinalHp(currentRound.BotHp, playerDmg)
+	res := playRound(s.rng, fighterOf(user), bot, rounds, currentRound.PlayerHp, currentRound.BotHp, playerAttackPoint, playerDefensePoint)
+	botAttackPoint := string(res.BotAttack)
+	botDefensePoint := string(res.BotDefense)
+	playerDmg, botDmg := res.PlayerDmg, res.BotDmg
+	finalPlayerHp, finalBotHp := res.PlayerHp, res.BotHp
 
 	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
@@ -133,32 +254,137 @@ func (s *FightService) Hit(ctx context.Context, userID uuid.UUID, playerAttackPo
 	roundRepoTx := repository.NewRoundRepository(tx)
 	fightRepoTx := repository.NewFightRepository(tx)
 
+	if err = roundRepoTx.LockInProgress(currentRound.ID); err != nil {
+		if errors.Is(err, repository.ErrRoundNotInProgress) {
+			return nil, ErrRoundAlreadyResolved
+		}
+		return nil, ErrInternalError
+	}
+
+	if timedOut {
+		if err = roundRepoTx.MarkTimedOut(currentRound.ID); err != nil {
+			return nil, ErrInternalError
+		}
+	}
+
+	if res.Ability != nil {
+		if err = roundRepoTx.SetBotAbility(currentRound.ID, *res.Ability); err != nil {
+			return nil, ErrInternalError
+		}
+	}
+
+	if err = roundRepoTx.SetHitFlags(currentRound.ID, res.Critical, res.Dodged, res.ShieldBlocked); err != nil {
+		return nil, ErrInternalError
+	}
+
+	if err = roundRepoTx.SetBotHitFlags(currentRound.ID, res.BotCritical, res.BotDodged, res.BotShieldBlocked); err != nil {
+		return nil, ErrInternalError
+	}
+
 	if err = roundRepoTx.FinishRound(currentRound.ID, botAttackPoint, botDefensePoint, playerAttackPoint, playerDefensePoint,
 		playerDmg, botDmg, finalPlayerHp, finalBotHp); err != nil {
 		return nil, ErrInternalError
 	}
 
 	if finalPlayerHp == 0 || finalBotHp == 0 {
-		fight.DroppedGold = calculateDroppedGold(bot.Level)
-		fight.Exp = calculateExp(finalBotHp, user.Level, bot.Level)
+		fight.DroppedGold = calculateDroppedGold(s.rng, bot.Level)
+		progression := domain.CurrentProgression()
+		fight.Exp = calculateExp(progression, finalBotHp, user.Level, bot.Level)
 
-		lvl := calculateLvl(user.Level, user.Exp, fight.Exp)
+		lvl := calculateLvl(progression, user.Level, user.Exp, fight.Exp)
+		rewards := calculateLevelRewards(progression, user.Level, lvl)
+		freeStats := rewards.FreeStats
 
 		if lvl > user.Level {
+			if err = s.userRepo.AddLevelStatsWithExt(tx, userID, rewards.Hp, rewards.Attack, rewards.Defense); err != nil {
+				return nil, ErrInternalError
+			}
+			user.Hp += rewards.Hp
+			user.Attack += rewards.Attack
+			user.Defense += rewards.Defense
+
+			event := ws.LevelUpEvent(lvl, user.FreeStats+freeStats)
+			levelUp = &event
 			user.CurrentHp = user.Hp
 		} else {
 			user.CurrentHp = finalPlayerHp
 		}
+		user.FreeStats += freeStats
 
-		if err = s.userRepo.UpdateWithExt(tx, userID, fight.DroppedGold, fight.Exp, lvl, user.CurrentHp); err != nil {
+		if err = s.userRepo.UpdateWithExt(tx, userID, fight.DroppedGold, fight.Exp, lvl, user.CurrentHp, freeStats); err != nil {
 			return nil, ErrInternalError
 		}
 
-		finished, err := fightRepoTx.Finish(fight.ID, fight.DroppedGold, fight.Exp)
+		var droppedItem *domain.EquipmentItem
+		var droppedConsumable *domain.ConsumableItem
+		if finalBotHp == 0 {
+			loot, err := s.botRepo.FindLootByBotID(bot.ID)
+			if err != nil {
+				return nil, ErrInternalError
+			}
+
+			itemID := rollLoot(s.rng, bot, loot, user.Level)
+			if bot.IsBoss {
+				if err = repository.NewBossKillRepository(tx).Record(userID, bot.ID); err != nil {
+					return nil, ErrInternalError
+				}
+			}
+
+			if itemID != nil {
+				droppedItem, err = s.equipmentItemRepo.FindByID(*itemID)
+				if err != nil {
+					return nil, ErrInternalError
+				}
+
+				inventoryRepoTx := repository.NewInventoryRepository(tx)
+				if err = inventoryRepoTx.Create(&domain.Inventory{UserID: userID, EquipmentItemID: droppedItem.ID}); err != nil {
+					return nil, ErrInternalError
+				}
+				fight.DroppedItemID = &droppedItem.ID
+			}
+
+			consumableRepoTx := repository.NewConsumableItemRepository(tx)
+			drops, err := consumableRepoTx.FindDropsByBotID(bot.ID)
+			if err != nil {
+				return nil, ErrInternalError
+			}
+
+			if consumableID := rollConsumableDrop(s.rng, drops); consumableID != nil {
+				if droppedConsumable, err = consumableRepoTx.FindByID(*consumableID); err != nil {
+					return nil, ErrInternalError
+				}
+
+				inventory := &domain.InventoryConsumable{UserID: userID, ConsumableItemID: droppedConsumable.ID}
+				if err = repository.NewInventoryRepository(tx).CreateConsumable(inventory); err != nil {
+					return nil, ErrInternalError
+				}
+
+				if err = fightRepoTx.SetDroppedConsumable(fight.ID, droppedConsumable.ID); err != nil {
+					return nil, ErrInternalError
+				}
+			}
+		}
+
+		finished, err := fightRepoTx.Finish(fight.ID, fight.DroppedGold, fight.Exp, fight.DroppedItemID)
 		if err != nil {
 			return nil, ErrInternalError
 		}
+
+		if err = releaseBotInstance(tx, fight.ID, finalBotHp); err != nil {
+			return nil, ErrInternalError
+		}
 		fight = finished
+		fight.DroppedItem = droppedItem
+		fight.DroppedConsumable = droppedConsumable
+		fight.Outcome = domain.FightOutcomeLost
+		if finalBotHp == 0 {
+			fight.Outcome = domain.FightOutcomeWon
+		} else {
+			user.Gold += fight.DroppedGold
+			if err = defeatInFight(tx, s.userRepo, s.locationRepo, fight, user); err != nil {
+				return nil, ErrInternalError
+			}
+		}
 	} else {
 		if err = roundRepoTx.Create(fight.ID, finalPlayerHp, finalBotHp); err != nil {
 			return nil, ErrInternalError
@@ -170,11 +396,17 @@ func (s *FightService) Hit(ctx context.Context, userID uuid.UUID, playerAttackPo
 		return nil, ErrInternalError
 	}
 	fight.Rounds = updatedRounds
+	fight.PlayerArmour = &user.Armour
 
 	if err = tx.Commit(); err != nil {
 		return nil, ErrInternalError
 	}
 
+	s.publisher.Publish(userID, ws.FightRoundEvent(dto.FightFromDomain(fight)))
+	if levelUp != nil {
+		s.publisher.Publish(userID, *levelUp)
+	}
+
 	return &GetCurrentFightResult{
 		User:  user,
 		Bot:   bot,
@@ -182,17 +414,409 @@ func (s *FightService) Hit(ctx context.Context, userID uuid.UUID, playerAttackPo
 	}, nil
 }
 
-func calculateDamage(attack, defense uint, attackPoint, defensePoint string) uint {
-	var base int
+type FleeResult struct {
+	*GetCurrentFightResult
+	Fled bool
+}
+
+// Flee tries to leave the current fight. On success the fight is closed as
+// FLED with no rewards; on failure the bot lands a free hit on the undefended
+// player, which may end the fight as a loss.
+func (s *FightService) Flee(ctx context.Context, userID uuid.UUID) (*FleeResult, error) {
+	fight, err := s.fightRepo.FindActiveByUserID(userID)
+	if err != nil {
+		return nil, ErrNoActiveFight
+	}
+
+	user, err := findFighter(s.userRepo, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	bot, err := findBot(s.botRepo, fight.BotID)
+	if err != nil {
+		return nil, ErrBotNotFound
+	}
+
+	rounds, err := s.roundRepo.FindByFightID(fight.ID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	if len(rounds) == 0 {
+		return nil, ErrInternalError
+	}
+
+	currentRound := rounds[0]
+	fled := s.rng.Float64() < fleeChance(user.Level, bot.Level)
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	defer tx.Rollback()
+
+	roundRepoTx := repository.NewRoundRepository(tx)
+	fightRepoTx := repository.NewFightRepository(tx)
+
+	if err = roundRepoTx.LockInProgress(currentRound.ID); err != nil {
+		if errors.Is(err, repository.ErrRoundNotInProgress) {
+			return nil, ErrRoundAlreadyResolved
+		}
+		return nil, ErrInternalError
+	}
+
+	if fled {
+		if err = roundRepoTx.Abandon(currentRound.ID); err != nil {
+			return nil, ErrInternalError
+		}
+
+		user.CurrentHp = currentRound.PlayerHp
+		if err = s.userRepo.UpdateCurrentHpWithExt(tx, userID, user.CurrentHp); err != nil {
+			return nil, ErrInternalError
+		}
+
+		if fight, err = fightRepoTx.Flee(fight.ID); err != nil {
+			return nil, ErrInternalError
+		}
+
+		if err = releaseBotInstance(tx, fight.ID, currentRound.BotHp); err != nil {
+			return nil, ErrInternalError
+		}
+		fight.Outcome = domain.FightOutcomeFled
+	} else if fight, err = s.takeFreeHit(tx, fight, user, bot, rounds, currentRound.PlayerHp); err != nil {
+		return nil, ErrInternalError
+	}
+
+	updatedRounds, err := roundRepoTx.FindByFightID(fight.ID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	fight.Rounds = updatedRounds
+	fight.PlayerArmour = &user.Armour
+
+	if err = tx.Commit(); err != nil {
+		return nil, ErrInternalError
+	}
+
+	s.publisher.Publish(userID, ws.FightRoundEvent(dto.FightFromDomain(fight)))
+
+	return &FleeResult{
+		GetCurrentFightResult: &GetCurrentFightResult{
+			User:  user,
+			Bot:   bot,
+			Fight: fight,
+		},
+		Fled: fled,
+	}, nil
+}
+
+type UseItemResult struct {
+	*GetCurrentFightResult
+	Item *domain.ConsumableItem
+	Buff *domain.UserBuff
+}
+
+// UseItem spends the player's turn on a consumable from their inventory: it
+// heals them and starts its buff, then the bot strikes the undefended player
+// as it does after a failed escape.
+func (s *FightService) UseItem(ctx context.Context, userID, inventoryID uuid.UUID) (*UseItemResult, error) {
+	fight, err := s.fightRepo.FindActiveByUserID(userID)
+	if err != nil {
+		return nil, ErrNoActiveFight
+	}
+
+	user, err := findFighter(s.userRepo, userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	bot, err := findBot(s.botRepo, fight.BotID)
+	if err != nil {
+		return nil, ErrBotNotFound
+	}
+
+	rounds, err := s.roundRepo.FindByFightID(fight.ID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	if len(rounds) == 0 {
+		return nil, ErrInternalError
+	}
+
+	currentRound := rounds[0]
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	defer tx.Rollback()
+
+	roundRepoTx := repository.NewRoundRepository(tx)
+
+	if err = roundRepoTx.LockInProgress(currentRound.ID); err != nil {
+		if errors.Is(err, repository.ErrRoundNotInProgress) {
+			return nil, ErrRoundAlreadyResolved
+		}
+		return nil, ErrInternalError
+	}
+
+	item, err := consume(tx, user, inventoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buff *domain.UserBuff
+	if item.HasBuff() {
+		if buff, err = s.userRepo.AddBuffWithExt(tx, userID, item); err != nil {
+			return nil, ErrInternalError
+		}
+		user.Buffs = append(user.Buffs, buff)
+	}
+
+	if err = roundRepoTx.SetConsumable(currentRound.ID, item.ID); err != nil {
+		return nil, ErrInternalError
+	}
+
+	playerHp := item.Heal(currentRound.PlayerHp, user.Hp)
+	if fight, err = s.takeFreeHit(tx, fight, user, bot, rounds, playerHp); err != nil {
+		return nil, ErrInternalError
+	}
+
+	updatedRounds, err := roundRepoTx.FindByFightID(fight.ID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	fight.Rounds = updatedRounds
+	fight.PlayerArmour = &user.Armour
+
+	if err = tx.Commit(); err != nil {
+		return nil, ErrInternalError
+	}
+
+	s.publisher.Publish(userID, ws.FightRoundEvent(dto.FightFromDomain(fight)))
+
+	return &UseItemResult{
+		GetCurrentFightResult: &GetCurrentFightResult{
+			User:  user,
+			Bot:   bot,
+			Fight: fight,
+		},
+		Item: item,
+		Buff: buff,
+	}, nil
+}
+
+// takeFreeHit closes the current round with only the bot striking the
+// undefended player, who starts it with playerHp. If they drop to 0 HP the
+// fight is lost, otherwise the next round begins. It returns the fight as it
+// stands afterwards.
+func (s *FightService) takeFreeHit(tx repository.ExtHandle, fight *domain.Fight, user *domain.User, bot *domain.Bot,
+	rounds []*domain.Round, playerHp uint) (*domain.Fight, error) {
+	currentRound := rounds[0]
+	roundRepoTx := repository.NewRoundRepository(tx)
+
+	fighter := bot.WithPhase(bot.PhaseAt(currentRound.BotHp))
+	botAttack, _ := botStrategyFor(fighter).Choose(rounds, s.rng.Intn)
+	_, botHit := botStrike(s.rng, fighter, fighterOf(user), nil, botAttack, "")
+	finalPlayerHp := calculateFinalHp(playerHp, botHit.Damage)
+
+	if err := roundRepoTx.FinishFreeHit(currentRound.ID, string(botAttack), botHit.Damage, finalPlayerHp); err != nil {
+		return nil, err
+	}
+
+	if err := roundRepoTx.SetHitFlags(currentRound.ID, false, botHit.Dodged, botHit.ShieldBlocked); err != nil {
+		return nil, err
+	}
+
+	if err := roundRepoTx.SetBotHitFlags(currentRound.ID, botHit.Critical, false, false); err != nil {
+		return nil, err
+	}
+
+	if finalPlayerHp > 0 {
+		return fight, roundRepoTx.Create(fight.ID, finalPlayerHp, currentRound.BotHp)
+	}
+
+	user.CurrentHp = 0
+	if err := s.userRepo.UpdateCurrentHpWithExt(tx, user.ID, user.CurrentHp); err != nil {
+		return nil, err
+	}
+
+	finished, err := repository.NewFightRepository(tx).Finish(fight.ID, 0, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = releaseBotInstance(tx, finished.ID, currentRound.BotHp); err != nil {
+		return nil, err
+	}
+	finished.Outcome = domain.FightOutcomeLost
+
+	if err = defeatInFight(tx, s.userRepo, s.locationRepo, finished, user); err != nil {
+		return nil, err
+	}
+
+	return finished, nil
+}
+
+// ResolveIdleRounds plays out rounds the player left hanging for longer than
+// timeout with random choices on their behalf. Once maxMissed rounds in a row
+// went unanswered the player forfeits the fight. It returns how many fights
+// were touched.
+func (s *FightService) ResolveIdleRounds(ctx context.Context, timeout time.Duration, maxMissed int) (int, error) {
+	fightIDs, err := s.roundRepo.FindIdleFightIDs(timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	resolved := 0
+	for _, fightID := range fightIDs {
+		err := s.resolveIdleFight(ctx, fightID, maxMissed)
+		if err != nil {
+			if errors.Is(err, ErrRoundAlreadyResolved) {
+				continue
+			}
+			fmt.Printf("[FightService] Failed to resolve idle fight %s: %v\n", fightID, err)
+			continue
+		}
+		resolved++
+	}
+
+	return resolved, nil
+}
+
+func (s *FightService) resolveIdleFight(ctx context.Context, fightID uuid.UUID, maxMissed int) error {
+	fight, err := s.fightRepo.FindByID(fightID)
+	if err != nil {
+		return err
+	}
+
+	user, err := findFighter(s.userRepo, fight.UserID)
+	if err != nil {
+		return err
+	}
+
+	bot, err := findBot(s.botRepo, fight.BotID)
+	if err != nil {
+		return err
+	}
+
+	rounds, err := s.roundRepo.FindByFightID(fight.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(rounds) == 0 {
+		return ErrInternalError
+	}
+
+	if missedRounds(rounds)+1 >= maxMissed {
+		return s.forfeit(ctx, fight, rounds[0])
+	}
+
+	attack, defense := randomStrategy{}.Choose(rounds, s.rng.Intn)
+	_, err = s.resolveRound(ctx, fight, user, bot, rounds, string(attack), string(defense), true)
+	return err
+}
+
+// forfeit ends the fight as a loss without rewards. The player keeps the HP
+// they had left.
+func (s *FightService) forfeit(ctx context.Context, fight *domain.Fight, currentRound *domain.Round) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	roundRepoTx := repository.NewRoundRepository(tx)
+
+	if err = roundRepoTx.LockInProgress(currentRound.ID); err != nil {
+		if errors.Is(err, repository.ErrRoundNotInProgress) {
+			return ErrRoundAlreadyResolved
+		}
+		return err
+	}
+
+	if err = roundRepoTx.MarkTimedOut(currentRound.ID); err != nil {
+		return err
+	}
+
+	if err = roundRepoTx.Abandon(currentRound.ID); err != nil {
+		return err
+	}
+
+	if err = s.userRepo.UpdateCurrentHpWithExt(tx, fight.UserID, currentRound.PlayerHp); err != nil {
+		return err
+	}
+
+	finished, err := repository.NewFightRepository(tx).Finish(fight.ID, 0, 0, nil)
+	if err != nil {
+		return err
+	}
+	finished.Outcome = domain.FightOutcomeLost
+
+	if err = releaseBotInstance(tx, fight.ID, currentRound.BotHp); err != nil {
+		return err
+	}
+
+	if finished.Rounds, err = roundRepoTx.FindByFightID(fight.ID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	s.publisher.Publish(fight.UserID, ws.FightRoundEvent(dto.FightFromDomain(finished)))
+
+	return nil
+}
+
+// releaseBotInstance frees the bot instance a finished fight was holding: it
+// dies and starts its respawn timer at 0 HP, otherwise it keeps what is left.
+func releaseBotInstance(tx repository.ExtHandle, fightID uuid.UUID, botHp uint) error {
+	instanceRepo := repository.NewBotInstanceRepository(tx)
+	if botHp == 0 {
+		return instanceRepo.KillByFightID(fightID)
+	}
+	return instanceRepo.ReleaseByFightID(fightID, botHp)
+}
+
+// missedRounds counts the finished rounds in a row, newest first, that were
+// resolved on the player's behalf.
+func missedRounds(rounds []*domain.Round) int {
+	missed := 0
+	for _, r := range rounds {
+		if r.Status != domain.RoundStatusFinished {
+			continue
+		}
+		if !r.TimedOut {
+			break
+		}
+		missed++
+	}
+	return missed
+}
+
+// fleeChance starts at even odds against a bot of the same level and moves by
+// 10% per level of difference, never dropping below 10% or rising above 90%.
+func fleeChance(playerLvl, botLvl uint) float64 {
+	chance := 0.5 + float64(int(playerLvl)-int(botLvl))*0.1
+	return math.Max(0.1, math.Min(0.9, chance))
+}
+
+// calculateDamage rolls a hit on attackPoint. The armour covering that part
+// always soaks some of it; defense only counts when the part was blocked.
+func calculateDamage(rng Rand, attack, defense, armour uint, attackPoint, defensePoint string) uint {
+	base := int(attack) - int(armour)
 	if attackPoint == defensePoint {
-		base = int(attack) - int(defense)
-	} else {
-		base = int(attack)
+		base -= int(defense)
 	}
 	if base <= 0 {
 		return 0
 	}
-	mult := 0.9 + rand.Float64()*0.2
+	mult := 0.9 + rng.Float64()*0.2
 	dmg := int(math.Round(float64(base) * mult))
 	if dmg < 0 {
 		return 0
@@ -211,72 +835,112 @@ func calculateFinalHp(currentHp, damage uint) uint {
 	}
 }
 
-func calculateDroppedGold(botLvl uint) uint {
+func calculateDroppedGold(rng Rand, botLvl uint) uint {
 	limitDroppedGold := botLvl * 5
 
-	if rand.Intn(3) == 1 {
-		return uint(rand.Intn(int(limitDroppedGold)) + 1)
+	if rng.Intn(3) == 1 {
+		return uint(rng.Intn(int(limitDroppedGold)) + 1)
 	}
 
 	return 0
 }
 
-func calculateExp(botFinalHp, playerLvl, botLvl uint) uint {
-	if botFinalHp > 0 || playerLvl >= 20 {
+// rollLoot picks what a beaten bot drops. Bosses always drop from their loot
+// table; other bots only one time in four.
+func rollLoot(rng Rand, bot *domain.Bot, loot []*domain.BotLoot, playerLvl uint) *uuid.UUID {
+	if bot.IsBoss {
+		return pickLoot(loot, playerLvl, rng.Intn)
+	}
+	return calculateDroppedItem(rng, loot, playerLvl)
+}
+
+func calculateDroppedItem(rng Rand, loot []*domain.BotLoot, playerLvl uint) *uuid.UUID {
+	if rng.Intn(4) != 1 {
+		return nil
+	}
+
+	return pickLoot(loot, playerLvl, rng.Intn)
+}
+
+func pickLoot(loot []*domain.BotLoot, playerLvl uint, intn func(int) int) *uuid.UUID {
+	total := 0
+	for _, l := range loot {
+		if l.AvailableFor(playerLvl) {
+			total += int(l.Weight)
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	roll := intn(total)
+	for _, l := range loot {
+		if !l.AvailableFor(playerLvl) {
+			continue
+		}
+		roll -= int(l.Weight)
+		if roll < 0 {
+			id := l.EquipmentItemID
+			return &id
+		}
+	}
+
+	return nil
+}
+
+// rollConsumableDrop rolls each of the bot's consumable drops in turn and
+// returns the first one that comes up; a bot drops at most one consumable.
+func rollConsumableDrop(rng Rand, drops []*domain.BotConsumableDrop) *uuid.UUID {
+	for _, drop := range drops {
+		if uint(rng.Intn(100)) < drop.Chance {
+			id := drop.ConsumableItemID
+			return &id
+		}
+	}
+	return nil
+}
+
+func calculateExp(p *domain.Progression, botFinalHp, playerLvl, botLvl uint) uint {
+	if botFinalHp > 0 || playerLvl >= p.MaxLevel {
 		return 0
 	}
 
-	nextLevel := playerLvl + 1
-	requiredExp, exists := domain.LevelMatrix[nextLevel]
+	requiredExp, exists := p.RequiredExp(playerLvl + 1)
 	if !exists {
 		return 0
 	}
 
-	bots := botsToLevel(playerLvl)
+	bots := botsToLevel(p, playerLvl)
 	baseExp := float64(requiredExp) / float64(bots)
-	mod := levelModifier(playerLvl, botLvl)
+	mod := p.ExpModifier(playerLvl, botLvl)
 
 	return uint(baseExp * mod)
 }
 
-func botsToLevel(playerLvl uint) uint {
-	return uint(5 * math.Pow(1.6, float64(playerLvl-1)))
-}
-
-func levelModifier(playerLvl, botLvl uint) float64 {
-	diff := int(botLvl) - int(playerLvl)
-
-	switch {
-	case diff == 0:
-		return 1.0
-	case diff > 0:
-		return 1.0 + float64(diff)*0.25
-	default:
-		return 1.0 / (1.0 + float64(-diff)*0.5)
-	}
+func botsToLevel(p *domain.Progression, playerLvl uint) uint {
+	return p.BotsToLevelFrom(playerLvl)
 }
 
-func calculateLvl(playerLvl, currentExp, gotExp uint) uint {
+func calculateLvl(p *domain.Progression, playerLvl, currentExp, gotExp uint) uint {
 	newExp := currentExp + gotExp
 	newLevel := playerLvl
 
-	for {
-		nextLevel := newLevel + 1
-		if nextLevel > 20 {
-			break
-		}
-
-		requiredExp, exists := domain.LevelMatrix[nextLevel]
-		if !exists {
-			break
-		}
-
-		if newExp >= requiredExp {
-			newLevel = nextLevel
-		} else {
+	for newLevel < p.MaxLevel {
+		requiredExp, exists := p.RequiredExp(newLevel + 1)
+		if !exists || newExp < requiredExp {
 			break
 		}
+		newLevel++
 	}
 
 	return newLevel
 }
+
+// calculateLevelRewards sums the free points and stats granted for climbing
+// from oldLvl to newLvl.
+func calculateLevelRewards(p *domain.Progression, oldLvl, newLvl uint) domain.LevelReward {
+	if newLvl <= oldLvl {
+		return domain.LevelReward{}
+	}
+	return p.RewardsBetween(oldLvl, newLvl)
+}
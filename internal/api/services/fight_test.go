@@ -273,3 +273,194 @@ func TestFightService_Hit(t *testing.T) {
 		assert.Equal(t, ErrNoActiveFight, err)
 	})
 }
+
+func TestPickLoot(t *testing.T) {
+	maxLevel := uint(3)
+	low := &domain.BotLoot{EquipmentItemID: uuid.New(), Weight: 1, MinLevel: 1, MaxLevel: &maxLevel}
+	high := &domain.BotLoot{EquipmentItemID: uuid.New(), Weight: 3, MinLevel: 2}
+	loot := []*domain.BotLoot{low, high}
+
+	t.Run("empty table drops nothing", func(t *testing.T) {
+		assert.Nil(t, pickLoot(nil, 1, func(int) int { return 0 }))
+	})
+
+	t.Run("level bounds filter entries", func(t *testing.T) {
+		id := pickLoot(loot, 1, func(n int) int {
+			assert.Equal(t, 1, n)
+			return 0
+		})
+		require.NotNil(t, id)
+		assert.Equal(t, low.EquipmentItemID, *id)
+
+		id = pickLoot(loot, 5, func(n int) int {
+			assert.Equal(t, 3, n)
+			return 2
+		})
+		require.NotNil(t, id)
+		assert.Equal(t, high.EquipmentItemID, *id)
+	})
+
+	t.Run("roll respects weights", func(t *testing.T) {
+		id := pickLoot(loot, 2, func(int) int { return 0 })
+		require.NotNil(t, id)
+		assert.Equal(t, low.EquipmentItemID, *id)
+
+		id = pickLoot(loot, 2, func(int) int { return 1 })
+		require.NotNil(t, id)
+		assert.Equal(t, high.EquipmentItemID, *id)
+	})
+}
+
+func TestFightService_Flee(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Test database not initialized")
+	}
+
+	db := testDB.DB()
+	service := NewFightService(db)
+
+	t.Run("fight is either fled or the bot gets a free hit", func(t *testing.T) {
+		_, user, _, fight, err := setupFightTestData(db)
+		require.NoError(t, err)
+
+		result, err := service.Flee(context.Background(), user.ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, result.Fight.Rounds)
+
+		if result.Fled {
+			assert.Equal(t, domain.FightStatusFled, result.Fight.Status)
+			assert.Equal(t, domain.FightOutcomeFled, result.Fight.Outcome)
+
+			_, err = service.GetCurrentFight(context.Background(), user.ID)
+			assert.Equal(t, ErrNoActiveFight, err)
+
+			history, total, err := service.GetFightHistory(context.Background(), user.ID, "", "fled", 10, 0)
+			require.NoError(t, err)
+			assert.Equal(t, 1, total)
+			assert.Equal(t, fight.ID, history[0].ID)
+			return
+		}
+
+		freeHit := result.Fight.Rounds[len(result.Fight.Rounds)-1]
+		assert.Equal(t, domain.RoundStatusFinished, freeHit.Status)
+		assert.NotNil(t, freeHit.BotAttackPoint)
+		assert.Nil(t, freeHit.PlayerAttackPoint)
+		assert.Equal(t, uint(0), freeHit.PlayerDamage)
+	})
+
+	t.Run("no active fight", func(t *testing.T) {
+		_, user, _, fight, err := setupFightTestData(db)
+		require.NoError(t, err)
+		_, err = repository.NewFightRepository(db).Finish(fight.ID, 0, 0, nil)
+		require.NoError(t, err)
+
+		_, err = service.Flee(context.Background(), user.ID)
+		assert.Equal(t, ErrNoActiveFight, err)
+	})
+}
+
+func TestFleeChance(t *testing.T) {
+	assert.InDelta(t, 0.5, fleeChance(3, 3), 1e-9)
+	assert.InDelta(t, 0.7, fleeChance(5, 3), 1e-9)
+	assert.InDelta(t, 0.3, fleeChance(3, 5), 1e-9)
+	assert.InDelta(t, 0.9, fleeChance(20, 1), 1e-9)
+	assert.InDelta(t, 0.1, fleeChance(1, 20), 1e-9)
+}
+
+func TestCalculateLevelRewards(t *testing.T) {
+	p, err := domain.ParseProgression([]byte(`{
+		"version": 1,
+		"maxLevel": 3,
+		"levels": [
+			{"level": 1, "exp": 0},
+			{"level": 2, "exp": 100, "freeStats": 3, "hp": 10, "attack": 1},
+			{"level": 3, "exp": 300, "freeStats": 3, "hp": 15, "defense": 2}
+		],
+		"botsToLevel": {"base": 1, "growth": 1}
+	}`))
+	require.NoError(t, err)
+
+	lvl := calculateLvl(p, 1, 50, 300)
+	require.Equal(t, uint(3), lvl)
+	assert.Equal(t, domain.LevelReward{FreeStats: 6, Hp: 25, Attack: 1, Defense: 2}, calculateLevelRewards(p, 1, lvl),
+		"a level-up grants the stats of every level climbed")
+	assert.Equal(t, domain.LevelReward{}, calculateLevelRewards(p, 2, 2))
+}
+
+func TestCalculateDamage_Armour(t *testing.T) {
+	rng := fixedRand{}
+
+	assert.Equal(t, uint(7), calculateDamage(rng, 10, 5, 3, string(domain.BodyPartHead), string(domain.BodyPartLegs)),
+		"armour soaks a hit the player did not block")
+	assert.Equal(t, uint(2), calculateDamage(rng, 10, 5, 3, string(domain.BodyPartHead), string(domain.BodyPartHead)))
+	assert.Equal(t, uint(0), calculateDamage(rng, 10, 8, 3, string(domain.BodyPartHead), string(domain.BodyPartHead)))
+}
+
+func TestFightService_ResolveIdleRounds(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Test database not initialized")
+	}
+
+	db := testDB.DB()
+	service := NewFightService(db)
+	ctx := context.Background()
+
+	backdate := func(t *testing.T, fightID uuid.UUID) {
+		_, err := db.Exec(`UPDATE rounds SET created_at = created_at - INTERVAL '1 hour' WHERE fight_id = $1 AND status = 'IN_PROGRESS'`, fightID)
+		require.NoError(t, err)
+	}
+
+	t.Run("idle round is resolved on the player's behalf", func(t *testing.T) {
+		_, user, _, fight, err := setupFightTestData(db)
+		require.NoError(t, err)
+		backdate(t, fight.ID)
+
+		_, err = service.ResolveIdleRounds(ctx, 30*time.Minute, 3)
+		require.NoError(t, err)
+
+		rounds, err := repository.NewRoundRepository(db).FindByFightID(fight.ID)
+		require.NoError(t, err)
+		require.Len(t, rounds, 2)
+		assert.True(t, rounds[1].TimedOut)
+		assert.Equal(t, domain.RoundStatusFinished, rounds[1].Status)
+		assert.NotNil(t, rounds[1].PlayerAttackPoint)
+
+		_, err = service.GetCurrentFight(ctx, user.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("player forfeits after missing too many rounds", func(t *testing.T) {
+		_, user, _, fight, err := setupFightTestData(db)
+		require.NoError(t, err)
+
+		backdate(t, fight.ID)
+		_, err = service.ResolveIdleRounds(ctx, 30*time.Minute, 2)
+		require.NoError(t, err)
+
+		backdate(t, fight.ID)
+		_, err = service.ResolveIdleRounds(ctx, 30*time.Minute, 2)
+		require.NoError(t, err)
+
+		_, err = service.GetCurrentFight(ctx, user.ID)
+		assert.Equal(t, ErrNoActiveFight, err)
+
+		history, _, err := service.GetFightHistory(ctx, user.ID, "", "lost", 10, 0)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, fight.ID, history[0].ID)
+	})
+}
+
+func TestMissedRounds(t *testing.T) {
+	rounds := []*domain.Round{
+		{Status: domain.RoundStatusInProgress},
+		{Status: domain.RoundStatusFinished, TimedOut: true},
+		{Status: domain.RoundStatusFinished, TimedOut: true},
+		{Status: domain.RoundStatusFinished},
+		{Status: domain.RoundStatusFinished, TimedOut: true},
+	}
+
+	assert.Equal(t, 2, missedRounds(rounds))
+	assert.Equal(t, 0, missedRounds(rounds[3:]))
+	assert.Equal(t, 0, missedRounds(nil))
+}
@@ -0,0 +1,711 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/api/dto"
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+const (
+	GroupFightMaxMembers  = 5
+	GroupFightTurnTimeout = 30 * time.Second
+)
+
+var (
+	ErrGroupFightNotFound  = errors.New("group fight not found")
+	ErrGroupFightFull      = errors.New("group fight is full")
+	ErrGroupFightElsewhere = errors.New("group fight is in another location")
+	ErrBotNotInLocation    = errors.New("bot is not in the same location as user")
+	ErrOutOfGroupFight     = errors.New("you are out of the fight")
+)
+
+type GroupFightService struct {
+	groupFightRepo *repository.GroupFightRepository
+	roundRepo      *repository.GroupFightRoundRepository
+	botRepo        *repository.BotRepository
+	userRepo       *repository.UserRepository
+	locationRepo   *repository.LocationRepository
+	publisher      ws.Publisher
+	rng            Rand
+	db             *sqlx.DB
+}
+
+func NewGroupFightService(db *sqlx.DB) *GroupFightService {
+	return NewGroupFightServiceWithRand(db, globalRand{})
+}
+
+// NewGroupFightServiceWithRand builds a GroupFightService whose damage rolls,
+// bot choices, targets and drops all come from rng.
+func NewGroupFightServiceWithRand(db *sqlx.DB, rng Rand) *GroupFightService {
+	return &GroupFightService{
+		groupFightRepo: repository.NewGroupFightRepository(db),
+		roundRepo:      repository.NewGroupFightRoundRepository(db),
+		botRepo:        repository.NewBotRepository(db),
+		userRepo:       repository.NewUserRepository(db),
+		locationRepo:   repository.NewLocationRepository(db),
+		publisher:      ws.GetHub(),
+		rng:            rng,
+		db:             db,
+	}
+}
+
+// Start opens a group fight against an idle instance of the bot in the
+// user's location, with the user as its leader and first member.
+func (s *GroupFightService) Start(ctx context.Context, userID uuid.UUID, botSlug string) (*domain.GroupFight, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	defer tx.Rollback()
+
+	user, err := s.availableUser(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	bot, err := s.botRepo.FindBySlug(botSlug)
+	if err != nil {
+		return nil, ErrBotNotFound
+	}
+
+	if err := s.checkBossCooldown(user.ID, bot); err != nil {
+		return nil, err
+	}
+
+	instanceRepoTx := repository.NewBotInstanceRepository(tx)
+	if err := instanceRepoTx.Spawn(user.LocationID); err != nil {
+		return nil, ErrInternalError
+	}
+
+	instance, err := instanceRepoTx.FindAvailableForUpdate(user.LocationID, bot.ID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrBotInstanceNotFound) {
+			return nil, ErrInternalError
+		}
+		if exists, err := s.locationRepo.HasBot(user.LocationID, bot.ID); err != nil || !exists {
+			return nil, ErrBotNotInLocation
+		}
+		return nil, ErrNoBotAvailable
+	}
+
+	fight := &domain.GroupFight{
+		LeaderID:   user.ID,
+		BotID:      bot.ID,
+		LocationID: user.LocationID,
+		MaxMembers: GroupFightMaxMembers,
+	}
+
+	groupFightRepoTx := repository.NewGroupFightRepository(tx)
+	if err := groupFightRepoTx.Create(fight); err != nil {
+		return nil, ErrInternalError
+	}
+	if user.CurrentHp, err = s.userRepo.FreezeHpWithExt(tx, user.ID); err != nil {
+		return nil, ErrInternalError
+	}
+	if err := cancelMovementWithExt(tx, user.ID); err != nil {
+		return nil, ErrInternalError
+	}
+	if err := groupFightRepoTx.AddMember(fight.ID, user.ID, user.CurrentHp); err != nil {
+		return nil, ErrInternalError
+	}
+	if err := repository.NewGroupFightRoundRepository(tx).Create(fight.ID, instance.CurrentHp, GroupFightTurnTimeout); err != nil {
+		return nil, ErrInternalError
+	}
+	if err := instanceRepoTx.EngageGroup(instance.ID, fight.ID); err != nil {
+		return nil, ErrInternalError
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ErrInternalError
+	}
+
+	return s.reloadAndNotify(fight.ID)
+}
+
+// Join adds the user to a running group fight in their location. They move
+// from the current round on.
+func (s *GroupFightService) Join(ctx context.Context, userID, fightID uuid.UUID) (*domain.GroupFight, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	defer tx.Rollback()
+
+	user, err := s.availableUser(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	groupFightRepoTx := repository.NewGroupFightRepository(tx)
+
+	fight, err := groupFightRepoTx.FindByIDForUpdate(fightID)
+	if err != nil || fight.Status != domain.GroupFightStatusInProgress {
+		return nil, ErrGroupFightNotFound
+	}
+
+	if fight.LocationID != user.LocationID {
+		return nil, ErrGroupFightElsewhere
+	}
+
+	members, err := groupFightRepoTx.FindMembers(fight.ID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if len(members) >= fight.MaxMembers {
+		return nil, ErrGroupFightFull
+	}
+
+	bot, err := s.botRepo.FindByID(fight.BotID)
+	if err != nil {
+		return nil, ErrBotNotFound
+	}
+	if err := s.checkBossCooldown(user.ID, bot); err != nil {
+		return nil, err
+	}
+
+	if user.CurrentHp, err = s.userRepo.FreezeHpWithExt(tx, user.ID); err != nil {
+		return nil, ErrInternalError
+	}
+	if err := cancelMovementWithExt(tx, user.ID); err != nil {
+		return nil, ErrInternalError
+	}
+	if err := groupFightRepoTx.AddMember(fight.ID, user.ID, user.CurrentHp); err != nil {
+		return nil, ErrInternalError
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ErrInternalError
+	}
+
+	return s.reloadAndNotify(fight.ID)
+}
+
+func (s *GroupFightService) GetCurrent(ctx context.Context, userID uuid.UUID) (*domain.GroupFight, error) {
+	fight, err := s.groupFightRepo.FindActiveByUserID(userID)
+	if err != nil {
+		return nil, ErrGroupFightNotFound
+	}
+
+	return s.load(fight)
+}
+
+// GetJoinable lists the group fights in the user's location that have a free
+// place.
+func (s *GroupFightService) GetJoinable(ctx context.Context, userID uuid.UUID) ([]*domain.GroupFight, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	fights, err := s.groupFightRepo.FindJoinableByLocationID(user.LocationID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	for i, fight := range fights {
+		if fights[i], err = s.load(fight); err != nil {
+			return nil, err
+		}
+	}
+
+	return fights, nil
+}
+
+// Hit records the user's move for the current round. The round is resolved
+// once every member still standing has moved.
+func (s *GroupFightService) Hit(ctx context.Context, userID uuid.UUID, attackPoint, defensePoint string) (*domain.GroupFight, error) {
+	if !isValidBodyPart(attackPoint) || !isValidBodyPart(defensePoint) {
+		return nil, ErrInvalidBodyPart
+	}
+
+	active, err := s.groupFightRepo.FindActiveByUserID(userID)
+	if err != nil {
+		return nil, ErrGroupFightNotFound
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	defer tx.Rollback()
+
+	groupFightRepoTx := repository.NewGroupFightRepository(tx)
+	roundRepoTx := repository.NewGroupFightRoundRepository(tx)
+
+	fight, err := groupFightRepoTx.FindByIDForUpdate(active.ID)
+	if err != nil || fight.Status != domain.GroupFightStatusInProgress {
+		return nil, ErrGroupFightNotFound
+	}
+
+	if fight.Members, err = groupFightRepoTx.FindMembers(fight.ID); err != nil {
+		return nil, ErrInternalError
+	}
+	if member := fight.Member(userID); member == nil || member.Hp == 0 {
+		return nil, ErrOutOfGroupFight
+	}
+
+	round, err := roundRepoTx.FindCurrentForUpdate(fight.ID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if round.Moves, err = roundRepoTx.FindMovesByRoundID(round.ID); err != nil {
+		return nil, ErrInternalError
+	}
+	if round.Move(userID) != nil {
+		return nil, ErrAlreadyMoved
+	}
+
+	move := &domain.GroupFightMove{
+		RoundID:      round.ID,
+		UserID:       userID,
+		AttackPoint:  domain.BodyPart(attackPoint),
+		DefensePoint: domain.BodyPart(defensePoint),
+	}
+	if err := roundRepoTx.AddMove(move); err != nil {
+		return nil, ErrInternalError
+	}
+	round.Moves = append(round.Moves, move)
+
+	var events []groupFightEvent
+	if allMoved(fight, round) {
+		if events, err = s.resolveRound(tx, fight, round); err != nil {
+			return nil, ErrInternalError
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, ErrInternalError
+	}
+
+	s.publish(events)
+
+	return s.reloadAndNotify(fight.ID)
+}
+
+// ResolveExpiredRounds picks random moves for members who missed their turn
+// and plays the round out. It returns the number of fights touched.
+func (s *GroupFightService) ResolveExpiredRounds(ctx context.Context) (int, error) {
+	fightIDs, err := s.roundRepo.FindExpiredFightIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	resolved := 0
+	for _, fightID := range fightIDs {
+		ok, err := s.resolveExpiredRound(ctx, fightID)
+		if err != nil {
+			fmt.Printf("[GroupFightService] Failed to resolve expired round of group fight %s: %v\n", fightID, err)
+			continue
+		}
+		if ok {
+			resolved++
+			if _, err := s.reloadAndNotify(fightID); err != nil {
+				fmt.Printf("[GroupFightService] Failed to notify about group fight %s: %v\n", fightID, err)
+			}
+		}
+	}
+
+	return resolved, nil
+}
+
+func (s *GroupFightService) resolveExpiredRound(ctx context.Context, fightID uuid.UUID) (bool, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	groupFightRepoTx := repository.NewGroupFightRepository(tx)
+	roundRepoTx := repository.NewGroupFightRoundRepository(tx)
+
+	fight, err := groupFightRepoTx.FindByIDForUpdate(fightID)
+	if err != nil {
+		return false, err
+	}
+	if fight.Status != domain.GroupFightStatusInProgress {
+		return false, nil
+	}
+
+	round, err := roundRepoTx.FindExpiredForUpdate(fight.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if fight.Members, err = groupFightRepoTx.FindMembers(fight.ID); err != nil {
+		return false, err
+	}
+	if round.Moves, err = roundRepoTx.FindMovesByRoundID(round.ID); err != nil {
+		return false, err
+	}
+
+	for _, member := range fight.AliveMembers() {
+		if round.Move(member.UserID) != nil {
+			continue
+		}
+		attack, defense := randomStrategy{}.Choose(nil, s.rng.Intn)
+		move := &domain.GroupFightMove{RoundID: round.ID, UserID: member.UserID, AttackPoint: attack, DefensePoint: defense}
+		if err := roundRepoTx.AddMove(move); err != nil {
+			return false, err
+		}
+		round.Moves = append(round.Moves, move)
+	}
+
+	events, err := s.resolveRound(tx, fight, round)
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	s.publish(events)
+
+	return true, nil
+}
+
+// groupFightEvent is an event for one member, sent once the round is
+// committed.
+type groupFightEvent struct {
+	userID uuid.UUID
+	event  ws.Event
+}
+
+// resolveRound lets every standing member hit the bot, then the bot strikes
+// one of them picked at random. fight.Members and round.Moves must be loaded.
+func (s *GroupFightService) resolveRound(tx *sqlx.Tx, fight *domain.GroupFight, round *domain.GroupFightRound) ([]groupFightEvent, error) {
+	bot, err := findBot(s.botRepo, fight.BotID)
+	if err != nil {
+		return nil, err
+	}
+
+	alive := fight.AliveMembers()
+	users := make(map[uuid.UUID]*domain.User, len(fight.Members))
+	for _, member := range fight.Members {
+		if users[member.UserID], err = findFighter(s.userRepo, member.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	roundRepoTx := repository.NewGroupFightRoundRepository(tx)
+
+	moves, err := roundRepoTx.FindMovesByFightID(fight.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	phase := bot.PhaseAt(round.BotHp)
+	fighter := bot.WithPhase(phase)
+	botAttack, botDefense := botStrategyFor(fighter).Choose(groupFightHistory(moves, round.ID), s.rng.Intn)
+	ability := rollAbility(s.rng, phase)
+	target := alive[s.rng.Intn(len(alive))]
+
+	var totalDmg uint
+	for _, member := range alive {
+		move := round.Move(member.UserID)
+		player := fighterOf(users[member.UserID])
+		hit := defaultDamagePipeline.Resolve(s.rng, Hit{
+			Attack:       player.Attack,
+			Defense:      fighter.Defense,
+			AttackPoint:  string(move.AttackPoint),
+			DefensePoint: string(botDefense),
+			Attacker:     player.CombatStats,
+			Defender:     fighter.CombatStats,
+		})
+		move.Damage, move.Critical = hit.Damage, hit.Critical
+		move.BotDodged, move.BotShieldBlocked = hit.Dodged, hit.ShieldBlocked
+		if err := roundRepoTx.SetMoveHit(move); err != nil {
+			return nil, err
+		}
+		member.Damage += hit.Damage
+		totalDmg += hit.Damage
+	}
+
+	botAttack, botHit := botStrike(s.rng, fighter, fighterOf(users[target.UserID]), ability, botAttack,
+		string(round.Move(target.UserID).DefensePoint))
+	botDmg := botHit.Damage
+	target.Hp = calculateFinalHp(target.Hp, botDmg)
+
+	round.BotHp = healBot(bot, phase, ability, calculateFinalHp(round.BotHp, totalDmg))
+	round.TargetID = &target.UserID
+	round.BotAttackPoint = &botAttack
+	round.BotDefensePoint = &botDefense
+	round.BotDamage = botDmg
+	round.BotAbility = ability
+	round.BotCritical = botHit.Critical
+	round.TargetDodged, round.TargetShieldBlocked = botHit.Dodged, botHit.ShieldBlocked
+	if err := roundRepoTx.FinishRound(round); err != nil {
+		return nil, err
+	}
+
+	groupFightRepoTx := repository.NewGroupFightRepository(tx)
+	for _, member := range alive {
+		if err := groupFightRepoTx.UpdateMember(member); err != nil {
+			return nil, err
+		}
+	}
+
+	// A member knocked out leaves the fight right away with 0 HP and is
+	// carried back like a player beaten in a 1v1 fight.
+	if target.Hp == 0 {
+		if err := s.userRepo.UpdateCurrentHpWithExt(tx, target.UserID, 0); err != nil {
+			return nil, err
+		}
+
+		d, err := defeat(tx, s.userRepo, s.locationRepo, users[target.UserID], domain.CurrentDefeatPolicy())
+		if err != nil {
+			return nil, err
+		}
+		target.LostGold, target.LostExp = d.LostGold, d.LostExp
+		target.RespawnLocationID, target.WeakenedUntil = &d.RespawnLocation.ID, d.WeakenedUntil
+		if err := groupFightRepoTx.RecordMemberDefeat(target); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case round.BotHp == 0:
+		return s.finishWon(tx, fight, bot, users)
+	case len(fight.AliveMembers()) == 0:
+		if err := groupFightRepoTx.Finish(fight.ID, domain.GroupFightStatusLost, nil); err != nil {
+			return nil, err
+		}
+		return nil, releaseBotInstance(tx, fight.ID, round.BotHp)
+	default:
+		return nil, roundRepoTx.Create(fight.ID, round.BotHp, GroupFightTurnTimeout)
+	}
+}
+
+// groupFightHistory turns the members' moves from rounds before currentRoundID
+// into the newest-first round history bot strategies read in 1v1 fights. The
+// bot sees every member's move as if one player had made it.
+func groupFightHistory(moves []*domain.GroupFightMove, currentRoundID uuid.UUID) []*domain.Round {
+	rounds := make([]*domain.Round, 0, len(moves))
+	for i := len(moves) - 1; i >= 0; i-- {
+		move := moves[i]
+		if move.RoundID == currentRoundID {
+			continue
+		}
+		attack, defense := move.AttackPoint, move.DefensePoint
+		rounds = append(rounds, &domain.Round{
+			Status:             domain.RoundStatusFinished,
+			PlayerAttackPoint:  &attack,
+			PlayerDefensePoint: &defense,
+		})
+	}
+	return rounds
+}
+
+// finishWon splits exp and gold by the share of damage each member dealt.
+// The item drop goes to whoever dealt the most.
+func (s *GroupFightService) finishWon(tx *sqlx.Tx, fight *domain.GroupFight, bot *domain.Bot,
+	users map[uuid.UUID]*domain.User) ([]groupFightEvent, error) {
+	progression := domain.CurrentProgression()
+	gold := calculateDroppedGold(s.rng, bot.Level)
+
+	var totalDmg uint
+	var top *domain.GroupFightMember
+	for _, member := range fight.Members {
+		totalDmg += member.Damage
+		if top == nil || member.Damage > top.Damage {
+			top = member
+		}
+	}
+
+	groupFightRepoTx := repository.NewGroupFightRepository(tx)
+	bossKillRepoTx := repository.NewBossKillRepository(tx)
+
+	var events []groupFightEvent
+	for _, member := range fight.Members {
+		if member.Damage == 0 {
+			continue
+		}
+		user := users[member.UserID]
+
+		member.Exp = splitByDamage(calculateExp(progression, 0, user.Level, bot.Level), member.Damage, totalDmg)
+		member.Gold = splitByDamage(gold, member.Damage, totalDmg)
+		if err := groupFightRepoTx.UpdateMember(member); err != nil {
+			return nil, err
+		}
+
+		lvl := calculateLvl(progression, user.Level, user.Exp, member.Exp)
+		rewards := calculateLevelRewards(progression, user.Level, lvl)
+		freeStats := rewards.FreeStats
+		currentHp := member.Hp
+		if lvl > user.Level {
+			if err := s.userRepo.AddLevelStatsWithExt(tx, user.ID, rewards.Hp, rewards.Attack, rewards.Defense); err != nil {
+				return nil, err
+			}
+			currentHp = user.Hp + rewards.Hp
+			events = append(events, groupFightEvent{user.ID, ws.LevelUpEvent(lvl, user.FreeStats+freeStats)})
+		}
+
+		if err := s.userRepo.UpdateWithExt(tx, user.ID, member.Gold, member.Exp, lvl, currentHp, freeStats); err != nil {
+			return nil, err
+		}
+
+		if bot.IsBoss {
+			if err := bossKillRepoTx.Record(user.ID, bot.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Members who dealt no damage get nothing but are still released with the
+	// HP they have left.
+	for _, member := range fight.Members {
+		if member.Damage == 0 && member.Hp > 0 {
+			if err := s.userRepo.UpdateCurrentHpWithExt(tx, member.UserID, member.Hp); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var itemID *uuid.UUID
+	if top != nil && top.Damage > 0 {
+		loot, err := s.botRepo.FindLootByBotID(bot.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		itemID = rollLoot(s.rng, bot, loot, users[top.UserID].Level)
+
+		if itemID != nil {
+			inventory := &domain.Inventory{UserID: top.UserID, EquipmentItemID: *itemID}
+			if err := repository.NewInventoryRepository(tx).Create(inventory); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := groupFightRepoTx.Finish(fight.ID, domain.GroupFightStatusWon, itemID); err != nil {
+		return nil, err
+	}
+
+	return events, releaseBotInstance(tx, fight.ID, 0)
+}
+
+// splitByDamage gives a member the part of pool matching their share of the
+// total damage, rounded down.
+func splitByDamage(pool, damage, totalDamage uint) uint {
+	if totalDamage == 0 {
+		return 0
+	}
+	return uint(uint64(pool) * uint64(damage) / uint64(totalDamage))
+}
+
+func allMoved(fight *domain.GroupFight, round *domain.GroupFightRound) bool {
+	for _, member := range fight.AliveMembers() {
+		if round.Move(member.UserID) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// availableUser locks the user for the rest of tx and checks they are free to
+// fight.
+func (s *GroupFightService) availableUser(tx *sqlx.Tx, userID uuid.UUID) (*domain.User, error) {
+	if err := s.userRepo.LockWithExt(tx, userID); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, ErrInternalError
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	inFight, err := s.userRepo.InFightWithExt(tx, userID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	if inFight {
+		return nil, ErrUserInFight
+	}
+
+	return user, nil
+}
+
+func (s *GroupFightService) checkBossCooldown(userID uuid.UUID, bot *domain.Bot) error {
+	if !bot.IsBoss {
+		return nil
+	}
+
+	until, err := repository.NewBossKillRepository(s.db).CooldownUntil(userID, bot.ID)
+	if err != nil {
+		return ErrInternalError
+	}
+	if until != nil {
+		return fmt.Errorf("%w, try again after %s", ErrBossOnCooldown, until.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// load fills in the bot, members and rounds with their moves.
+func (s *GroupFightService) load(fight *domain.GroupFight) (*domain.GroupFight, error) {
+	var err error
+	if fight.Bot, err = s.botRepo.FindByID(fight.BotID); err != nil {
+		return nil, ErrInternalError
+	}
+	if fight.Members, err = s.groupFightRepo.FindMembers(fight.ID); err != nil {
+		return nil, ErrInternalError
+	}
+	if fight.Rounds, err = s.roundRepo.FindByFightID(fight.ID); err != nil {
+		return nil, ErrInternalError
+	}
+
+	moves, err := s.roundRepo.FindMovesByFightID(fight.ID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+	byRound := make(map[uuid.UUID][]*domain.GroupFightMove, len(fight.Rounds))
+	for _, move := range moves {
+		byRound[move.RoundID] = append(byRound[move.RoundID], move)
+	}
+	for _, round := range fight.Rounds {
+		round.Moves = byRound[round.ID]
+	}
+
+	return fight, nil
+}
+
+func (s *GroupFightService) reloadAndNotify(fightID uuid.UUID) (*domain.GroupFight, error) {
+	fight, err := s.groupFightRepo.FindByID(fightID)
+	if err != nil {
+		return nil, ErrInternalError
+	}
+
+	if fight, err = s.load(fight); err != nil {
+		return nil, err
+	}
+
+	event := ws.GroupFightUpdateEvent(dto.GroupFightFromDomain(fight))
+	for _, member := range fight.Members {
+		s.publisher.Publish(member.UserID, event)
+	}
+
+	return fight, nil
+}
+
+func (s *GroupFightService) publish(events []groupFightEvent) {
+	for _, e := range events {
+		s.publisher.Publish(e.userID, e.event)
+	}
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+)
+
+func TestSplitByDamage(t *testing.T) {
+	assert.Equal(t, uint(75), splitByDamage(100, 30, 40))
+	assert.Equal(t, uint(25), splitByDamage(100, 10, 40))
+	assert.Equal(t, uint(33), splitByDamage(100, 1, 3))
+	assert.Equal(t, uint(0), splitByDamage(100, 0, 40))
+	assert.Equal(t, uint(0), splitByDamage(100, 0, 0))
+}
+
+func TestAllMoved(t *testing.T) {
+	alive := &domain.GroupFightMember{UserID: uuid.New(), Hp: 10}
+	dead := &domain.GroupFightMember{UserID: uuid.New(), Hp: 0}
+	fight := &domain.GroupFight{Members: []*domain.GroupFightMember{alive, dead}}
+
+	round := &domain.GroupFightRound{}
+	assert.False(t, allMoved(fight, round))
+
+	round.Moves = append(round.Moves, &domain.GroupFightMove{UserID: alive.UserID})
+	assert.True(t, allMoved(fight, round), "knocked out members do not move")
+}
+
+func TestGroupFightHistory(t *testing.T) {
+	previous, current := uuid.New(), uuid.New()
+	moves := []*domain.GroupFightMove{
+		{RoundID: previous, AttackPoint: domain.BodyPartHead, DefensePoint: domain.BodyPartChest},
+		{RoundID: previous, AttackPoint: domain.BodyPartLegs, DefensePoint: domain.BodyPartHead},
+		{RoundID: current, AttackPoint: domain.BodyPartChest, DefensePoint: domain.BodyPartLegs},
+	}
+
+	rounds := groupFightHistory(moves, current)
+	require.Len(t, rounds, 2, "moves of the round being resolved are left out")
+	assert.Equal(t, domain.BodyPartLegs, *rounds[0].PlayerAttackPoint, "the newest move comes first")
+	assert.Equal(t, domain.BodyPartHead, *rounds[1].PlayerAttackPoint)
+
+	attack, defense := mirrorStrategy{}.Choose(rounds, func(int) int { return 0 })
+	assert.Equal(t, domain.BodyPartLegs, attack)
+	assert.Equal(t, domain.BodyPartHead, defense)
+}
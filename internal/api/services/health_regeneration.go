@@ -1,6 +1,8 @@
 package services
 
 import (
+	"github.com/google/uuid"
+
 	"moonshine/internal/repository"
 )
 
@@ -14,6 +16,8 @@ func NewHealthRegenerationService(userRepo *repository.UserRepository) *HealthRe
 	}
 }
 
-func (s *HealthRegenerationService) RegenerateAllUsers(percent float64) ([]repository.HPUpdate, error) {
-	return s.userRepo.RegenerateAllUsersHealth(percent)
+// CurrentHP returns the HP the users have right now. Regeneration is worked
+// out by the database on read, so nothing is written.
+func (s *HealthRegenerationService) CurrentHP(userIDs []uuid.UUID) ([]repository.HPUpdate, error) {
+	return s.userRepo.GetHPForUsers(userIDs)
 }
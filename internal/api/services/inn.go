@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+var (
+	ErrNotInInn      = errors.New("user is not in the inn")
+	ErrNothingToHeal = errors.New("nothing to heal")
+)
+
+// InnQuote is what a full heal at the inn would cost the user right now.
+type InnQuote struct {
+	MissingHp uint
+	Price     uint
+}
+
+type InnService struct {
+	db           *sqlx.DB
+	userRepo     *repository.UserRepository
+	locationRepo *repository.LocationRepository
+	publisher    ws.Publisher
+}
+
+func NewInnService(db *sqlx.DB) *InnService {
+	return &InnService{
+		db:           db,
+		userRepo:     repository.NewUserRepository(db),
+		locationRepo: repository.NewLocationRepository(db),
+		publisher:    ws.GetHub(),
+	}
+}
+
+func (s *InnService) Quote(ctx context.Context, userID uuid.UUID) (*InnQuote, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	return quoteFor(user), nil
+}
+
+// Heal restores the user to full HP for the quoted price. Only a player
+// standing in the inn can be healed there; the user row stays locked from the
+// checks to the payment, so a move or a fight cannot slip in between.
+func (s *InnService) Heal(ctx context.Context, userID uuid.UUID) (*domain.User, *InnQuote, error) {
+	inn, err := s.locationRepo.FindBySlug(domain.InnSlug)
+	if err != nil {
+		if errors.Is(err, repository.ErrLocationNotFound) {
+			return nil, nil, ErrNotInInn
+		}
+		return nil, nil, ErrInternalError
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, nil, ErrInternalError
+	}
+	defer tx.Rollback()
+
+	if err = s.userRepo.LockWithExt(tx, userID); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil, nil, ErrUserNotFound
+		}
+		return nil, nil, ErrInternalError
+	}
+
+	user, err := s.userRepo.FindByIDWithExt(tx, userID)
+	if err != nil {
+		return nil, nil, ErrUserNotFound
+	}
+	if user.LocationID != inn.ID {
+		return nil, nil, ErrNotInInn
+	}
+
+	inFight, err := s.userRepo.InFightWithExt(tx, userID)
+	if err != nil {
+		return nil, nil, ErrInternalError
+	}
+	if inFight {
+		return nil, nil, ErrUserInFight
+	}
+
+	quote := quoteFor(user)
+	if quote.MissingHp == 0 {
+		return nil, nil, ErrNothingToHeal
+	}
+
+	if user.CurrentHp, err = s.userRepo.InnHealWithExt(tx, userID, inn.ID, quote.Price); err != nil {
+		if errors.Is(err, repository.ErrNotEnoughGold) {
+			return nil, nil, ErrInsufficientGold
+		}
+		return nil, nil, ErrInternalError
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, ErrInternalError
+	}
+	user.Gold -= quote.Price
+
+	s.publisher.Publish(userID, ws.HPUpdateEvent(user.CurrentHp, user.Hp))
+
+	return user, quote, nil
+}
+
+func quoteFor(user *domain.User) *InnQuote {
+	missing := user.MissingHp()
+	return &InnQuote{
+		MissingHp: missing,
+		Price:     domain.InnHealPrice(missing, user.Level),
+	}
+}
@@ -13,16 +13,18 @@ import (
 
 var (
 	ErrLocationNotConnected = errors.New("locations are not connected")
+	ErrNoActiveMovement     = errors.New("no active movement")
 )
 
 type MovingWorker interface {
-	StartMovement(userID uuid.UUID, cellSlugs []string) error
+	StartMovement(userID uuid.UUID, cellSlugs []string) (*domain.Movement, error)
 }
 
 type LocationService struct {
 	db           *sqlx.DB
 	locationRepo *repository.LocationRepository
 	userRepo     *repository.UserRepository
+	movementRepo *repository.MovementRepository
 	movingWorker MovingWorker
 	graph        *LocationGraph
 }
@@ -42,6 +44,7 @@ func NewLocationService(
 		db:           db,
 		locationRepo: locationRepo,
 		userRepo:     userRepo,
+		movementRepo: repository.NewMovementRepository(db),
 		movingWorker: movingWorker,
 		graph:        graph,
 	}, nil
@@ -82,8 +85,17 @@ func (s *LocationService) MoveToLocation(ctx context.Context, userID uuid.UUID,
 		return nil
 	}
 
-	updateLocationQuery := `UPDATE users SET location_id = $1 WHERE id = $2`
-	_, err = tx.Exec(updateLocationQuery, targetLocation.ID, userID)
+	err = s.userRepo.UpdateLocationIDWithExt(tx, userID, targetLocation.ID)
+	if err != nil {
+		return err
+	}
+
+	movementLogRepo := repository.NewMovementLogRepository(tx)
+	err = movementLogRepo.Create(&domain.MovementLog{
+		UserID:   userID,
+		FromCell: &currentLocation.Slug,
+		ToCell:   targetLocation.Slug,
+	})
 	if err != nil {
 		return err
 	}
@@ -99,6 +111,37 @@ func (s *LocationService) FindShortestPath(fromSlug, toSlug string) ([]string, e
 	return s.graph.FindShortestPath(fromSlug, toSlug)
 }
 
-func (s *LocationService) StartCellMovement(userID uuid.UUID, cellSlugs []string) error {
+func (s *LocationService) StartCellMovement(userID uuid.UUID, cellSlugs []string) (*domain.Movement, error) {
 	return s.movingWorker.StartMovement(userID, cellSlugs)
 }
+
+func (s *LocationService) GetMovement(userID uuid.UUID) (*domain.Movement, error) {
+	movement, err := s.movementRepo.FindByUserID(userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMovementNotFound) {
+			return nil, ErrNoActiveMovement
+		}
+		return nil, err
+	}
+
+	return movement, nil
+}
+
+// cancelMovementWithExt drops the user's walk, if any. Fights call it when they
+// start, so nobody keeps walking while they fight.
+func cancelMovementWithExt(h repository.ExtHandle, userID uuid.UUID) error {
+	_, err := repository.NewMovementRepository(h).DeleteByUserID(userID)
+	return err
+}
+
+func (s *LocationService) CancelMovement(userID uuid.UUID) error {
+	deleted, err := s.movementRepo.DeleteByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNoActiveMovement
+	}
+
+	return nil
+}
@@ -44,7 +44,9 @@ func createConnections(db *sqlx.DB, connections [][2]uuid.UUID) error {
 
 type noopMovingWorker struct{}
 
-func (noopMovingWorker) StartMovement(userID uuid.UUID, cellSlugs []string) error { return nil }
+func (noopMovingWorker) StartMovement(userID uuid.UUID, cellSlugs []string) (*domain.Movement, error) {
+	return &domain.Movement{UserID: userID, Path: cellSlugs}, nil
+}
 
 func TestLocationService_FindShortestPath(t *testing.T) {
 	if testDB == nil {
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+type MovementLogService struct {
+	movementLogRepo *repository.MovementLogRepository
+}
+
+func NewMovementLogService(movementLogRepo *repository.MovementLogRepository) *MovementLogService {
+	return &MovementLogService{
+		movementLogRepo: movementLogRepo,
+	}
+}
+
+func (s *MovementLogService) GetUserMovements(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.MovementLog, int, error) {
+	logs, err := s.movementLogRepo.FindByUserID(userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.movementLogRepo.CountByUserID(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
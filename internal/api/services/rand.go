@@ -0,0 +1,41 @@
+package services
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Rand is the random source used by combat. Injecting a seeded one makes
+// fights reproducible in tests and simulations.
+type Rand interface {
+	Intn(n int) int
+	Float64() float64
+}
+
+// NewRand returns a seeded source that is safe for concurrent use.
+func NewRand(seed int64) Rand {
+	return &lockedRand{r: rand.New(rand.NewSource(seed))}
+}
+
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func (l *lockedRand) Intn(n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Intn(n)
+}
+
+func (l *lockedRand) Float64() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Float64()
+}
+
+// globalRand uses the math/rand package source.
+type globalRand struct{}
+
+func (globalRand) Intn(n int) int   { return rand.Intn(n) }
+func (globalRand) Float64() float64 { return rand.Float64() }
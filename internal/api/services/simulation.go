@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+
+	"moonshine/internal/domain"
+)
+
+// maxSimulatedRounds stops fights where neither side can hurt the other.
+const maxSimulatedRounds = 1000
+
+var ErrInvalidSimulation = errors.New("invalid simulation")
+
+// SimulationConfig describes a batch of simulated fights.
+type SimulationConfig struct {
+	Fights int
+	// RoundSeconds is how long a player takes per round, used to turn rounds
+	// into play time.
+	RoundSeconds float64
+	Rand         Rand
+	// Progression defaults to domain.CurrentProgression.
+	Progression *domain.Progression
+}
+
+type SimulationReport struct {
+	Fights        int
+	Wins          int
+	Stalemates    int
+	WinRate       float64
+	AvgRounds     float64
+	AvgExp        float64
+	AvgGold       float64
+	ExpPerHour    float64
+	GoldPerHour   float64
+	ExpToLevel    uint
+	BotsToLevel   uint
+	FightsToLevel float64
+	HoursToLevel  float64
+}
+
+// SimulateFights plays fights between player and bot with the same damage,
+// strategy, boss phase and reward rules as FightService.Hit. The player picks attack and
+// defense points at random; items are added on top of the player's stats.
+func SimulateFights(player *domain.User, items []*domain.EquipmentItem, bot *domain.Bot, cfg SimulationConfig) (*SimulationReport, error) {
+	if player == nil || bot == nil || cfg.Fights <= 0 || cfg.RoundSeconds <= 0 || cfg.Rand == nil {
+		return nil, ErrInvalidSimulation
+	}
+
+	progression := cfg.Progression
+	if progression == nil {
+		progression = domain.CurrentProgression()
+	}
+
+	fighter := *player
+	for _, item := range items {
+		fighter.Attack += item.Attack
+		fighter.Defense += item.Defense
+		fighter.Armour.Cover(item.EquipmentType, item.Defense)
+		fighter.CombatStats.CritChance += item.CritChance
+		fighter.CombatStats.CritPower += item.CritPower
+		fighter.CombatStats.DodgeChance += item.DodgeChance
+		if item.EquipmentType == "shield" {
+			fighter.CombatStats.BlockChance += item.BlockChance
+		}
+		fighter.Hp += item.Hp
+	}
+
+	report := &SimulationReport{Fights: cfg.Fights}
+	totalRounds, totalExp, totalGold := 0, uint(0), uint(0)
+
+	for i := 0; i < cfg.Fights; i++ {
+		rounds, playerHp, botHp := simulateFight(cfg.Rand, &fighter, bot)
+		totalRounds += rounds
+
+		if playerHp > 0 && botHp > 0 {
+			report.Stalemates++
+			continue
+		}
+		if botHp == 0 {
+			report.Wins++
+		}
+
+		totalGold += calculateDroppedGold(cfg.Rand, bot.Level)
+		totalExp += calculateExp(progression, botHp, fighter.Level, bot.Level)
+	}
+
+	hours := float64(totalRounds) * cfg.RoundSeconds / 3600
+	report.WinRate = float64(report.Wins) / float64(cfg.Fights)
+	report.AvgRounds = float64(totalRounds) / float64(cfg.Fights)
+	report.AvgExp = float64(totalExp) / float64(cfg.Fights)
+	report.AvgGold = float64(totalGold) / float64(cfg.Fights)
+	if hours > 0 {
+		report.ExpPerHour = float64(totalExp) / hours
+		report.GoldPerHour = float64(totalGold) / hours
+	}
+
+	if requiredExp, ok := progression.RequiredExp(fighter.Level + 1); ok && requiredExp > fighter.Exp {
+		report.ExpToLevel = requiredExp - fighter.Exp
+		report.BotsToLevel = botsToLevel(progression, fighter.Level)
+		if report.AvgExp > 0 {
+			report.FightsToLevel = float64(report.ExpToLevel) / report.AvgExp
+		}
+		if report.ExpPerHour > 0 {
+			report.HoursToLevel = float64(report.ExpToLevel) / report.ExpPerHour
+		}
+	}
+
+	return report, nil
+}
+
+// simulateFight returns the number of rounds played and both final HPs.
+func simulateFight(rng Rand, player *domain.User, bot *domain.Bot) (int, uint, uint) {
+	playerHp, botHp := player.Hp, bot.Hp
+	var history []*domain.Round
+
+	for len(history) < maxSimulatedRounds && playerHp > 0 && botHp > 0 {
+		playerAttack, playerDefense := randomStrategy{}.Choose(nil, rng.Intn)
+		res := playRound(rng, player, bot, history, playerHp, botHp, string(playerAttack), string(playerDefense))
+		playerHp, botHp = res.PlayerHp, res.BotHp
+
+		history = append([]*domain.Round{{
+			Status:             domain.RoundStatusFinished,
+			PlayerAttackPoint:  &playerAttack,
+			PlayerDefensePoint: &playerDefense,
+			BotAttackPoint:     &res.BotAttack,
+			BotDefensePoint:    &res.BotDefense,
+			BotAbility:         res.Ability,
+		}}, history...)
+	}
+
+	return len(history), playerHp, botHp
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+)
+
+func TestSimulateFights(t *testing.T) {
+	player := &domain.User{Level: 1, Attack: 10, Defense: 5, Hp: 100}
+	bot := &domain.Bot{Level: 1, Attack: 2, Defense: 10, Hp: 20, Strategy: domain.BotStrategyWeighted}
+
+	run := func(seed int64, items []*domain.EquipmentItem) *SimulationReport {
+		report, err := SimulateFights(player, items, bot, SimulationConfig{Fights: 500, RoundSeconds: 5, Rand: NewRand(seed)})
+		require.NoError(t, err)
+		return report
+	}
+
+	t.Run("same seed gives the same report", func(t *testing.T) {
+		assert.Equal(t, run(42, nil), run(42, nil))
+	})
+
+	t.Run("reports balance numbers", func(t *testing.T) {
+		report := run(1, nil)
+		assert.Equal(t, 500, report.Fights)
+		assert.Equal(t, 1.0, report.WinRate)
+		assert.Greater(t, report.AvgRounds, 1.0)
+		assert.Greater(t, report.ExpPerHour, 0.0)
+		requiredExp, _ := domain.CurrentProgression().RequiredExp(2)
+		assert.Equal(t, requiredExp, report.ExpToLevel)
+		assert.Equal(t, botsToLevel(domain.CurrentProgression(), 1), report.BotsToLevel)
+		assert.Greater(t, report.HoursToLevel, 0.0)
+	})
+
+	t.Run("items add to player stats", func(t *testing.T) {
+		sword := &domain.EquipmentItem{Attack: 20}
+		assert.Less(t, run(1, []*domain.EquipmentItem{sword}).AvgRounds, run(1, nil).AvgRounds)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := SimulateFights(player, nil, bot, SimulationConfig{Fights: 0, RoundSeconds: 5, Rand: NewRand(1)})
+		assert.Equal(t, ErrInvalidSimulation, err)
+	})
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+var (
+	ErrNoStatsToSpend     = errors.New("no stats to spend")
+	ErrNotEnoughFreeStats = errors.New("not enough free stats")
+	ErrNothingToReset     = errors.New("nothing to reset")
+)
+
+type SpendStatsInput struct {
+	Attack  uint
+	Defense uint
+	Hp      uint
+}
+
+func (in SpendStatsInput) Total() uint {
+	return in.Attack + in.Defense + in.Hp
+}
+
+type UserStatsService struct {
+	db       *sqlx.DB
+	userRepo *repository.UserRepository
+}
+
+func NewUserStatsService(db *sqlx.DB, userRepo *repository.UserRepository) *UserStatsService {
+	return &UserStatsService{
+		db:       db,
+		userRepo: userRepo,
+	}
+}
+
+func (s *UserStatsService) SpendStats(ctx context.Context, userID uuid.UUID, input SpendStatsInput) (*domain.User, error) {
+	if input.Total() == 0 {
+		return nil, ErrNoStatsToSpend
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, repository.ErrUserNotFound
+	}
+
+	if user.FreeStats < input.Total() {
+		return nil, ErrNotEnoughFreeStats
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := s.userRepo.SpendStatsWithExt(tx, userID, input.Attack, input.Defense, input.Hp); err != nil {
+		if errors.Is(err, repository.ErrNotEnoughFreeStats) {
+			return nil, ErrNotEnoughFreeStats
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.FindByID(userID)
+}
+
+func (s *UserStatsService) ResetStats(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, repository.ErrUserNotFound
+	}
+
+	if user.AllocatedStats() == 0 {
+		return nil, ErrNothingToReset
+	}
+
+	price := user.StatsResetPrice()
+	if user.Gold < price {
+		return nil, ErrInsufficientGold
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := s.userRepo.ResetStatsWithExt(tx, userID, price); err != nil {
+		if errors.Is(err, repository.ErrNotEnoughGold) {
+			return nil, ErrInsufficientGold
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.FindByID(userID)
+}
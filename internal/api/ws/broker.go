@@ -0,0 +1,15 @@
+package ws
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Broker fans events out between server instances. Every instance, including
+// the publishing one, receives each event through Run and delivers it to its
+// own connections.
+type Broker interface {
+	Publish(userID uuid.UUID, data []byte) error
+	Run(ctx context.Context, deliver func(userID uuid.UUID, data []byte)) error
+}
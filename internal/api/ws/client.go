@@ -0,0 +1,138 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	commandTimeout = 10 * time.Second
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = 30 * time.Second
+	sendBufferSize = 32
+)
+
+// Client is a single socket of a user. Writes go through a buffered channel
+// drained by the client's own goroutine, so a slow socket never blocks the hub.
+type Client struct {
+	userID    uuid.UUID
+	conn      *websocket.Conn
+	hub       *Hub
+	send      chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newClient(hub *Hub, userID uuid.UUID, conn *websocket.Conn) *Client {
+	return &Client{
+		userID: userID,
+		conn:   conn,
+		hub:    hub,
+		send:   make(chan []byte, sendBufferSize),
+		done:   make(chan struct{}),
+	}
+}
+
+func (c *Client) UserID() uuid.UUID {
+	return c.userID
+}
+
+// Run pumps the connection until it is closed and then unregisters the client.
+// Incoming commands are answered through router; with a nil router they are ignored.
+func (c *Client) Run(router *Router) {
+	go c.writePump()
+	c.readPump(router)
+}
+
+// enqueue reports false when the client is closed or its buffer is full.
+func (c *Client) enqueue(data []byte) bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+	}
+
+	select {
+	case c.send <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+func (c *Client) readPump(router *Router) {
+	defer c.hub.Unregister(c)
+
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		if router != nil {
+			c.handleCommand(router, data)
+		}
+	}
+}
+
+func (c *Client) handleCommand(router *Router, data []byte) {
+	var cmd Command
+	var reply Reply
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		reply = Reply{Type: "error", Error: NewCommandError(ErrorCodeBadRequest, "invalid command")}
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		reply = router.Dispatch(ctx, c.userID, cmd)
+		cancel()
+	}
+
+	encoded, err := json.Marshal(reply)
+	if err != nil {
+		return
+	}
+	c.enqueue(encoded)
+}
+
+func (c *Client) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.hub.Unregister(c)
+	}()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case data := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
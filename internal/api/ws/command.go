@@ -0,0 +1,90 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ErrorCodeBadRequest = "bad_request"
+	ErrorCodeNotFound   = "not_found"
+	ErrorCodeInternal   = "internal_error"
+	ErrorCodeUnknown    = "unknown_command"
+)
+
+// Command is a request sent by the client. ID is echoed back in the reply so
+// the client can match responses to requests.
+type Command struct {
+	ID   string          `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// Reply answers a Command with either Data or Error.
+type Reply struct {
+	Type    string        `json:"type"`
+	ID      string        `json:"id"`
+	Command string        `json:"command"`
+	Data    interface{}   `json:"data,omitempty"`
+	Error   *CommandError `json:"error,omitempty"`
+}
+
+type CommandError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *CommandError) Error() string {
+	return e.Message
+}
+
+func NewCommandError(code, message string) *CommandError {
+	return &CommandError{Code: code, Message: message}
+}
+
+type CommandHandler func(ctx context.Context, userID uuid.UUID, data json.RawMessage) (interface{}, error)
+
+// Router dispatches client commands by type.
+type Router struct {
+	handlers map[string]CommandHandler
+}
+
+func NewRouter() *Router {
+	return &Router{handlers: make(map[string]CommandHandler)}
+}
+
+func (r *Router) Handle(commandType string, handler CommandHandler) {
+	r.handlers[commandType] = handler
+}
+
+// Dispatch runs the handler for cmd. Errors that are not a *CommandError are
+// reported as internal errors without leaking their text to the client.
+func (r *Router) Dispatch(ctx context.Context, userID uuid.UUID, cmd Command) Reply {
+	reply := Reply{Type: "response", ID: cmd.ID, Command: cmd.Type}
+
+	handler, exists := r.handlers[cmd.Type]
+	if !exists {
+		reply.Type = "error"
+		reply.Error = NewCommandError(ErrorCodeUnknown, fmt.Sprintf("unknown command %q", cmd.Type))
+		return reply
+	}
+
+	data, err := handler(ctx, userID, cmd.Data)
+	if err != nil {
+		var cmdErr *CommandError
+		if !errors.As(err, &cmdErr) {
+			fmt.Printf("[Router] %s failed for %s: %v\n", cmd.Type, userID, err)
+			cmdErr = NewCommandError(ErrorCodeInternal, "internal server error")
+		}
+		reply.Type = "error"
+		reply.Error = cmdErr
+		return reply
+	}
+
+	reply.Data = data
+	return reply
+}
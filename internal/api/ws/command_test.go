@@ -0,0 +1,94 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_Dispatch(t *testing.T) {
+	userID := uuid.New()
+	router := NewRouter()
+	router.Handle("echo", func(ctx context.Context, id uuid.UUID, data json.RawMessage) (interface{}, error) {
+		assert.Equal(t, userID, id)
+		return data, nil
+	})
+	router.Handle("missing", func(context.Context, uuid.UUID, json.RawMessage) (interface{}, error) {
+		return nil, NewCommandError(ErrorCodeNotFound, "nothing here")
+	})
+	router.Handle("broken", func(context.Context, uuid.UUID, json.RawMessage) (interface{}, error) {
+		return nil, errors.New("db is down")
+	})
+
+	reply := router.Dispatch(context.Background(), userID, Command{ID: "1", Type: "echo", Data: json.RawMessage(`{"a":1}`)})
+	assert.Equal(t, "response", reply.Type)
+	assert.Equal(t, "1", reply.ID)
+	assert.Nil(t, reply.Error)
+
+	reply = router.Dispatch(context.Background(), userID, Command{ID: "2", Type: "missing"})
+	assert.Equal(t, "error", reply.Type)
+	require.NotNil(t, reply.Error)
+	assert.Equal(t, ErrorCodeNotFound, reply.Error.Code)
+
+	reply = router.Dispatch(context.Background(), userID, Command{ID: "3", Type: "broken"})
+	require.NotNil(t, reply.Error)
+	assert.Equal(t, ErrorCodeInternal, reply.Error.Code)
+	assert.NotContains(t, reply.Error.Message, "db is down")
+
+	reply = router.Dispatch(context.Background(), userID, Command{ID: "4", Type: "nope"})
+	require.NotNil(t, reply.Error)
+	assert.Equal(t, ErrorCodeUnknown, reply.Error.Code)
+}
+
+func TestClient_AnswersCommands(t *testing.T) {
+	userID := uuid.New()
+	hub := NewHub()
+	router := NewRouter()
+	router.Handle("ping", func(context.Context, uuid.UUID, json.RawMessage) (interface{}, error) {
+		return "pong", nil
+	})
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		go hub.Register(userID, conn).Run(router)
+	}))
+	defer server.Close()
+
+	conn := dialTestHub(t, "ws"+strings.TrimPrefix(server.URL, "http"))
+
+	require.NoError(t, conn.WriteJSON(Command{ID: "abc", Type: "ping"}))
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	var reply struct {
+		Type    string `json:"type"`
+		ID      string `json:"id"`
+		Command string `json:"command"`
+		Data    string `json:"data"`
+	}
+	require.NoError(t, conn.ReadJSON(&reply))
+	assert.Equal(t, "response", reply.Type)
+	assert.Equal(t, "abc", reply.ID)
+	assert.Equal(t, "ping", reply.Command)
+	assert.Equal(t, "pong", reply.Data)
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("not json")))
+	var errReply Reply
+	require.NoError(t, conn.ReadJSON(&errReply))
+	assert.Equal(t, "error", errReply.Type)
+	require.NotNil(t, errReply.Error)
+	assert.Equal(t, ErrorCodeBadRequest, errReply.Error.Code)
+}
@@ -0,0 +1,75 @@
+package ws
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EventHPUpdate     EventType = "hp_update"
+	EventFightRound   EventType = "fight_round"
+	EventMovementStep EventType = "movement_step"
+	EventLevelUp      EventType = "level_up"
+	EventDuelUpdate   EventType = "duel_update"
+	EventGroupFight   EventType = "group_fight_update"
+)
+
+// Event is the envelope every message pushed to clients is wrapped in.
+type Event struct {
+	Type EventType   `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// Publisher delivers events to all connections of a user. Services depend on
+// this interface rather than on the hub and its socket library.
+type Publisher interface {
+	Publish(userID uuid.UUID, event Event)
+}
+
+type HPUpdateData struct {
+	CurrentHp uint `json:"currentHp"`
+	Hp        uint `json:"hp"`
+}
+
+type MovementStepData struct {
+	Cell          string     `json:"cell"`
+	RemainingPath []string   `json:"remainingPath"`
+	NextStepAt    *time.Time `json:"nextStepAt,omitempty"`
+	Arrived       bool       `json:"arrived"`
+}
+
+type LevelUpData struct {
+	Level     uint `json:"level"`
+	FreeStats uint `json:"freeStats"`
+}
+
+func HPUpdateEvent(currentHp, hp uint) Event {
+	return Event{Type: EventHPUpdate, Data: HPUpdateData{CurrentHp: currentHp, Hp: hp}}
+}
+
+func MovementStepEvent(data MovementStepData) Event {
+	return Event{Type: EventMovementStep, Data: data}
+}
+
+func LevelUpEvent(level, freeStats uint) Event {
+	return Event{Type: EventLevelUp, Data: LevelUpData{Level: level, FreeStats: freeStats}}
+}
+
+// FightRoundEvent carries the fight state after a round, usually a dto.Fight.
+func FightRoundEvent(fight interface{}) Event {
+	return Event{Type: EventFightRound, Data: fight}
+}
+
+// DuelUpdateEvent carries the duel state after any change, usually a dto.Duel.
+func DuelUpdateEvent(duel interface{}) Event {
+	return Event{Type: EventDuelUpdate, Data: duel}
+}
+
+// GroupFightUpdateEvent carries the group fight state after any change,
+// usually a dto.GroupFight. Every member receives it.
+func GroupFightUpdateEvent(fight interface{}) Event {
+	return Event{Type: EventGroupFight, Data: fight}
+}
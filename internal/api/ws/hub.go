@@ -1,28 +1,19 @@
 package ws
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"sync"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
-type Message struct {
-	Type string      `json:"type"`
-	Data interface{} `json:"data"`
-}
-
-type HPUpdateData struct {
-	CurrentHp uint `json:"currentHp"`
-	Hp        uint `json:"hp"`
-}
-
 type Hub struct {
-	connections map[uuid.UUID]*websocket.Conn
-	mu          sync.RWMutex
+	clients map[uuid.UUID]map[*Client]struct{}
+	broker  Broker
+	mu      sync.RWMutex
 }
 
 var globalHub *Hub
@@ -30,83 +21,147 @@ var once sync.Once
 
 func GetHub() *Hub {
 	once.Do(func() {
-		globalHub = &Hub{
-			connections: make(map[uuid.UUID]*websocket.Conn),
-		}
+		globalHub = NewHub()
 	})
 	return globalHub
 }
 
-func (h *Hub) Register(userID uuid.UUID, conn *websocket.Conn) {
+func NewHub() *Hub {
+	return &Hub{
+		clients: make(map[uuid.UUID]map[*Client]struct{}),
+	}
+}
+
+// Register adds a connection to the user's set. The caller runs the returned client.
+func (h *Hub) Register(userID uuid.UUID, conn *websocket.Conn) *Client {
+	client := newClient(h, userID, conn)
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	h.connections[userID] = conn
-	fmt.Printf("[Hub] User %s connected. Total connections: %d\n", userID, len(h.connections))
+	if h.clients[userID] == nil {
+		h.clients[userID] = make(map[*Client]struct{})
+	}
+	h.clients[userID][client] = struct{}{}
+	fmt.Printf("[Hub] User %s connected (%d connections)\n", userID, len(h.clients[userID]))
+
+	return client
 }
 
-func (h *Hub) Unregister(userID uuid.UUID) {
+func (h *Hub) Unregister(client *Client) {
+	client.close()
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	if conn, exists := h.connections[userID]; exists {
-		conn.Close()
-		delete(h.connections, userID)
-		fmt.Printf("[Hub] User %s disconnected. Total connections: %d\n", userID, len(h.connections))
+	conns, exists := h.clients[client.userID]
+	if !exists {
+		return
+	}
+	if _, exists := conns[client]; !exists {
+		return
+	}
+
+	delete(conns, client)
+	if len(conns) == 0 {
+		delete(h.clients, client.userID)
 	}
+	fmt.Printf("[Hub] User %s disconnected (%d connections)\n", client.userID, len(conns))
 }
 
-func (h *Hub) SendToUser(userID uuid.UUID, msg Message) error {
+// UseBroker routes published events through b so users connected to other
+// instances receive them too. It blocks until ctx is done.
+func (h *Hub) UseBroker(ctx context.Context, b Broker) error {
+	h.mu.Lock()
+	h.broker = b
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		h.broker = nil
+		h.mu.Unlock()
+	}()
+
+	return b.Run(ctx, h.deliver)
+}
+
+// Publish sends the event to every connection of the user, on any instance
+// when a broker is in use. If the broker fails, only local connections get it.
+func (h *Hub) Publish(userID uuid.UUID, event Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		fmt.Printf("[Hub] Error encoding %s event: %v\n", event.Type, err)
+		return
+	}
+
 	h.mu.RLock()
-	conn, exists := h.connections[userID]
+	broker := h.broker
 	h.mu.RUnlock()
 
-	if !exists {
-		return nil
+	if broker != nil {
+		err := broker.Publish(userID, data)
+		if err == nil {
+			return
+		}
+		fmt.Printf("[Hub] Error publishing %s event: %v\n", event.Type, err)
 	}
 
-	data, err := json.Marshal(msg)
+	h.deliver(userID, data)
+}
+
+// PublishLocal sends the event only to the user's connections on this
+// instance. It suits events every instance produces for its own users.
+func (h *Hub) PublishLocal(userID uuid.UUID, event Event) {
+	data, err := json.Marshal(event)
 	if err != nil {
-		return err
+		fmt.Printf("[Hub] Error encoding %s event: %v\n", event.Type, err)
+		return
 	}
 
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
-	conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-	return conn.WriteMessage(websocket.TextMessage, data)
+	h.deliver(userID, data)
 }
 
-func (h *Hub) SendHPUpdate(userID uuid.UUID, currentHp, hp uint) error {
-	msg := Message{
-		Type: "hp_update",
-		Data: HPUpdateData{
-			CurrentHp: currentHp,
-			Hp:        hp,
-		},
+// deliver queues data on the local connections of the user. Connections whose
+// buffer is full are dropped instead of blocking the caller.
+func (h *Hub) deliver(userID uuid.UUID, data []byte) {
+	h.mu.RLock()
+	var stale []*Client
+	for client := range h.clients[userID] {
+		if !client.enqueue(data) {
+			stale = append(stale, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, client := range stale {
+		h.Unregister(client)
 	}
-	return h.SendToUser(userID, msg)
 }
 
 func (h *Hub) IsConnected(userID uuid.UUID) bool {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
-	_, exists := h.connections[userID]
+	_, exists := h.clients[userID]
 	return exists
 }
 
 func (h *Hub) ConnectionCount() int {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
-	return len(h.connections)
+
+	count := 0
+	for _, conns := range h.clients {
+		count += len(conns)
+	}
+	return count
 }
 
 func (h *Hub) GetConnectedUserIDs() []uuid.UUID {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
-	
-	userIDs := make([]uuid.UUID, 0, len(h.connections))
-	for userID := range h.connections {
+
+	userIDs := make([]uuid.UUID, 0, len(h.clients))
+	for userID := range h.clients {
 		userIDs = append(userIDs, userID)
 	}
 	return userIDs
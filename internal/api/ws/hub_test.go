@@ -0,0 +1,154 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startTestHub(t *testing.T, userID uuid.UUID) (*Hub, string) {
+	hub := NewHub()
+	upgrader := websocket.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		go hub.Register(userID, conn).Run(nil)
+	}))
+	t.Cleanup(server.Close)
+
+	return hub, "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func dialTestHub(t *testing.T, url string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestHub_PublishToAllConnections(t *testing.T) {
+	userID := uuid.New()
+	hub, url := startTestHub(t, userID)
+
+	first := dialTestHub(t, url)
+	second := dialTestHub(t, url)
+
+	require.Eventually(t, func() bool { return hub.ConnectionCount() == 2 }, time.Second, 10*time.Millisecond)
+	assert.True(t, hub.IsConnected(userID))
+
+	hub.Publish(userID, HPUpdateEvent(15, 20))
+
+	for _, conn := range []*websocket.Conn{first, second} {
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+
+		var event struct {
+			Type EventType    `json:"type"`
+			Data HPUpdateData `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(data, &event))
+		assert.Equal(t, EventHPUpdate, event.Type)
+		assert.Equal(t, uint(15), event.Data.CurrentHp)
+	}
+}
+
+func TestHub_UnregisterOnClose(t *testing.T) {
+	userID := uuid.New()
+	hub, url := startTestHub(t, userID)
+
+	first := dialTestHub(t, url)
+	dialTestHub(t, url)
+	require.Eventually(t, func() bool { return hub.ConnectionCount() == 2 }, time.Second, 10*time.Millisecond)
+
+	first.Close()
+
+	require.Eventually(t, func() bool { return hub.ConnectionCount() == 1 }, time.Second, 10*time.Millisecond)
+	assert.True(t, hub.IsConnected(userID))
+}
+
+type fakeBroker struct {
+	published chan []byte
+	remote    chan []byte
+	userID    uuid.UUID
+	err       error
+}
+
+func (b *fakeBroker) Publish(userID uuid.UUID, data []byte) error {
+	if b.err != nil {
+		return b.err
+	}
+	b.published <- data
+	return nil
+}
+
+func (b *fakeBroker) Run(ctx context.Context, deliver func(userID uuid.UUID, data []byte)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case data := <-b.remote:
+			deliver(b.userID, data)
+		}
+	}
+}
+
+func TestHub_UseBroker(t *testing.T) {
+	userID := uuid.New()
+	hub, url := startTestHub(t, userID)
+	conn := dialTestHub(t, url)
+	require.Eventually(t, func() bool { return hub.IsConnected(userID) }, time.Second, 10*time.Millisecond)
+
+	broker := &fakeBroker{published: make(chan []byte, 1), remote: make(chan []byte, 1), userID: userID}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.UseBroker(ctx, broker)
+
+	require.Eventually(t, func() bool {
+		hub.mu.RLock()
+		defer hub.mu.RUnlock()
+		return hub.broker != nil
+	}, time.Second, 10*time.Millisecond)
+
+	hub.Publish(userID, LevelUpEvent(2, 3))
+	published := <-broker.published
+	assert.Contains(t, string(published), string(EventLevelUp))
+
+	broker.remote <- published
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.JSONEq(t, string(published), string(data))
+
+	t.Run("local events skip the broker", func(t *testing.T) {
+		hub.PublishLocal(userID, HPUpdateEvent(3, 4))
+
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.Contains(t, string(data), string(EventHPUpdate))
+		assert.Empty(t, broker.published)
+	})
+
+	t.Run("falls back to local delivery", func(t *testing.T) {
+		broker.err = errors.New("broker down")
+		hub.Publish(userID, HPUpdateEvent(1, 2))
+
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.Contains(t, string(data), string(EventHPUpdate))
+	})
+}
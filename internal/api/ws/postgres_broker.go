@@ -0,0 +1,186 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	postgresBrokerChannel = "ws_events"
+
+	// maxNotifyPayload stays below the 8000 byte NOTIFY limit. Larger events
+	// are only sent by id and read back from ws_events.
+	maxNotifyPayload = 7500
+
+	// wsEventsRetention is how long stored events are kept for slow listeners
+	// and for replay after a reconnect.
+	wsEventsRetention = time.Minute
+)
+
+type postgresNotification struct {
+	UserID uuid.UUID       `json:"userId"`
+	Event  json.RawMessage `json:"event,omitempty"`
+	Ref    int64           `json:"ref"`
+}
+
+type storedEvent struct {
+	ID      int64     `db:"id"`
+	UserID  uuid.UUID `db:"user_id"`
+	Payload string    `db:"payload"`
+}
+
+// PostgresBroker delivers events to every instance via LISTEN/NOTIFY. Each
+// event is also stored in ws_events, so a listener that connects late or
+// reconnects replays what it missed. Events older than wsEventsRetention are
+// gone by then; clients refetch state when their socket reconnects.
+type PostgresBroker struct {
+	db  *sqlx.DB
+	dsn string
+}
+
+func NewPostgresBroker(db *sqlx.DB, dsn string) *PostgresBroker {
+	return &PostgresBroker{db: db, dsn: dsn}
+}
+
+func (b *PostgresBroker) Publish(userID uuid.UUID, data []byte) error {
+	var id int64
+	query := `INSERT INTO ws_events (user_id, payload) VALUES ($1, $2) RETURNING id`
+	if err := b.db.QueryRow(query, userID, string(data)).Scan(&id); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(postgresNotification{UserID: userID, Event: data, Ref: id})
+	if err != nil {
+		return err
+	}
+
+	if len(payload) > maxNotifyPayload {
+		payload, err = json.Marshal(postgresNotification{UserID: userID, Ref: id})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = b.db.Exec(`SELECT pg_notify($1, $2)`, postgresBrokerChannel, string(payload))
+	return err
+}
+
+func (b *PostgresBroker) cleanup() {
+	query := `DELETE FROM ws_events WHERE created_at < CURRENT_TIMESTAMP - $1 * INTERVAL '1 second'`
+	if _, err := b.db.Exec(query, wsEventsRetention.Seconds()); err != nil {
+		fmt.Printf("[PostgresBroker] Error cleaning up events: %v\n", err)
+	}
+}
+
+func (b *PostgresBroker) Run(ctx context.Context, deliver func(userID uuid.UUID, data []byte)) error {
+	// Everything stored after this point is replayed once LISTEN is up, so
+	// events published while it was being set up are not lost.
+	var lastID int64
+	if err := b.db.Get(&lastID, `SELECT COALESCE(MAX(id), 0) FROM ws_events`); err != nil {
+		return err
+	}
+
+	listener := pq.NewListener(b.dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			fmt.Printf("[PostgresBroker] Listener event %d: %v\n", ev, err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(postgresBrokerChannel); err != nil {
+		return err
+	}
+
+	r := &replayer{lastID: lastID}
+	r.replay(b, deliver)
+
+	ping := time.NewTicker(90 * time.Second)
+	defer ping.Stop()
+
+	cleanup := time.NewTicker(wsEventsRetention)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ping.C:
+			go listener.Ping()
+		case <-cleanup.C:
+			b.cleanup()
+		case n := <-listener.Notify:
+			// A nil notification means the connection was re-established;
+			// whatever was sent in between is read back from ws_events.
+			if n == nil {
+				r.replay(b, deliver)
+				continue
+			}
+			b.handle(n.Extra, r, deliver)
+		}
+	}
+}
+
+// replayer remembers the newest event id seen and which events the last
+// replay delivered, so their notifications are not delivered twice.
+type replayer struct {
+	lastID   int64
+	replayed map[int64]struct{}
+}
+
+func (r *replayer) replay(b *PostgresBroker, deliver func(userID uuid.UUID, data []byte)) {
+	var events []storedEvent
+	query := `SELECT id, user_id, payload FROM ws_events WHERE id > $1 ORDER BY id`
+	if err := b.db.Select(&events, query, r.lastID); err != nil {
+		fmt.Printf("[PostgresBroker] Error replaying events after %d: %v\n", r.lastID, err)
+		return
+	}
+
+	r.replayed = make(map[int64]struct{}, len(events))
+	for _, event := range events {
+		r.replayed[event.ID] = struct{}{}
+		r.lastID = event.ID
+		deliver(event.UserID, []byte(event.Payload))
+	}
+}
+
+// seen records id and reports whether the event was already replayed.
+func (r *replayer) seen(id int64) bool {
+	if id > r.lastID {
+		r.lastID = id
+	}
+	if _, ok := r.replayed[id]; ok {
+		delete(r.replayed, id)
+		return true
+	}
+	return false
+}
+
+func (b *PostgresBroker) handle(payload string, r *replayer, deliver func(userID uuid.UUID, data []byte)) {
+	var notification postgresNotification
+	if err := json.Unmarshal([]byte(payload), &notification); err != nil {
+		fmt.Printf("[PostgresBroker] Error decoding notification: %v\n", err)
+		return
+	}
+
+	if r.seen(notification.Ref) {
+		return
+	}
+
+	data := []byte(notification.Event)
+	if len(data) == 0 {
+		var stored string
+		if err := b.db.Get(&stored, `SELECT payload FROM ws_events WHERE id = $1`, notification.Ref); err != nil {
+			fmt.Printf("[PostgresBroker] Error loading event %d: %v\n", notification.Ref, err)
+			return
+		}
+		data = []byte(stored)
+	}
+
+	deliver(notification.UserID, data)
+}
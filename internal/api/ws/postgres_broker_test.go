@@ -0,0 +1,19 @@
+package ws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayer_Seen(t *testing.T) {
+	r := &replayer{lastID: 5, replayed: map[int64]struct{}{4: {}, 5: {}}}
+
+	assert.True(t, r.seen(5), "replayed events are not delivered again")
+	assert.False(t, r.seen(5), "a replayed event is skipped only once")
+	assert.False(t, r.seen(7))
+	assert.Equal(t, int64(7), r.lastID)
+
+	assert.False(t, r.seen(6), "events that arrive out of order are still delivered")
+	assert.Equal(t, int64(7), r.lastID)
+}
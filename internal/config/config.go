@@ -1,14 +1,39 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
 	Env      string
 	HTTPAddr string
 	JWTKey   string
-	Database DatabaseConfig
+	WSBroker string
+	// ProgressionFile points at a level curve JSON; empty uses the built-in one.
+	ProgressionFile string
+	Database        DatabaseConfig
+	Fight           FightConfig
+}
+
+type FightConfig struct {
+	// RoundTimeout is how long a round waits for the player before it is
+	// resolved on their behalf.
+	RoundTimeout time.Duration
+	// MaxMissedRounds is how many rounds in a row may time out before the
+	// player forfeits the fight.
+	MaxMissedRounds int
+	// DefeatGoldPenaltyPercent and DefeatExpPenaltyPercent are the share of
+	// gold and of the exp towards the next level a player loses when a bot
+	// beats them; 0 turns the penalty off.
+	DefeatGoldPenaltyPercent uint
+	DefeatExpPenaltyPercent  uint
+	// WeakenedFor is how long a beaten player fights with WeakenedPercent
+	// less attack and defense.
+	WeakenedFor     time.Duration
+	WeakenedPercent uint
 }
 
 type DatabaseConfig struct {
@@ -22,9 +47,11 @@ type DatabaseConfig struct {
 
 func Load() *Config {
 	return &Config{
-		Env:      getEnv("ENV", "development"),
-		HTTPAddr: normalizeAddr(getEnv("HTTP_ADDR", ":8080")),
-		JWTKey:   getEnv("JWT_KEY", "secret"),
+		Env:             getEnv("ENV", "development"),
+		HTTPAddr:        normalizeAddr(getEnv("HTTP_ADDR", ":8080")),
+		JWTKey:          getEnv("JWT_KEY", "secret"),
+		WSBroker:        getEnv("WS_BROKER", "postgres"),
+		ProgressionFile: os.Getenv("PROGRESSION_FILE"),
 		Database: DatabaseConfig{
 			Host:     getEnv("DATABASE_HOST", "localhost"),
 			Port:     getEnv("DATABASE_PORT", "5433"),
@@ -33,9 +60,25 @@ func Load() *Config {
 			Name:     getEnv("DATABASE_NAME", "moonshine"),
 			SSLMode:  getEnv("DATABASE_SSL_MODE", "disable"),
 		},
+		Fight: FightConfig{
+			RoundTimeout:    getEnvDuration("FIGHT_ROUND_TIMEOUT", 60*time.Second),
+			MaxMissedRounds: getEnvInt("FIGHT_MAX_MISSED_ROUNDS", 3),
+
+			DefeatGoldPenaltyPercent: getEnvUint("FIGHT_DEFEAT_GOLD_PENALTY_PERCENT", 10),
+			DefeatExpPenaltyPercent:  getEnvUint("FIGHT_DEFEAT_EXP_PENALTY_PERCENT", 0),
+			WeakenedFor:              getEnvDuration("FIGHT_WEAKENED_DURATION", 5*time.Minute),
+			WeakenedPercent:          getEnvUint("FIGHT_WEAKENED_PERCENT", 20),
+		},
 	}
 }
 
+func (d DatabaseConfig) DSN() string {
+	return fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		d.Host, d.Port, d.User, d.Password, d.Name, d.SSLMode,
+	)
+}
+
 func (c *Config) IsProduction() bool {
 	return c.Env == "production" || c.Env == "prod"
 }
@@ -47,6 +90,28 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d > 0 {
+		return d
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	if n, err := strconv.Atoi(os.Getenv(key)); err == nil && n > 0 {
+		return n
+	}
+	return fallback
+}
+
+// getEnvUint accepts 0, unlike getEnvInt, so a value can be switched off.
+func getEnvUint(key string, fallback uint) uint {
+	if n, err := strconv.ParseUint(os.Getenv(key), 10, 0); err == nil {
+		return uint(n)
+	}
+	return fallback
+}
+
 func normalizeAddr(addr string) string {
 	if addr == "" {
 		return addr
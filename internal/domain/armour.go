@@ -0,0 +1,50 @@
+package domain
+
+// Armour is the defense equipped items give to the body part they cover. A
+// hit on a covered part is reduced by it whether or not the player blocked
+// there.
+type Armour struct {
+	Head  uint `db:"head"`
+	Neck  uint `db:"neck"`
+	Chest uint `db:"chest"`
+	Belt  uint `db:"belt"`
+	Legs  uint `db:"legs"`
+}
+
+func (a Armour) At(part BodyPart) uint {
+	switch part {
+	case BodyPartHead:
+		return a.Head
+	case BodyPartNeck:
+		return a.Neck
+	case BodyPartChest:
+		return a.Chest
+	case BodyPartBelt:
+		return a.Belt
+	case BodyPartLegs:
+		return a.Legs
+	default:
+		return 0
+	}
+}
+
+func (a Armour) Total() uint {
+	return a.Head + a.Neck + a.Chest + a.Belt + a.Legs
+}
+
+// Cover adds the defense of an item of the given equipment type to the part
+// it covers; items for other slots do not cover a body part.
+func (a *Armour) Cover(equipmentType string, defense uint) {
+	switch equipmentType {
+	case "head":
+		a.Head += defense
+	case "neck":
+		a.Neck += defense
+	case "chest":
+		a.Chest += defense
+	case "belt":
+		a.Belt += defense
+	case "legs":
+		a.Legs += defense
+	}
+}
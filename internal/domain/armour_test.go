@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArmour_Cover(t *testing.T) {
+	var armour Armour
+	armour.Cover("head", 3)
+	armour.Cover("legs", 2)
+	armour.Cover("weapon", 10)
+	armour.Cover("head", 1)
+
+	assert.Equal(t, uint(4), armour.At(BodyPartHead))
+	assert.Equal(t, uint(2), armour.At(BodyPartLegs))
+	assert.Equal(t, uint(0), armour.At(BodyPartChest))
+	assert.Equal(t, uint(6), armour.Total(), "items off the body give no armour")
+}
+
+func TestUser_BlockDefense(t *testing.T) {
+	user := &User{Defense: 10, Armour: Armour{Head: 3, Chest: 4}}
+	assert.Equal(t, uint(3), user.BlockDefense())
+
+	user.Armour.Legs = 5
+	assert.Equal(t, uint(0), user.BlockDefense())
+}
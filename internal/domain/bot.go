@@ -1,12 +1,62 @@
 package domain
 
+type BotStrategy string
+
+const (
+	BotStrategyRandom      BotStrategy = "RANDOM"
+	BotStrategyFavoursHead BotStrategy = "FAVOURS_HEAD"
+	BotStrategyMirror      BotStrategy = "MIRROR"
+	BotStrategyWeighted    BotStrategy = "WEIGHTED"
+)
+
 type Bot struct {
 	Model
-	Name    string `db:"name"`
-	Slug    string `db:"slug"`
-	Avatar  string `db:"avatar"`
-	Attack  uint `db:"attack"`
-	Defense uint `db:"defense"`
-	Hp      uint `db:"hp"`
-	Level   uint `db:"level"`
+	Name     string      `db:"name"`
+	Slug     string      `db:"slug"`
+	Avatar   string      `db:"avatar"`
+	Attack   uint        `db:"attack"`
+	Defense  uint        `db:"defense"`
+	Hp       uint        `db:"hp"`
+	Level    uint        `db:"level"`
+	Strategy BotStrategy `db:"strategy"`
+	IsBoss   bool        `db:"is_boss"`
+	// KillCooldownSeconds is how long a player has to wait after killing a
+	// boss before attacking it again.
+	KillCooldownSeconds uint `db:"kill_cooldown_seconds"`
+	// CombatStats are the bot's own, it wears no items.
+	CombatStats
+	Phases []*BotPhase `db:"-"`
+}
+
+// PhaseAt returns the phase a boss is in with hp left: the deepest one whose
+// threshold has been reached, or nil while it is above all of them.
+func (b *Bot) PhaseAt(hp uint) *BotPhase {
+	var active *BotPhase
+	for _, phase := range b.Phases {
+		if !phase.ReachedAt(hp, b.Hp) {
+			continue
+		}
+		if active == nil || phase.HpPercent < active.HpPercent {
+			active = phase
+		}
+	}
+	return active
+}
+
+// WithPhase returns a copy of the bot with the phase's overrides applied.
+func (b *Bot) WithPhase(phase *BotPhase) *Bot {
+	bot := *b
+	if phase == nil {
+		return &bot
+	}
+	if phase.Attack != nil {
+		bot.Attack = *phase.Attack
+	}
+	if phase.Defense != nil {
+		bot.Defense = *phase.Defense
+	}
+	if phase.Strategy != nil {
+		bot.Strategy = *phase.Strategy
+	}
+	return &bot
 }
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BotInstanceState string
+
+const (
+	BotInstanceStateAlive   BotInstanceState = "ALIVE"
+	BotInstanceStateInFight BotInstanceState = "IN_FIGHT"
+	BotInstanceStateDead    BotInstanceState = "DEAD"
+)
+
+// BotInstance is one spawned copy of a bot in a location. A location_bots
+// link owns MaxPopulation slots; a killed instance stays DEAD until
+// RespawnAt and only one player can fight an instance at a time.
+type BotInstance struct {
+	Model
+	LocationBotID uuid.UUID        `db:"location_bot_id"`
+	LocationID    uuid.UUID        `db:"location_id"`
+	BotID         uuid.UUID        `db:"bot_id"`
+	Slot          int              `db:"slot"`
+	State         BotInstanceState `db:"state"`
+	CurrentHp     uint             `db:"current_hp"`
+	FightID       *uuid.UUID       `db:"fight_id"`
+	GroupFightID  *uuid.UUID       `db:"group_fight_id"`
+	RespawnAt     *time.Time       `db:"respawn_at"`
+	Bot           *Bot             `db:"-"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "github.com/google/uuid"
+
+type BotLoot struct {
+	Model
+	BotID           uuid.UUID `db:"bot_id"`
+	EquipmentItemID uuid.UUID `db:"equipment_item_id"`
+	Weight          uint      `db:"weight"`
+	MinLevel        uint      `db:"min_level"`
+	MaxLevel        *uint     `db:"max_level"`
+}
+
+func (loot *BotLoot) AvailableFor(level uint) bool {
+	if level < loot.MinLevel {
+		return false
+	}
+	if loot.MaxLevel != nil && level > *loot.MaxLevel {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "github.com/google/uuid"
+
+type BotAbility string
+
+const (
+	// BotAbilityDoubleStrike hits the player twice at the same point.
+	BotAbilityDoubleStrike BotAbility = "DOUBLE_STRIKE"
+	// BotAbilityHeal restores AbilityPower HP after the player's hit, unless
+	// the hit was fatal.
+	BotAbilityHeal BotAbility = "HEAL"
+	// BotAbilityUnblockableHead attacks the head and ignores the player's
+	// defense.
+	BotAbilityUnblockableHead BotAbility = "UNBLOCKABLE_HEAD"
+)
+
+// BotPhase changes a boss once its HP drops to HpPercent of its maximum.
+// Nil stats keep the bot's own; the ability fires with AbilityChance percent
+// each round.
+type BotPhase struct {
+	Model
+	BotID         uuid.UUID    `db:"bot_id"`
+	HpPercent     uint         `db:"hp_percent"`
+	Attack        *uint        `db:"attack"`
+	Defense       *uint        `db:"defense"`
+	Strategy      *BotStrategy `db:"strategy"`
+	Ability       *BotAbility  `db:"ability"`
+	AbilityChance uint         `db:"ability_chance"`
+	AbilityPower  uint         `db:"ability_power"`
+}
+
+func (p *BotPhase) ReachedAt(hp, maxHp uint) bool {
+	return hp*100 <= p.HpPercent*maxHp
+}
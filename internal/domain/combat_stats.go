@@ -0,0 +1,22 @@
+package domain
+
+// CombatStats are the secondary stats, all in percent. Items carry them and a
+// user fights with the sum of what they wear, except BlockChance, which only
+// the shield slot gives.
+type CombatStats struct {
+	CritChance uint `db:"crit_chance"`
+	// CritPower is extra damage on top of BaseCritPower.
+	CritPower   uint `db:"crit_power"`
+	DodgeChance uint `db:"dodge_chance"`
+	BlockChance uint `db:"block_chance"`
+}
+
+const (
+	// BaseCritPower is how much more damage any critical hit does.
+	BaseCritPower uint = 50
+	// ShieldBlockPercent is how much of a hit a shield block stops.
+	ShieldBlockPercent uint = 50
+	// MaxCombatChance caps crit, dodge and block chances however much gear
+	// is stacked.
+	MaxCombatChance uint = 75
+)
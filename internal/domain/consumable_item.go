@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConsumableItem is used up in one go: it heals the player and may leave a
+// buff on them for BuffSeconds.
+type ConsumableItem struct {
+	Model
+	Name            string `db:"name"`
+	Slug            string `db:"slug"`
+	Image           string `db:"image"`
+	Price           uint   `db:"price"`
+	RequiredLevel   uint   `db:"required_level"`
+	HealHp          uint   `db:"heal_hp"`
+	BuffAttack      uint   `db:"buff_attack"`
+	BuffDefense     uint   `db:"buff_defense"`
+	BuffCritChance  uint   `db:"buff_crit_chance"`
+	BuffDodgeChance uint   `db:"buff_dodge_chance"`
+	BuffSeconds     uint   `db:"buff_seconds"`
+}
+
+// HasBuff reports whether using the item leaves a buff behind.
+func (item *ConsumableItem) HasBuff() bool {
+	if item.BuffSeconds == 0 {
+		return false
+	}
+	return item.BuffAttack > 0 || item.BuffDefense > 0 || item.BuffCritChance > 0 || item.BuffDodgeChance > 0
+}
+
+// Heal returns currentHp raised by the item, never above maxHp.
+func (item *ConsumableItem) Heal(currentHp, maxHp uint) uint {
+	return min(currentHp+item.HealHp, max(currentHp, maxHp))
+}
+
+// InventoryConsumable is one consumable sitting in a user's inventory; ID is
+// the inventory row, so two potions of the same kind are used one by one.
+type InventoryConsumable struct {
+	Model
+	UserID           uuid.UUID       `db:"user_id"`
+	ConsumableItemID uuid.UUID       `db:"consumable_item_id"`
+	Item             *ConsumableItem `db:"-"`
+}
+
+// UserBuff is what an elixir gives until ExpiresAt.
+type UserBuff struct {
+	Model
+	UserID           uuid.UUID `db:"user_id"`
+	ConsumableItemID uuid.UUID `db:"consumable_item_id"`
+	Attack           uint      `db:"attack"`
+	Defense          uint      `db:"defense"`
+	CritChance       uint      `db:"crit_chance"`
+	DodgeChance      uint      `db:"dodge_chance"`
+	ExpiresAt        time.Time `db:"expires_at"`
+}
+
+func (buff *UserBuff) IsActive(now time.Time) bool {
+	return now.Before(buff.ExpiresAt)
+}
+
+// ApplyTo adds the buff to the user's stats. Buffed defense counts for
+// blocks, not for armour.
+func (buff *UserBuff) ApplyTo(user *User) {
+	user.Attack += buff.Attack
+	user.Defense += buff.Defense
+	user.CombatStats.CritChance += buff.CritChance
+	user.CombatStats.DodgeChance += buff.DodgeChance
+}
+
+// BotConsumableDrop is a percent chance for a bot to drop a consumable when
+// beaten, rolled independently of its equipment loot.
+type BotConsumableDrop struct {
+	Model
+	BotID            uuid.UUID `db:"bot_id"`
+	ConsumableItemID uuid.UUID `db:"consumable_item_id"`
+	Chance           uint      `db:"chance"`
+}
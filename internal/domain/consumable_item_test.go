@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumableItem_Heal(t *testing.T) {
+	potion := &ConsumableItem{HealHp: 30}
+
+	assert.Equal(t, uint(80), potion.Heal(50, 100))
+	assert.Equal(t, uint(100), potion.Heal(90, 100), "never heals above max HP")
+	assert.Equal(t, uint(100), (&ConsumableItem{}).Heal(100, 100))
+}
+
+func TestConsumableItem_HasBuff(t *testing.T) {
+	assert.False(t, (&ConsumableItem{HealHp: 30}).HasBuff())
+	assert.False(t, (&ConsumableItem{BuffAttack: 5}).HasBuff(), "a buff needs a duration")
+	assert.True(t, (&ConsumableItem{BuffDodgeChance: 10, BuffSeconds: 60}).HasBuff())
+}
+
+func TestUserBuff_ApplyTo(t *testing.T) {
+	now := time.Now()
+	buff := &UserBuff{Attack: 5, Defense: 3, CritChance: 10, DodgeChance: 7, ExpiresAt: now.Add(time.Minute)}
+	user := &User{Attack: 10, Defense: 4, CombatStats: CombatStats{CritChance: 5}}
+
+	buff.ApplyTo(user)
+
+	assert.Equal(t, uint(15), user.Attack)
+	assert.Equal(t, uint(7), user.Defense)
+	assert.Equal(t, uint(15), user.CombatStats.CritChance)
+	assert.Equal(t, uint(7), user.CombatStats.DodgeChance)
+
+	assert.True(t, buff.IsActive(now))
+	assert.False(t, buff.IsActive(now.Add(time.Minute)))
+}
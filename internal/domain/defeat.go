@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+var ErrInvalidDefeatPolicy = errors.New("invalid defeat policy")
+
+// Defeat is what dropping to 0 HP cost a player.
+type Defeat struct {
+	LostGold        uint
+	LostExp         uint
+	RespawnLocation *Location
+	WeakenedUntil   *time.Time
+}
+
+// DefeatPolicy is what a player pays for dropping to 0 HP against a bot:
+// they are carried back to Moonshine, lose a share of their gold and of the
+// exp earned towards the next level, and stay weakened for a while.
+type DefeatPolicy struct {
+	GoldPenaltyPercent uint
+	ExpPenaltyPercent  uint
+	WeakenedFor        time.Duration
+	// WeakenedPercent is how much attack and defense a weakened player
+	// loses.
+	WeakenedPercent uint
+}
+
+var DefaultDefeatPolicy = DefeatPolicy{
+	GoldPenaltyPercent: 10,
+	ExpPenaltyPercent:  0,
+	WeakenedFor:        5 * time.Minute,
+	WeakenedPercent:    20,
+}
+
+var currentDefeatPolicy atomic.Pointer[DefeatPolicy]
+
+func init() {
+	p := DefaultDefeatPolicy
+	currentDefeatPolicy.Store(&p)
+}
+
+func CurrentDefeatPolicy() DefeatPolicy {
+	return *currentDefeatPolicy.Load()
+}
+
+func SetDefeatPolicy(p DefeatPolicy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	currentDefeatPolicy.Store(&p)
+	return nil
+}
+
+func (p DefeatPolicy) Validate() error {
+	if p.GoldPenaltyPercent > 100 || p.ExpPenaltyPercent > 100 || p.WeakenedPercent > 100 {
+		return ErrInvalidDefeatPolicy
+	}
+	if p.WeakenedFor < 0 {
+		return ErrInvalidDefeatPolicy
+	}
+	return nil
+}
+
+func (p DefeatPolicy) GoldPenalty(gold uint) uint {
+	return uint(uint64(gold) * uint64(p.GoldPenaltyPercent) / 100)
+}
+
+// ExpPenalty only takes exp gathered since the current level was reached,
+// so a defeat never costs a level.
+func (p DefeatPolicy) ExpPenalty(progression *Progression, level, exp uint) uint {
+	floor, _ := progression.RequiredExp(level)
+	if exp <= floor {
+		return 0
+	}
+	return uint(uint64(exp-floor) * uint64(p.ExpPenaltyPercent) / 100)
+}
+
+// Weaken returns a copy of the user with the debuff applied to their attack
+// and defense.
+func (p DefeatPolicy) Weaken(user *User) *User {
+	weakened := *user
+	weakened.Attack -= user.Attack * p.WeakenedPercent / 100
+	weakened.Defense -= user.Defense * p.WeakenedPercent / 100
+	return &weakened
+}
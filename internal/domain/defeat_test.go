@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefeatPolicy_Validate(t *testing.T) {
+	assert.NoError(t, DefaultDefeatPolicy.Validate())
+	assert.NoError(t, DefeatPolicy{}.Validate())
+	assert.ErrorIs(t, DefeatPolicy{GoldPenaltyPercent: 101}.Validate(), ErrInvalidDefeatPolicy)
+	assert.ErrorIs(t, DefeatPolicy{WeakenedPercent: 101}.Validate(), ErrInvalidDefeatPolicy)
+	assert.ErrorIs(t, DefeatPolicy{WeakenedFor: -time.Second}.Validate(), ErrInvalidDefeatPolicy)
+}
+
+func TestDefeatPolicy_Penalties(t *testing.T) {
+	policy := DefeatPolicy{GoldPenaltyPercent: 10, ExpPenaltyPercent: 50}
+	progression := CurrentProgression()
+
+	assert.Equal(t, uint(15), policy.GoldPenalty(155))
+	assert.Equal(t, uint(0), policy.GoldPenalty(0))
+
+	t.Run("only exp above the level floor is lost", func(t *testing.T) {
+		assert.Equal(t, uint(30), policy.ExpPenalty(progression, 3, 260))
+		assert.Equal(t, uint(0), policy.ExpPenalty(progression, 3, 200))
+	})
+}
+
+func TestDefeatPolicy_Weaken(t *testing.T) {
+	user := &User{Attack: 10, Defense: 5}
+	weakened := DefeatPolicy{WeakenedPercent: 20}.Weaken(user)
+
+	assert.Equal(t, uint(8), weakened.Attack)
+	assert.Equal(t, uint(4), weakened.Defense)
+	assert.Equal(t, uint(10), user.Attack, "the original user is left untouched")
+}
+
+func TestUser_IsWeakened(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Minute)
+	earlier := now.Add(-time.Minute)
+
+	assert.False(t, (&User{}).IsWeakened(now))
+	assert.True(t, (&User{WeakenedUntil: &later}).IsWeakened(now))
+	assert.False(t, (&User{WeakenedUntil: &earlier}).IsWeakened(now))
+}
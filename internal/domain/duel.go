@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DuelStatus string
+
+const (
+	DuelStatusPending    DuelStatus = "PENDING"
+	DuelStatusInProgress DuelStatus = "IN_PROGRESS"
+	DuelStatusFinished   DuelStatus = "FINISHED"
+	DuelStatusDeclined   DuelStatus = "DECLINED"
+)
+
+type Duel struct {
+	Model
+	ChallengerID uuid.UUID  `db:"challenger_id"`
+	OpponentID   uuid.UUID  `db:"opponent_id"`
+	LocationID   uuid.UUID  `db:"location_id"`
+	Status       DuelStatus `db:"status"`
+	WinnerID     *uuid.UUID `db:"winner_id"`
+	// ChallengerRespawnLocationID and ChallengerWeakenedUntil record where a
+	// beaten challenger was carried to and how long they stay weakened; the
+	// opponent's fields mirror them.
+	ChallengerRespawnLocationID *uuid.UUID   `db:"challenger_respawn_location_id"`
+	ChallengerWeakenedUntil     *time.Time   `db:"challenger_weakened_until"`
+	OpponentRespawnLocationID   *uuid.UUID   `db:"opponent_respawn_location_id"`
+	OpponentWeakenedUntil       *time.Time   `db:"opponent_weakened_until"`
+	Rounds                      []*DuelRound `db:"-"`
+}
+
+func (d *Duel) IsParticipant(userID uuid.UUID) bool {
+	return d.ChallengerID == userID || d.OpponentID == userID
+}
+
+func (d *Duel) IsChallenger(userID uuid.UUID) bool {
+	return d.ChallengerID == userID
+}
+
+type DuelRound struct {
+	Model
+	DuelID                 uuid.UUID   `db:"duel_id"`
+	Status                 RoundStatus `db:"status"`
+	DeadlineAt             time.Time   `db:"deadline_at"`
+	ChallengerHp           uint        `db:"challenger_hp"`
+	OpponentHp             uint        `db:"opponent_hp"`
+	ChallengerDamage       uint        `db:"challenger_damage"`
+	OpponentDamage         uint        `db:"opponent_damage"`
+	ChallengerAttackPoint  *BodyPart   `db:"challenger_attack_point"`
+	ChallengerDefensePoint *BodyPart   `db:"challenger_defense_point"`
+	OpponentAttackPoint    *BodyPart   `db:"opponent_attack_point"`
+	OpponentDefensePoint   *BodyPart   `db:"opponent_defense_point"`
+	// ChallengerCritical marks a critical hit by the challenger;
+	// ChallengerDodged and ChallengerShieldBlocked say how the challenger got
+	// out of the opponent's hit. The opponent's flags mirror them.
+	ChallengerCritical      bool `db:"challenger_critical"`
+	ChallengerDodged        bool `db:"challenger_dodged"`
+	ChallengerShieldBlocked bool `db:"challenger_shield_blocked"`
+	OpponentCritical        bool `db:"opponent_critical"`
+	OpponentDodged          bool `db:"opponent_dodged"`
+	OpponentShieldBlocked   bool `db:"opponent_shield_blocked"`
+}
+
+func (r *DuelRound) ChallengerChose() bool {
+	return r.ChallengerAttackPoint != nil && r.ChallengerDefensePoint != nil
+}
+
+func (r *DuelRound) OpponentChose() bool {
+	return r.OpponentAttackPoint != nil && r.OpponentDefensePoint != nil
+}
@@ -15,4 +15,5 @@ type EquipmentItem struct {
 	EquipmentCategoryID uuid.UUID `db:"equipment_category_id"`
 	Image               string    `db:"image"`
 	EquipmentType       string    `db:"equipment_type"`
+	CombatStats
 }
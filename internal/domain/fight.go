@@ -1,21 +1,52 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type FightStatus string
 
 const (
 	FightStatusInProgress FightStatus = "IN_PROGRESS"
 	FightStatusFinished   FightStatus = "FINISHED"
+	FightStatusFled       FightStatus = "FLED"
+)
+
+// FightOutcome is derived from the rounds of a finished fight; it is empty
+// while the fight is still going.
+type FightOutcome string
+
+const (
+	FightOutcomeWon  FightOutcome = "WON"
+	FightOutcomeLost FightOutcome = "LOST"
+	FightOutcomeFled FightOutcome = "FLED"
 )
 
 type Fight struct {
 	Model
-	UserID        uuid.UUID   `db:"user_id"`
-	BotID         uuid.UUID   `db:"bot_id"`
-	Status        FightStatus `db:"status"`
-	DroppedGold   uint        `db:"dropped_gold"`
-	Exp           uint        `db:"exp"`
-	DroppedItemID *uuid.UUID  `db:"dropped_item_id"`
-	Rounds        []*Round    `db:"-"`
+	UserID        uuid.UUID    `db:"user_id"`
+	BotID         uuid.UUID    `db:"bot_id"`
+	Status        FightStatus  `db:"status"`
+	DroppedGold   uint         `db:"dropped_gold"`
+	Exp           uint         `db:"exp"`
+	DroppedItemID *uuid.UUID   `db:"dropped_item_id"`
+	Outcome       FightOutcome `db:"outcome"`
+	// LostGold, LostExp, RespawnLocationID and WeakenedUntil record what a
+	// defeat cost the player; they stay empty for any other outcome.
+	LostGold          uint       `db:"lost_gold"`
+	LostExp           uint       `db:"lost_exp"`
+	RespawnLocationID *uuid.UUID `db:"respawn_location_id"`
+	WeakenedUntil     *time.Time `db:"weakened_until"`
+	RespawnLocation   *Location  `db:"-"`
+	// DroppedConsumableID is a consumable the bot dropped on top of its
+	// equipment loot.
+	DroppedConsumableID *uuid.UUID      `db:"dropped_consumable_item_id"`
+	DroppedConsumable   *ConsumableItem `db:"-"`
+	// PlayerArmour is what the player's equipment gives each body part.
+	PlayerArmour *Armour        `db:"-"`
+	DroppedItem  *EquipmentItem `db:"-"`
+	Bot          *Bot           `db:"-"`
+	Rounds       []*Round       `db:"-"`
 }
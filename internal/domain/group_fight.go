@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GroupFightStatus string
+
+const (
+	GroupFightStatusInProgress GroupFightStatus = "IN_PROGRESS"
+	GroupFightStatusWon        GroupFightStatus = "WON"
+	GroupFightStatusLost       GroupFightStatus = "LOST"
+)
+
+// GroupFight is a party of players in one location against a single bot.
+// The leader starts it; others in the location join while it is running.
+type GroupFight struct {
+	Model
+	LeaderID      uuid.UUID           `db:"leader_id"`
+	BotID         uuid.UUID           `db:"bot_id"`
+	LocationID    uuid.UUID           `db:"location_id"`
+	Status        GroupFightStatus    `db:"status"`
+	MaxMembers    int                 `db:"max_members"`
+	DroppedItemID *uuid.UUID          `db:"dropped_item_id"`
+	Bot           *Bot                `db:"-"`
+	Members       []*GroupFightMember `db:"-"`
+	Rounds        []*GroupFightRound  `db:"-"`
+}
+
+func (f *GroupFight) Member(userID uuid.UUID) *GroupFightMember {
+	for _, m := range f.Members {
+		if m.UserID == userID {
+			return m
+		}
+	}
+	return nil
+}
+
+// AliveMembers are the members still standing; only they move and can be
+// targeted.
+func (f *GroupFight) AliveMembers() []*GroupFightMember {
+	var alive []*GroupFightMember
+	for _, m := range f.Members {
+		if m.Hp > 0 {
+			alive = append(alive, m)
+		}
+	}
+	return alive
+}
+
+// GroupFightMember tracks a player's HP in the fight, the damage they dealt
+// and, once the bot is dead, their share of the rewards.
+type GroupFightMember struct {
+	ID           uuid.UUID `db:"id"`
+	CreatedAt    time.Time `db:"created_at"`
+	GroupFightID uuid.UUID `db:"group_fight_id"`
+	UserID       uuid.UUID `db:"user_id"`
+	Username     string    `db:"username"`
+	Hp           uint      `db:"hp"`
+	Damage       uint      `db:"damage"`
+	Exp          uint      `db:"exp"`
+	Gold         uint      `db:"gold"`
+	// LostGold, LostExp, RespawnLocationID and WeakenedUntil record what
+	// being knocked out by the bot cost the member.
+	LostGold          uint       `db:"lost_gold"`
+	LostExp           uint       `db:"lost_exp"`
+	RespawnLocationID *uuid.UUID `db:"respawn_location_id"`
+	WeakenedUntil     *time.Time `db:"weakened_until"`
+}
+
+type GroupFightRound struct {
+	Model
+	GroupFightID    uuid.UUID   `db:"group_fight_id"`
+	Status          RoundStatus `db:"status"`
+	DeadlineAt      time.Time   `db:"deadline_at"`
+	BotHp           uint        `db:"bot_hp"`
+	TargetID        *uuid.UUID  `db:"target_id"`
+	BotAttackPoint  *BodyPart   `db:"bot_attack_point"`
+	BotDefensePoint *BodyPart   `db:"bot_defense_point"`
+	BotDamage       uint        `db:"bot_damage"`
+	BotAbility      *BotAbility `db:"bot_ability"`
+	// BotCritical marks a critical hit by the bot; TargetDodged and
+	// TargetShieldBlocked say how the target got out of it.
+	BotCritical         bool              `db:"bot_critical"`
+	TargetDodged        bool              `db:"target_dodged"`
+	TargetShieldBlocked bool              `db:"target_shield_blocked"`
+	Moves               []*GroupFightMove `db:"-"`
+}
+
+func (r *GroupFightRound) Move(userID uuid.UUID) *GroupFightMove {
+	for _, m := range r.Moves {
+		if m.UserID == userID {
+			return m
+		}
+	}
+	return nil
+}
+
+type GroupFightMove struct {
+	ID           uuid.UUID `db:"id"`
+	CreatedAt    time.Time `db:"created_at"`
+	RoundID      uuid.UUID `db:"round_id"`
+	UserID       uuid.UUID `db:"user_id"`
+	AttackPoint  BodyPart  `db:"attack_point"`
+	DefensePoint BodyPart  `db:"defense_point"`
+	Damage       uint      `db:"damage"`
+	// Critical marks a critical hit by the member; BotDodged and
+	// BotShieldBlocked say how the bot got out of it.
+	Critical         bool `db:"critical"`
+	BotDodged        bool `db:"bot_dodged"`
+	BotShieldBlocked bool `db:"bot_shield_blocked"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+// InnHpPerGold is how much HP one gold buys at the inn for a level 1 player;
+// every further level makes the same HP proportionally dearer.
+const InnHpPerGold uint = 10
+
+// InnHealPrice is what a full heal at the inn costs a player of the given
+// level who is missingHp short of their max. Any heal costs at least one
+// gold.
+func InnHealPrice(missingHp, level uint) uint {
+	if missingHp == 0 {
+		return 0
+	}
+	price := (missingHp*max(level, 1) + InnHpPerGold - 1) / InnHpPerGold
+	return max(price, 1)
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInnHealPrice(t *testing.T) {
+	assert.Equal(t, uint(0), InnHealPrice(0, 5), "a full player pays nothing")
+	assert.Equal(t, uint(1), InnHealPrice(1, 1), "any heal costs at least one gold")
+	assert.Equal(t, uint(10), InnHealPrice(100, 1))
+	assert.Equal(t, uint(30), InnHealPrice(100, 3), "higher levels pay more for the same HP")
+	assert.Equal(t, uint(11), InnHealPrice(101, 1), "the price is rounded up")
+	assert.Equal(t, uint(10), InnHealPrice(100, 0))
+}
+
+func TestUser_MissingHp(t *testing.T) {
+	assert.Equal(t, uint(40), (&User{Hp: 100, CurrentHp: 60}).MissingHp())
+	assert.Equal(t, uint(0), (&User{Hp: 100, CurrentHp: 100}).MissingHp())
+	assert.Equal(t, uint(0), (&User{Hp: 100, CurrentHp: 120}).MissingHp())
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobRun is one execution of a scheduled background job.
+type JobRun struct {
+	ID         uuid.UUID `db:"id"`
+	JobName    string    `db:"job_name"`
+	InstanceID string    `db:"instance_id"`
+	StartedAt  time.Time `db:"started_at"`
+	FinishedAt time.Time `db:"finished_at"`
+	DurationMs int64     `db:"duration_ms"`
+	Error      *string   `db:"error"`
+}
+
+func (r *JobRun) Duration() time.Duration {
+	return time.Duration(r.DurationMs) * time.Millisecond
+}
+
+func (r *JobRun) Failed() bool {
+	return r.Error != nil
+}
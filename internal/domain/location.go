@@ -8,9 +8,13 @@ type Location struct {
 	Inactive bool   `db:"inactive"`
 	Image    string `db:"image"`
 	ImageBg  string `db:"image_bg"`
+	// RegenMultiplier scales the HP players standing here get back on
+	// every regeneration tick.
+	RegenMultiplier float64 `db:"regen_multiplier"`
 }
 
 const (
 	WaywardPinesSlug = "wayward_pines"
 	MoonshineSlug    = "moonshine"
+	InnSlug          = "inn"
 )
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Movement is a walk along a path of cells. The user enters Path[StepIndex]
+// at NextStepAt and then one more cell every StepSeconds.
+type Movement struct {
+	Model
+	UserID      uuid.UUID      `db:"user_id"`
+	Path        pq.StringArray `db:"path"`
+	StepIndex   int            `db:"step_index"`
+	StepSeconds int            `db:"step_seconds"`
+	NextStepAt  time.Time      `db:"next_step_at"`
+}
+
+func (m *Movement) StepInterval() time.Duration {
+	return time.Duration(m.StepSeconds) * time.Second
+}
+
+func (m *Movement) RemainingPath() []string {
+	if m.StepIndex >= len(m.Path) {
+		return []string{}
+	}
+	return m.Path[m.StepIndex:]
+}
+
+func (m *Movement) NextCell() string {
+	if m.StepIndex >= len(m.Path) {
+		return ""
+	}
+	return m.Path[m.StepIndex]
+}
+
+func (m *Movement) IsLastStep() bool {
+	return m.StepIndex >= len(m.Path)-1
+}
+
+// ArrivesAt is the moment the user enters the last cell of the path.
+func (m *Movement) ArrivesAt() time.Time {
+	remaining := len(m.RemainingPath())
+	if remaining == 0 {
+		return m.NextStepAt
+	}
+	return m.NextStepAt.Add(time.Duration(remaining-1) * m.StepInterval())
+}
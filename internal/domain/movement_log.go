@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MovementLog struct {
+	ID        uuid.UUID `db:"id"`
+	UserID    uuid.UUID `db:"user_id"`
+	FromCell  *string   `db:"from_cell"`
+	ToCell    string    `db:"to_cell"`
+	CreatedAt time.Time `db:"created_at"`
+}
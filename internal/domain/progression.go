@@ -0,0 +1,185 @@
+package domain
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sync/atomic"
+)
+
+//go:embed progression.json
+var defaultProgression []byte
+
+var ErrInvalidProgression = errors.New("invalid progression")
+
+// Progression holds the level curve: how much total exp every level needs,
+// the free stat points and stats granted on reaching it and how fight exp is
+// scaled.
+// It is loaded from JSON so a season can raise the cap without a deploy.
+type Progression struct {
+	Version      int           `json:"version"`
+	MaxLevel     uint          `json:"maxLevel"`
+	Levels       []LevelReward `json:"levels"`
+	BotsToLevel  BotsToLevel   `json:"botsToLevel"`
+	ExpModifiers ExpModifiers  `json:"expModifiers"`
+	byLevel      map[uint]LevelReward
+}
+
+type LevelReward struct {
+	Level     uint `json:"level"`
+	Exp       uint `json:"exp"`
+	FreeStats uint `json:"freeStats"`
+	Hp        uint `json:"hp"`
+	Attack    uint `json:"attack"`
+	Defense   uint `json:"defense"`
+}
+
+// BotsToLevel is how many same-level bots a player should need to beat to
+// reach the next level: Base * Growth^(level-1).
+type BotsToLevel struct {
+	Base   float64 `json:"base"`
+	Growth float64 `json:"growth"`
+}
+
+// ExpModifiers scale exp by the level gap: each level the bot is above the
+// player adds HigherBotBonus, each level below divides by 1+LowerBotPenalty.
+type ExpModifiers struct {
+	HigherBotBonus  float64 `json:"higherBotBonus"`
+	LowerBotPenalty float64 `json:"lowerBotPenalty"`
+}
+
+var currentProgression atomic.Pointer[Progression]
+
+func init() {
+	p, err := ParseProgression(defaultProgression)
+	if err != nil {
+		panic(err)
+	}
+	currentProgression.Store(p)
+}
+
+// CurrentProgression returns the active curve. It starts as the embedded
+// default and is swapped by SetProgression.
+func CurrentProgression() *Progression {
+	return currentProgression.Load()
+}
+
+func SetProgression(p *Progression) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	currentProgression.Store(p)
+	return nil
+}
+
+// LoadProgression reads a progression file; an empty path gives the
+// embedded default.
+func LoadProgression(path string) (*Progression, error) {
+	if path == "" {
+		return ParseProgression(defaultProgression)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseProgression(data)
+}
+
+func ParseProgression(data []byte) (*Progression, error) {
+	p := &Progression{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProgression, err)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Validate checks that levels run 1..MaxLevel without gaps, start at 0 exp
+// and need strictly more exp each step.
+func (p *Progression) Validate() error {
+	if p.Version <= 0 {
+		return fmt.Errorf("%w: version must be positive", ErrInvalidProgression)
+	}
+	if p.MaxLevel == 0 {
+		return fmt.Errorf("%w: maxLevel must be positive", ErrInvalidProgression)
+	}
+	if len(p.Levels) != int(p.MaxLevel) {
+		return fmt.Errorf("%w: expected %d levels, got %d", ErrInvalidProgression, p.MaxLevel, len(p.Levels))
+	}
+
+	byLevel := make(map[uint]LevelReward, len(p.Levels))
+	for i, l := range p.Levels {
+		if l.Level != uint(i+1) {
+			return fmt.Errorf("%w: level %d is out of order", ErrInvalidProgression, l.Level)
+		}
+		if i == 0 && l.Exp != 0 {
+			return fmt.Errorf("%w: level 1 must require 0 exp", ErrInvalidProgression)
+		}
+		if i > 0 && l.Exp <= p.Levels[i-1].Exp {
+			return fmt.Errorf("%w: level %d must require more exp than level %d", ErrInvalidProgression, l.Level, l.Level-1)
+		}
+		byLevel[l.Level] = l
+	}
+
+	if p.BotsToLevel.Base < 1 || p.BotsToLevel.Growth < 1 {
+		return fmt.Errorf("%w: botsToLevel base and growth must be at least 1", ErrInvalidProgression)
+	}
+	if p.ExpModifiers.HigherBotBonus < 0 || p.ExpModifiers.LowerBotPenalty < 0 {
+		return fmt.Errorf("%w: exp modifiers must not be negative", ErrInvalidProgression)
+	}
+
+	p.byLevel = byLevel
+	return nil
+}
+
+// RequiredExp is the total exp needed to reach level.
+func (p *Progression) RequiredExp(level uint) (uint, bool) {
+	l, ok := p.byLevel[level]
+	return l.Exp, ok
+}
+
+// FreeStatsBetween sums the free points granted for every level above
+// oldLevel up to newLevel.
+func (p *Progression) FreeStatsBetween(oldLevel, newLevel uint) uint {
+	return p.RewardsBetween(oldLevel, newLevel).FreeStats
+}
+
+// RewardsBetween sums the free points and stats granted for every level above
+// oldLevel up to newLevel. Level and Exp of the result are left empty.
+func (p *Progression) RewardsBetween(oldLevel, newLevel uint) LevelReward {
+	var total LevelReward
+	for lvl := oldLevel + 1; lvl <= newLevel; lvl++ {
+		reward := p.byLevel[lvl]
+		total.FreeStats += reward.FreeStats
+		total.Hp += reward.Hp
+		total.Attack += reward.Attack
+		total.Defense += reward.Defense
+	}
+	return total
+}
+
+func (p *Progression) BotsToLevelFrom(level uint) uint {
+	return uint(p.BotsToLevel.Base * math.Pow(p.BotsToLevel.Growth, float64(level-1)))
+}
+
+func (p *Progression) ExpModifier(playerLvl, botLvl uint) float64 {
+	diff := int(botLvl) - int(playerLvl)
+
+	switch {
+	case diff == 0:
+		return 1.0
+	case diff > 0:
+		return 1.0 + float64(diff)*p.ExpModifiers.HigherBotBonus
+	default:
+		return 1.0 / (1.0 + float64(-diff)*p.ExpModifiers.LowerBotPenalty)
+	}
+}
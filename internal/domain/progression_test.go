@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultProgression(t *testing.T) {
+	p, err := LoadProgression("")
+	require.NoError(t, err)
+
+	assert.Equal(t, uint(20), p.MaxLevel)
+
+	exp, ok := p.RequiredExp(2)
+	assert.True(t, ok)
+	assert.Equal(t, uint(100), exp)
+
+	_, ok = p.RequiredExp(21)
+	assert.False(t, ok)
+
+	assert.Equal(t, uint(6), p.FreeStatsBetween(1, 3))
+	assert.Equal(t, LevelReward{FreeStats: 6, Hp: 10, Attack: 2, Defense: 2}, p.RewardsBetween(1, 3))
+	assert.Equal(t, uint(5), p.BotsToLevelFrom(1))
+	assert.Equal(t, uint(8), p.BotsToLevelFrom(2))
+	assert.InDelta(t, 1.5, p.ExpModifier(1, 3), 1e-9)
+	assert.InDelta(t, 0.5, p.ExpModifier(3, 1), 1e-9)
+}
+
+func TestParseProgression(t *testing.T) {
+	valid := `{
+		"version": 2,
+		"maxLevel": 3,
+		"levels": [
+			{"level": 1, "exp": 0},
+			{"level": 2, "exp": 50, "freeStats": 2, "hp": 10},
+			{"level": 3, "exp": 150, "freeStats": 4, "attack": 2, "defense": 1}
+		],
+		"botsToLevel": {"base": 3, "growth": 1.2},
+		"expModifiers": {"higherBotBonus": 0.1, "lowerBotPenalty": 0.2}
+	}`
+
+	p, err := ParseProgression([]byte(valid))
+	require.NoError(t, err)
+	assert.Equal(t, 2, p.Version)
+	assert.Equal(t, uint(6), p.FreeStatsBetween(1, 3))
+	assert.Equal(t, LevelReward{FreeStats: 2, Hp: 10}, p.RewardsBetween(1, 2))
+	assert.Equal(t, LevelReward{FreeStats: 4, Attack: 2, Defense: 1}, p.RewardsBetween(2, 3))
+
+	invalid := map[string]string{
+		"malformed json":    `{"version": 1,`,
+		"missing version":   `{"maxLevel": 1, "levels": [{"level": 1, "exp": 0}], "botsToLevel": {"base": 1, "growth": 1}}`,
+		"level count":       `{"version": 1, "maxLevel": 2, "levels": [{"level": 1, "exp": 0}], "botsToLevel": {"base": 1, "growth": 1}}`,
+		"level gap":         `{"version": 1, "maxLevel": 2, "levels": [{"level": 1, "exp": 0}, {"level": 3, "exp": 10}], "botsToLevel": {"base": 1, "growth": 1}}`,
+		"exp not growing":   `{"version": 1, "maxLevel": 2, "levels": [{"level": 1, "exp": 0}, {"level": 2, "exp": 0}], "botsToLevel": {"base": 1, "growth": 1}}`,
+		"first level exp":   `{"version": 1, "maxLevel": 1, "levels": [{"level": 1, "exp": 5}], "botsToLevel": {"base": 1, "growth": 1}}`,
+		"bots to level":     `{"version": 1, "maxLevel": 1, "levels": [{"level": 1, "exp": 0}], "botsToLevel": {"base": 0, "growth": 1}}`,
+		"negative modifier": `{"version": 1, "maxLevel": 1, "levels": [{"level": 1, "exp": 0}], "botsToLevel": {"base": 1, "growth": 1}, "expModifiers": {"higherBotBonus": -1}}`,
+	}
+	for name, data := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseProgression([]byte(data))
+			assert.ErrorIs(t, err, ErrInvalidProgression)
+		})
+	}
+}
+
+func TestSetProgression(t *testing.T) {
+	original := CurrentProgression()
+	defer func() { require.NoError(t, SetProgression(original)) }()
+
+	assert.ErrorIs(t, SetProgression(&Progression{}), ErrInvalidProgression)
+	assert.Same(t, original, CurrentProgression())
+
+	raised := &Progression{
+		Version:     2,
+		MaxLevel:    2,
+		Levels:      []LevelReward{{Level: 1}, {Level: 2, Exp: 10, FreeStats: 1}},
+		BotsToLevel: BotsToLevel{Base: 1, Growth: 1},
+	}
+	require.NoError(t, SetProgression(raised))
+	assert.True(t, (&User{Level: 1, Exp: 10}).ReachedNewLevel())
+}
@@ -39,4 +39,18 @@ type Round struct {
 	PlayerDefensePoint *BodyPart   `db:"player_defense_point"`
 	BotAttackPoint     *BodyPart   `db:"bot_attack_point"`
 	BotDefensePoint    *BodyPart   `db:"bot_defense_point"`
+	TimedOut           bool        `db:"timed_out"`
+	BotAbility         *BotAbility `db:"bot_ability"`
+	// Critical marks a critical hit by the player; Dodged and ShieldBlocked
+	// say how the player got out of the bot's hit.
+	Critical      bool `db:"critical"`
+	Dodged        bool `db:"dodged"`
+	ShieldBlocked bool `db:"shield_blocked"`
+	// BotCritical, BotDodged and BotShieldBlocked are the same rolls seen
+	// from the bot's side.
+	BotCritical      bool `db:"bot_critical"`
+	BotDodged        bool `db:"bot_dodged"`
+	BotShieldBlocked bool `db:"bot_shield_blocked"`
+	// ConsumableItemID is the item the player used instead of striking.
+	ConsumableItemID *uuid.UUID `db:"consumable_item_id"`
 }
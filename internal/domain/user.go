@@ -16,6 +16,9 @@ type User struct {
 	Email                 string     `db:"email"`
 	Exp                   uint       `db:"exp"`
 	FreeStats             uint       `db:"free_stats"`
+	AllocatedAttack       uint       `db:"allocated_attack"`
+	AllocatedDefense      uint       `db:"allocated_defense"`
+	AllocatedHp           uint       `db:"allocated_hp"`
 	Gold                  uint       `db:"gold"`
 	Hp                    uint       `db:"hp"`
 	Level                 uint       `db:"level"`
@@ -38,34 +41,23 @@ type User struct {
 	Ring3EquipmentItemID  *uuid.UUID `db:"ring3_equipment_item_id"`
 	Ring4EquipmentItemID  *uuid.UUID `db:"ring4_equipment_item_id"`
 	Avatar                string     `db:"avatar"`
+	WeakenedUntil         *time.Time `db:"weakened_until"`
+	Armour                Armour     `db:"-"`
+	// CombatStats come from the equipped items, see UserRepository.FindCombatStats.
+	CombatStats CombatStats `db:"-"`
+	// Buffs are the consumable buffs running on the user, see
+	// UserRepository.FindActiveBuffs.
+	Buffs []*UserBuff `db:"-"`
 }
 
-var LevelMatrix = map[uint]uint{
-	1:  0,
-	2:  100,
-	3:  200,
-	4:  400,
-	5:  800,
-	6:  1500,
-	7:  3000,
-	8:  5000,
-	9:  10000,
-	10: 15000,
-	11: 20000,
-	12: 25000,
-	13: 30000,
-	14: 35000,
-	15: 40000,
-	16: 45000,
-	17: 50000,
-	18: 55000,
-	19: 60000,
-	20: 65000,
-}
+const (
+	HpPerStatPoint          uint = 5
+	StatsResetPricePerLevel uint = 50
+)
 
 func (user *User) ReachedNewLevel() bool {
 	nextLevel := user.Level + 1
-	requiredExp, exists := LevelMatrix[nextLevel]
+	requiredExp, exists := CurrentProgression().RequiredExp(nextLevel)
 	if !exists {
 		return false
 	}
@@ -92,3 +84,34 @@ func (user *User) RegenerateHealth(percent float64) uint {
 
 	return newHp
 }
+
+// MissingHp is how far the user is from full health.
+func (user *User) MissingHp() uint {
+	if user.CurrentHp >= user.Hp {
+		return 0
+	}
+	return user.Hp - user.CurrentHp
+}
+
+// IsWeakened reports whether the debuff from the last defeat is still on.
+func (user *User) IsWeakened(now time.Time) bool {
+	return user.WeakenedUntil != nil && now.Before(*user.WeakenedUntil)
+}
+
+// BlockDefense is the defense that counts when the player blocks the attacked
+// part. Armour only protects the part it covers, so it is left out.
+func (user *User) BlockDefense() uint {
+	armour := user.Armour.Total()
+	if armour >= user.Defense {
+		return 0
+	}
+	return user.Defense - armour
+}
+
+func (user *User) AllocatedStats() uint {
+	return user.AllocatedAttack + user.AllocatedDefense + user.AllocatedHp
+}
+
+func (user *User) StatsResetPrice() uint {
+	return user.Level * StatsResetPricePerLevel
+}
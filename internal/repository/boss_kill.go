@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BossKillRepository struct {
+	db ExtHandle
+}
+
+func NewBossKillRepository(db ExtHandle) *BossKillRepository {
+	return &BossKillRepository{db: db}
+}
+
+// Record remembers that the user has just killed the boss; only the latest
+// kill matters for the cooldown.
+func (r *BossKillRepository) Record(userID, botID uuid.UUID) error {
+	query := `
+		INSERT INTO boss_kills (user_id, bot_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, bot_id) DO UPDATE SET killed_at = CURRENT_TIMESTAMP
+	`
+	_, err := r.db.Exec(query, userID, botID)
+	return err
+}
+
+// CooldownUntil returns when the user may attack the boss again, or nil if
+// they already can.
+func (r *BossKillRepository) CooldownUntil(userID, botID uuid.UUID) (*time.Time, error) {
+	query := `
+		SELECT bk.killed_at + b.kill_cooldown_seconds * INTERVAL '1 second'
+		FROM boss_kills bk
+		INNER JOIN bots b ON b.id = bk.bot_id
+		WHERE bk.user_id = $1 AND bk.bot_id = $2
+		  AND bk.killed_at + b.kill_cooldown_seconds * INTERVAL '1 second' > CURRENT_TIMESTAMP
+	`
+
+	var until time.Time
+	err := r.db.Get(&until, query, userID, botID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &until, nil
+}
@@ -24,13 +24,20 @@ func NewBotRepository(db *sqlx.DB) *BotRepository {
 
 func (r *BotRepository) Create(bot *domain.Bot) error {
 	query := `
-		INSERT INTO bots (name, slug, attack, defense, hp, level, avatar)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO bots (name, slug, attack, defense, hp, level, avatar, strategy, is_boss, kill_cooldown_seconds,
+			crit_chance, crit_power, dodge_chance, block_chance)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id, created_at
 	`
 
+	if bot.Strategy == "" {
+		bot.Strategy = domain.BotStrategyRandom
+	}
+
 	err := r.db.QueryRow(query,
-		bot.Name, bot.Slug, bot.Attack, bot.Defense, bot.Hp, bot.Level, bot.Avatar,
+		bot.Name, bot.Slug, bot.Attack, bot.Defense, bot.Hp, bot.Level, bot.Avatar, bot.Strategy,
+		bot.IsBoss, bot.KillCooldownSeconds,
+		bot.CritChance, bot.CritPower, bot.DodgeChance, bot.BlockChance,
 	).Scan(&bot.ID, &bot.CreatedAt)
 	if err != nil {
 		return err
@@ -40,7 +47,8 @@ func (r *BotRepository) Create(bot *domain.Bot) error {
 
 func (r *BotRepository) FindBotsByLocationID(locationID uuid.UUID) ([]*domain.Bot, error) {
 	query := `
-		SELECT b.id, b.created_at, b.deleted_at, b.name, b.slug, b.attack, b.defense, b.hp, b.level, b.avatar
+		SELECT b.id, b.created_at, b.deleted_at, b.name, b.slug, b.attack, b.defense, b.hp, b.level, b.avatar, b.strategy,
+			b.is_boss, b.kill_cooldown_seconds, b.crit_chance, b.crit_power, b.dodge_chance, b.block_chance
 		FROM bots b
 		INNER JOIN location_bots lb ON lb.bot_id = b.id
 		WHERE lb.location_id = $1 AND b.deleted_at IS NULL AND lb.deleted_at IS NULL
@@ -57,7 +65,8 @@ func (r *BotRepository) FindBotsByLocationID(locationID uuid.UUID) ([]*domain.Bo
 
 func (r *BotRepository) FindBySlug(slug string) (*domain.Bot, error) {
 	query := `
-		SELECT id, created_at, deleted_at, name, slug, attack, defense, hp, level, avatar
+		SELECT id, created_at, deleted_at, name, slug, attack, defense, hp, level, avatar, strategy,
+			is_boss, kill_cooldown_seconds, crit_chance, crit_power, dodge_chance, block_chance
 		FROM bots
 		WHERE slug = $1 AND deleted_at IS NULL
 	`
@@ -76,7 +85,8 @@ func (r *BotRepository) FindBySlug(slug string) (*domain.Bot, error) {
 
 func (r *BotRepository) FindByID(id uuid.UUID) (*domain.Bot, error) {
 	query := `
-		SELECT id, created_at, deleted_at, name, slug, attack, defense, hp, level, avatar
+		SELECT id, created_at, deleted_at, name, slug, attack, defense, hp, level, avatar, strategy,
+			is_boss, kill_cooldown_seconds, crit_chance, crit_power, dodge_chance, block_chance
 		FROM bots
 		WHERE id = $1 AND deleted_at IS NULL
 	`
@@ -92,3 +102,78 @@ func (r *BotRepository) FindByID(id uuid.UUID) (*domain.Bot, error) {
 
 	return bot, nil
 }
+
+func (r *BotRepository) AddLoot(loot *domain.BotLoot) error {
+	query := `
+		INSERT INTO bot_loot (bot_id, equipment_item_id, weight, min_level, max_level)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(query,
+		loot.BotID, loot.EquipmentItemID, loot.Weight, loot.MinLevel, loot.MaxLevel,
+	).Scan(&loot.ID, &loot.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *BotRepository) FindLootByBotID(botID uuid.UUID) ([]*domain.BotLoot, error) {
+	query := `
+		SELECT id, created_at, deleted_at, bot_id, equipment_item_id, weight, min_level, max_level
+		FROM bot_loot
+		WHERE bot_id = $1 AND deleted_at IS NULL
+	`
+
+	var loot []*domain.BotLoot
+	err := r.db.Select(&loot, query, botID)
+	if err != nil {
+		return nil, err
+	}
+
+	return loot, nil
+}
+
+func (r *BotRepository) RemoveLoot(id uuid.UUID) error {
+	query := `UPDATE bot_loot SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+func (r *BotRepository) AddPhase(phase *domain.BotPhase) error {
+	query := `
+		INSERT INTO bot_phases (bot_id, hp_percent, attack, defense, strategy, ability, ability_chance, ability_power)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(query,
+		phase.BotID, phase.HpPercent, phase.Attack, phase.Defense, phase.Strategy, phase.Ability,
+		phase.AbilityChance, phase.AbilityPower,
+	).Scan(&phase.ID, &phase.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindPhasesByBotID returns the boss phases from the first (highest HP
+// threshold) to the last.
+func (r *BotRepository) FindPhasesByBotID(botID uuid.UUID) ([]*domain.BotPhase, error) {
+	query := `
+		SELECT id, created_at, deleted_at, bot_id, hp_percent, attack, defense, strategy, ability,
+			ability_chance, ability_power
+		FROM bot_phases
+		WHERE bot_id = $1 AND deleted_at IS NULL
+		ORDER BY hp_percent DESC
+	`
+
+	var phases []*domain.BotPhase
+	err := r.db.Select(&phases, query, botID)
+	if err != nil {
+		return nil, err
+	}
+
+	return phases, nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+)
+
+var (
+	ErrBotInstanceNotFound = errors.New("bot instance not found")
+)
+
+type BotInstanceRepository struct {
+	db ExtHandle
+}
+
+func NewBotInstanceRepository(db ExtHandle) *BotInstanceRepository {
+	return &BotInstanceRepository{db: db}
+}
+
+// Spawn brings the location up to date: dead instances whose timer ran out
+// come back with full HP, and every location_bots link gets its missing
+// slots filled. Slots are unique, so concurrent calls cannot overpopulate.
+func (r *BotInstanceRepository) Spawn(locationID uuid.UUID) error {
+	respawnQuery := `
+		UPDATE bot_instances bi
+		SET state = $1, current_hp = b.hp, respawn_at = NULL, fight_id = NULL, group_fight_id = NULL
+		FROM bots b
+		WHERE b.id = bi.bot_id AND bi.location_id = $2 AND bi.state = $3
+		  AND bi.respawn_at <= CURRENT_TIMESTAMP AND bi.deleted_at IS NULL
+	`
+	if _, err := r.db.Exec(respawnQuery, domain.BotInstanceStateAlive, locationID, domain.BotInstanceStateDead); err != nil {
+		return err
+	}
+
+	populateQuery := `
+		INSERT INTO bot_instances (location_bot_id, location_id, bot_id, slot, current_hp)
+		SELECT lb.id, lb.location_id, lb.bot_id, slot, b.hp
+		FROM location_bots lb
+		INNER JOIN bots b ON b.id = lb.bot_id
+		CROSS JOIN LATERAL generate_series(1, lb.max_population) AS slot
+		WHERE lb.location_id = $1 AND lb.deleted_at IS NULL AND b.deleted_at IS NULL
+		ON CONFLICT (location_bot_id, slot) DO NOTHING
+	`
+	_, err := r.db.Exec(populateQuery, locationID)
+	return err
+}
+
+// FindByLocationID lists the location's instances, skipping slots above a
+// lowered max_population.
+func (r *BotInstanceRepository) FindByLocationID(locationID uuid.UUID) ([]*domain.BotInstance, error) {
+	query := `
+		SELECT bi.id, bi.created_at, bi.deleted_at, bi.location_bot_id, bi.location_id, bi.bot_id,
+			bi.slot, bi.state, bi.current_hp, bi.fight_id, bi.group_fight_id, bi.respawn_at
+		FROM bot_instances bi
+		INNER JOIN location_bots lb ON lb.id = bi.location_bot_id
+		WHERE bi.location_id = $1 AND bi.slot <= lb.max_population
+		  AND bi.deleted_at IS NULL AND lb.deleted_at IS NULL
+		ORDER BY bi.created_at, bi.slot
+	`
+
+	instances := []*domain.BotInstance{}
+	err := r.db.Select(&instances, query, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// FindAvailableForUpdate locks an idle instance of the bot. Instances locked
+// by a concurrent attack are skipped, so two players never share one.
+func (r *BotInstanceRepository) FindAvailableForUpdate(locationID, botID uuid.UUID) (*domain.BotInstance, error) {
+	query := `
+		SELECT bi.id, bi.created_at, bi.deleted_at, bi.location_bot_id, bi.location_id, bi.bot_id,
+			bi.slot, bi.state, bi.current_hp, bi.fight_id, bi.group_fight_id, bi.respawn_at
+		FROM bot_instances bi
+		INNER JOIN location_bots lb ON lb.id = bi.location_bot_id
+		WHERE bi.location_id = $1 AND bi.bot_id = $2 AND bi.state = $3 AND bi.slot <= lb.max_population
+		  AND bi.deleted_at IS NULL AND lb.deleted_at IS NULL
+		ORDER BY bi.slot
+		LIMIT 1
+		FOR UPDATE OF bi SKIP LOCKED
+	`
+
+	instance := &domain.BotInstance{}
+	err := r.db.Get(instance, query, locationID, botID, domain.BotInstanceStateAlive)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrBotInstanceNotFound
+		}
+		return nil, err
+	}
+
+	return instance, nil
+}
+
+func (r *BotInstanceRepository) Engage(id, fightID uuid.UUID) error {
+	query := `UPDATE bot_instances SET state = $1, fight_id = $2 WHERE id = $3`
+	_, err := r.db.Exec(query, domain.BotInstanceStateInFight, fightID, id)
+	return err
+}
+
+func (r *BotInstanceRepository) EngageGroup(id, groupFightID uuid.UUID) error {
+	query := `UPDATE bot_instances SET state = $1, group_fight_id = $2 WHERE id = $3`
+	_, err := r.db.Exec(query, domain.BotInstanceStateInFight, groupFightID, id)
+	return err
+}
+
+// ReleaseByFightID frees the instance from a fight it survived; it keeps the
+// HP it had left. fightID may be a fight or a group fight.
+func (r *BotInstanceRepository) ReleaseByFightID(fightID uuid.UUID, currentHp uint) error {
+	query := `
+		UPDATE bot_instances
+		SET state = $1, fight_id = NULL, group_fight_id = NULL, current_hp = $2
+		WHERE fight_id = $3 OR group_fight_id = $3
+	`
+	_, err := r.db.Exec(query, domain.BotInstanceStateAlive, currentHp, fightID)
+	return err
+}
+
+// KillByFightID marks the instance dead until its link's respawn delay has
+// passed. fightID may be a fight or a group fight.
+func (r *BotInstanceRepository) KillByFightID(fightID uuid.UUID) error {
+	query := `
+		UPDATE bot_instances bi
+		SET state = $1,
+		    fight_id = NULL,
+		    group_fight_id = NULL,
+		    current_hp = 0,
+		    respawn_at = CURRENT_TIMESTAMP + lb.respawn_seconds * INTERVAL '1 second'
+		FROM location_bots lb
+		WHERE lb.id = bi.location_bot_id AND (bi.fight_id = $2 OR bi.group_fight_id = $2)
+	`
+	_, err := r.db.Exec(query, domain.BotInstanceStateDead, fightID)
+	return err
+}
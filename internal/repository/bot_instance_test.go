@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+)
+
+func setupBotInstanceTestData(t *testing.T, maxPopulation, respawnSeconds int) (*domain.Location, *domain.Bot) {
+	ts := time.Now().UnixNano()
+
+	location := &domain.Location{
+		Name: fmt.Sprintf("Instance Location %d", ts),
+		Slug: fmt.Sprintf("instance-location-%d", ts),
+		Cell: true,
+	}
+	require.NoError(t, NewLocationRepository(testDB.DB()).Create(location))
+
+	bot := &domain.Bot{
+		Name:    fmt.Sprintf("Instance Bot %d", ts),
+		Slug:    fmt.Sprintf("instance-bot-%d", ts),
+		Attack:  5,
+		Defense: 3,
+		Hp:      20,
+		Level:   1,
+		Avatar:  "images/bots/instance",
+	}
+	require.NoError(t, NewBotRepository(testDB.DB()).Create(bot))
+
+	linkQuery := `INSERT INTO location_bots (id, location_id, bot_id, max_population, respawn_seconds) VALUES ($1, $2, $3, $4, $5)`
+	_, err := testDB.DB().Exec(linkQuery, uuid.New(), location.ID, bot.ID, maxPopulation, respawnSeconds)
+	require.NoError(t, err)
+
+	return location, bot
+}
+
+func createBotInstanceTestFight(t *testing.T, location *domain.Location, bot *domain.Bot) uuid.UUID {
+	ts := time.Now().UnixNano()
+
+	user := &domain.User{
+		Username:   fmt.Sprintf("instanceuser%d", ts),
+		Email:      fmt.Sprintf("instance%d@example.com", ts),
+		Password:   "hashedpassword",
+		LocationID: location.ID,
+	}
+	require.NoError(t, NewUserRepository(testDB.DB()).Create(user))
+
+	fightID, err := NewFightRepository(testDB.DB()).Create(&domain.Fight{UserID: user.ID, BotID: bot.ID})
+	require.NoError(t, err)
+
+	return fightID
+}
+
+func TestBotInstanceRepository_Spawn(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Test database not initialized")
+	}
+
+	repo := NewBotInstanceRepository(testDB.DB())
+	location, bot := setupBotInstanceTestData(t, 3, 60)
+
+	require.NoError(t, repo.Spawn(location.ID))
+	require.NoError(t, repo.Spawn(location.ID))
+
+	instances, err := repo.FindByLocationID(location.ID)
+	require.NoError(t, err)
+	require.Len(t, instances, 3)
+	for i, instance := range instances {
+		assert.Equal(t, bot.ID, instance.BotID)
+		assert.Equal(t, i+1, instance.Slot)
+		assert.Equal(t, domain.BotInstanceStateAlive, instance.State)
+		assert.Equal(t, bot.Hp, instance.CurrentHp)
+	}
+}
+
+func TestBotInstanceRepository_FindAvailableForUpdate(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Test database not initialized")
+	}
+
+	repo := NewBotInstanceRepository(testDB.DB())
+	location, bot := setupBotInstanceTestData(t, 1, 60)
+	require.NoError(t, repo.Spawn(location.ID))
+
+	instance, err := repo.FindAvailableForUpdate(location.ID, bot.ID)
+	require.NoError(t, err)
+
+	fightID := createBotInstanceTestFight(t, location, bot)
+	require.NoError(t, repo.Engage(instance.ID, fightID))
+
+	_, err = repo.FindAvailableForUpdate(location.ID, bot.ID)
+	assert.ErrorIs(t, err, ErrBotInstanceNotFound)
+}
+
+func TestBotInstanceRepository_ReleaseByFightID(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Test database not initialized")
+	}
+
+	repo := NewBotInstanceRepository(testDB.DB())
+	location, bot := setupBotInstanceTestData(t, 1, 60)
+	require.NoError(t, repo.Spawn(location.ID))
+
+	instance, err := repo.FindAvailableForUpdate(location.ID, bot.ID)
+	require.NoError(t, err)
+
+	fightID := createBotInstanceTestFight(t, location, bot)
+	require.NoError(t, repo.Engage(instance.ID, fightID))
+	require.NoError(t, repo.ReleaseByFightID(fightID, 7))
+
+	released, err := repo.FindAvailableForUpdate(location.ID, bot.ID)
+	require.NoError(t, err)
+	assert.Equal(t, instance.ID, released.ID)
+	assert.Equal(t, uint(7), released.CurrentHp)
+	assert.Nil(t, released.FightID)
+}
+
+func TestBotInstanceRepository_KillByFightID(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Test database not initialized")
+	}
+
+	repo := NewBotInstanceRepository(testDB.DB())
+	location, bot := setupBotInstanceTestData(t, 1, 0)
+	require.NoError(t, repo.Spawn(location.ID))
+
+	instance, err := repo.FindAvailableForUpdate(location.ID, bot.ID)
+	require.NoError(t, err)
+
+	fightID := createBotInstanceTestFight(t, location, bot)
+	require.NoError(t, repo.Engage(instance.ID, fightID))
+	require.NoError(t, repo.KillByFightID(fightID))
+
+	instances, err := repo.FindByLocationID(location.ID)
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, domain.BotInstanceStateDead, instances[0].State)
+	assert.NotNil(t, instances[0].RespawnAt)
+
+	t.Run("respawns once the timer runs out", func(t *testing.T) {
+		require.NoError(t, repo.Spawn(location.ID))
+
+		respawned, err := repo.FindAvailableForUpdate(location.ID, bot.ID)
+		require.NoError(t, err)
+		assert.Equal(t, instance.ID, respawned.ID)
+		assert.Equal(t, bot.Hp, respawned.CurrentHp)
+	})
+}
@@ -156,3 +156,56 @@ func TestBotRepository_FindBotsByLocationID_Empty(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, bots)
 }
+
+func TestBotRepository_Loot(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Test database not initialized")
+	}
+
+	repo := NewBotRepository(testDB.DB())
+	itemRepo := NewEquipmentItemRepository(testDB.DB())
+	ts := time.Now().UnixNano()
+
+	bot := &domain.Bot{
+		Name:    fmt.Sprintf("Loot Bot %d", ts),
+		Slug:    fmt.Sprintf("loot-bot-%d", ts),
+		Attack:  5,
+		Defense: 3,
+		Hp:      20,
+		Level:   1,
+		Avatar:  "images/bots/loot",
+	}
+	require.NoError(t, repo.Create(bot))
+
+	item := &domain.EquipmentItem{
+		Name:          fmt.Sprintf("Loot Item %d", ts),
+		Slug:          fmt.Sprintf("loot-item-%d", ts),
+		RequiredLevel: 1,
+	}
+	require.NoError(t, itemRepo.Create(item))
+
+	maxLevel := uint(5)
+	loot := &domain.BotLoot{
+		BotID:           bot.ID,
+		EquipmentItemID: item.ID,
+		Weight:          10,
+		MinLevel:        1,
+		MaxLevel:        &maxLevel,
+	}
+	require.NoError(t, repo.AddLoot(loot))
+	assert.NotEqual(t, uuid.Nil, loot.ID)
+
+	found, err := repo.FindLootByBotID(bot.ID)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, item.ID, found[0].EquipmentItemID)
+	assert.Equal(t, uint(10), found[0].Weight)
+	require.NotNil(t, found[0].MaxLevel)
+	assert.Equal(t, maxLevel, *found[0].MaxLevel)
+
+	require.NoError(t, repo.RemoveLoot(loot.ID))
+
+	found, err = repo.FindLootByBotID(bot.ID)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
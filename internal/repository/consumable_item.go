@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+)
+
+var (
+	ErrConsumableItemNotFound = errors.New("consumable item not found")
+)
+
+const consumableItemColumns = `
+	ci.id, ci.created_at, ci.deleted_at, ci.name, ci.slug, ci.image, ci.price, ci.required_level,
+	ci.heal_hp, ci.buff_attack, ci.buff_defense, ci.buff_crit_chance, ci.buff_dodge_chance, ci.buff_seconds
+`
+
+type ConsumableItemRepository struct {
+	db ExtHandle
+}
+
+func NewConsumableItemRepository(db ExtHandle) *ConsumableItemRepository {
+	return &ConsumableItemRepository{db: db}
+}
+
+func (r *ConsumableItemRepository) Create(item *domain.ConsumableItem) error {
+	query := `
+		INSERT INTO consumable_items (
+			name, slug, image, price, required_level,
+			heal_hp, buff_attack, buff_defense, buff_crit_chance, buff_dodge_chance, buff_seconds
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(query,
+		item.Name, item.Slug, item.Image, item.Price, item.RequiredLevel,
+		item.HealHp, item.BuffAttack, item.BuffDefense, item.BuffCritChance, item.BuffDodgeChance, item.BuffSeconds,
+	).Scan(&item.ID, &item.CreatedAt)
+}
+
+func (r *ConsumableItemRepository) FindAll() ([]*domain.ConsumableItem, error) {
+	query := `
+		SELECT ` + consumableItemColumns + `
+		FROM consumable_items ci
+		WHERE ci.deleted_at IS NULL
+		ORDER BY ci.required_level ASC, ci.price ASC
+	`
+
+	items := []*domain.ConsumableItem{}
+	if err := r.db.Select(&items, query); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *ConsumableItemRepository) FindByID(id uuid.UUID) (*domain.ConsumableItem, error) {
+	query := `
+		SELECT ` + consumableItemColumns + `
+		FROM consumable_items ci
+		WHERE ci.id = $1 AND ci.deleted_at IS NULL
+	`
+
+	return r.find(query, id)
+}
+
+func (r *ConsumableItemRepository) FindBySlug(slug string) (*domain.ConsumableItem, error) {
+	query := `
+		SELECT ` + consumableItemColumns + `
+		FROM consumable_items ci
+		WHERE ci.slug = $1 AND ci.deleted_at IS NULL
+	`
+
+	return r.find(query, slug)
+}
+
+func (r *ConsumableItemRepository) find(query string, arg interface{}) (*domain.ConsumableItem, error) {
+	item := &domain.ConsumableItem{}
+	err := r.db.Get(item, query, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrConsumableItemNotFound
+		}
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (r *ConsumableItemRepository) AddDrop(drop *domain.BotConsumableDrop) error {
+	query := `
+		INSERT INTO bot_consumable_drops (bot_id, consumable_item_id, chance)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(query, drop.BotID, drop.ConsumableItemID, drop.Chance).Scan(&drop.ID, &drop.CreatedAt)
+}
+
+func (r *ConsumableItemRepository) FindDropsByBotID(botID uuid.UUID) ([]*domain.BotConsumableDrop, error) {
+	query := `
+		SELECT id, created_at, deleted_at, bot_id, consumable_item_id, chance
+		FROM bot_consumable_drops
+		WHERE bot_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at
+	`
+
+	var drops []*domain.BotConsumableDrop
+	if err := r.db.Select(&drops, query, botID); err != nil {
+		return nil, err
+	}
+
+	return drops, nil
+}
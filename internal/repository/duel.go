@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+)
+
+var (
+	ErrDuelNotFound = errors.New("duel not found")
+)
+
+type DuelRepository struct {
+	db ExtHandle
+}
+
+func NewDuelRepository(db ExtHandle) *DuelRepository {
+	return &DuelRepository{db: db}
+}
+
+func (r *DuelRepository) Create(duel *domain.Duel) error {
+	query := `
+		INSERT INTO duels (challenger_id, opponent_id, location_id)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, status
+	`
+
+	return r.db.QueryRow(query,
+		duel.ChallengerID, duel.OpponentID, duel.LocationID,
+	).Scan(&duel.ID, &duel.CreatedAt, &duel.Status)
+}
+
+func (r *DuelRepository) FindByID(id uuid.UUID) (*domain.Duel, error) {
+	query := `
+		SELECT id, created_at, deleted_at, challenger_id, opponent_id, location_id, status, winner_id,
+			challenger_respawn_location_id, challenger_weakened_until, opponent_respawn_location_id, opponent_weakened_until
+		FROM duels
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	duel := &domain.Duel{}
+	err := r.db.Get(duel, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDuelNotFound
+		}
+		return nil, err
+	}
+
+	return duel, nil
+}
+
+func (r *DuelRepository) FindByIDForUpdate(id uuid.UUID) (*domain.Duel, error) {
+	query := `
+		SELECT id, created_at, deleted_at, challenger_id, opponent_id, location_id, status, winner_id,
+			challenger_respawn_location_id, challenger_weakened_until, opponent_respawn_location_id, opponent_weakened_until
+		FROM duels
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	duel := &domain.Duel{}
+	err := r.db.Get(duel, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDuelNotFound
+		}
+		return nil, err
+	}
+
+	return duel, nil
+}
+
+// FindActiveByUserID returns the pending or in-progress duel the user takes part in.
+func (r *DuelRepository) FindActiveByUserID(userID uuid.UUID) (*domain.Duel, error) {
+	query := `
+		SELECT id, created_at, deleted_at, challenger_id, opponent_id, location_id, status, winner_id,
+			challenger_respawn_location_id, challenger_weakened_until, opponent_respawn_location_id, opponent_weakened_until
+		FROM duels
+		WHERE (challenger_id = $1 OR opponent_id = $1)
+		  AND status IN ($2, $3)
+		  AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	duel := &domain.Duel{}
+	err := r.db.Get(duel, query, userID, domain.DuelStatusPending, domain.DuelStatusInProgress)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDuelNotFound
+		}
+		return nil, err
+	}
+
+	return duel, nil
+}
+
+func (r *DuelRepository) UpdateStatus(id uuid.UUID, status domain.DuelStatus) error {
+	query := `UPDATE duels SET status = $1 WHERE id = $2`
+	_, err := r.db.Exec(query, status, id)
+	return err
+}
+
+func (r *DuelRepository) Finish(id uuid.UUID, winnerID *uuid.UUID) error {
+	query := `UPDATE duels SET status = $1, winner_id = $2 WHERE id = $3`
+	_, err := r.db.Exec(query, domain.DuelStatusFinished, winnerID, id)
+	return err
+}
+
+// RecordDefeats stores where the beaten participants were carried to.
+func (r *DuelRepository) RecordDefeats(duel *domain.Duel) error {
+	query := `
+		UPDATE duels
+		SET challenger_respawn_location_id = $1,
+		    challenger_weakened_until = $2,
+		    opponent_respawn_location_id = $3,
+		    opponent_weakened_until = $4
+		WHERE id = $5
+	`
+
+	_, err := r.db.Exec(query,
+		duel.ChallengerRespawnLocationID, duel.ChallengerWeakenedUntil,
+		duel.OpponentRespawnLocationID, duel.OpponentWeakenedUntil, duel.ID,
+	)
+	return err
+}
+
+// DeclineStalePending declines challenges that were not answered within timeout.
+func (r *DuelRepository) DeclineStalePending(timeout time.Duration) ([]*domain.Duel, error) {
+	query := `
+		UPDATE duels
+		SET status = $1
+		WHERE status = $2
+		  AND created_at < CURRENT_TIMESTAMP - $3 * INTERVAL '1 second'
+		  AND deleted_at IS NULL
+		RETURNING id, created_at, deleted_at, challenger_id, opponent_id, location_id, status, winner_id,
+			challenger_respawn_location_id, challenger_weakened_until, opponent_respawn_location_id, opponent_weakened_until
+	`
+
+	var duels []*domain.Duel
+	err := r.db.Select(&duels, query, domain.DuelStatusDeclined, domain.DuelStatusPending, timeout.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	return duels, nil
+}
+
+type DuelRoundRepository struct {
+	db ExtHandle
+}
+
+func NewDuelRoundRepository(db ExtHandle) *DuelRoundRepository {
+	return &DuelRoundRepository{db: db}
+}
+
+// Create opens a new round that has to be played within turnTimeout.
+func (r *DuelRoundRepository) Create(duelID uuid.UUID, challengerHp, opponentHp uint, turnTimeout time.Duration) error {
+	query := `
+		INSERT INTO duel_rounds (duel_id, challenger_hp, opponent_hp, deadline_at, status)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP + $4 * INTERVAL '1 second', $5)
+	`
+
+	_, err := r.db.Exec(query, duelID, challengerHp, opponentHp, turnTimeout.Seconds(), domain.RoundStatusInProgress)
+	return err
+}
+
+func (r *DuelRoundRepository) FindByDuelID(duelID uuid.UUID) ([]*domain.DuelRound, error) {
+	query := `
+		SELECT id, created_at, deleted_at, duel_id, status, deadline_at, challenger_hp, opponent_hp,
+			challenger_damage, opponent_damage, challenger_attack_point, challenger_defense_point,
+			opponent_attack_point, opponent_defense_point, challenger_critical, challenger_dodged,
+			challenger_shield_blocked, opponent_critical, opponent_dodged, opponent_shield_blocked
+		FROM duel_rounds
+		WHERE duel_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	var rounds []*domain.DuelRound
+	err := r.db.Select(&rounds, query, duelID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rounds, nil
+}
+
+func (r *DuelRoundRepository) FindCurrentForUpdate(duelID uuid.UUID) (*domain.DuelRound, error) {
+	query := `
+		SELECT id, created_at, deleted_at, duel_id, status, deadline_at, challenger_hp, opponent_hp,
+			challenger_damage, opponent_damage, challenger_attack_point, challenger_defense_point,
+			opponent_attack_point, opponent_defense_point, challenger_critical, challenger_dodged,
+			challenger_shield_blocked, opponent_critical, opponent_dodged, opponent_shield_blocked
+		FROM duel_rounds
+		WHERE duel_id = $1 AND status = $2 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1
+		FOR UPDATE
+	`
+
+	round := &domain.DuelRound{}
+	err := r.db.Get(round, query, duelID, domain.RoundStatusInProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	return round, nil
+}
+
+// FindExpiredForUpdate locks the current round only if its turn deadline has passed.
+func (r *DuelRoundRepository) FindExpiredForUpdate(duelID uuid.UUID) (*domain.DuelRound, error) {
+	query := `
+		SELECT id, created_at, deleted_at, duel_id, status, deadline_at, challenger_hp, opponent_hp,
+			challenger_damage, opponent_damage, challenger_attack_point, challenger_defense_point,
+			opponent_attack_point, opponent_defense_point, challenger_critical, challenger_dodged,
+			challenger_shield_blocked, opponent_critical, opponent_dodged, opponent_shield_blocked
+		FROM duel_rounds
+		WHERE duel_id = $1 AND status = $2 AND deadline_at < CURRENT_TIMESTAMP AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1
+		FOR UPDATE
+	`
+
+	round := &domain.DuelRound{}
+	err := r.db.Get(round, query, duelID, domain.RoundStatusInProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	return round, nil
+}
+
+// FindExpiredDuelIDs returns duels whose current round is past its turn deadline.
+func (r *DuelRoundRepository) FindExpiredDuelIDs() ([]uuid.UUID, error) {
+	query := `
+		SELECT DISTINCT duel_id
+		FROM duel_rounds
+		WHERE status = $1 AND deadline_at < CURRENT_TIMESTAMP AND deleted_at IS NULL
+	`
+
+	var ids []uuid.UUID
+	err := r.db.Select(&ids, query, domain.RoundStatusInProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *DuelRoundRepository) SetChallengerChoice(id uuid.UUID, attackPoint, defensePoint domain.BodyPart) error {
+	query := `UPDATE duel_rounds SET challenger_attack_point = $1, challenger_defense_point = $2 WHERE id = $3`
+	_, err := r.db.Exec(query, attackPoint, defensePoint, id)
+	return err
+}
+
+func (r *DuelRoundRepository) SetOpponentChoice(id uuid.UUID, attackPoint, defensePoint domain.BodyPart) error {
+	query := `UPDATE duel_rounds SET opponent_attack_point = $1, opponent_defense_point = $2 WHERE id = $3`
+	_, err := r.db.Exec(query, attackPoint, defensePoint, id)
+	return err
+}
+
+func (r *DuelRoundRepository) FinishRound(round *domain.DuelRound) error {
+	query := `
+		UPDATE duel_rounds
+		SET challenger_attack_point = $1,
+		    challenger_defense_point = $2,
+		    opponent_attack_point = $3,
+		    opponent_defense_point = $4,
+		    challenger_damage = $5,
+		    opponent_damage = $6,
+		    challenger_hp = $7,
+		    opponent_hp = $8,
+		    challenger_critical = $9,
+		    challenger_dodged = $10,
+		    challenger_shield_blocked = $11,
+		    opponent_critical = $12,
+		    opponent_dodged = $13,
+		    opponent_shield_blocked = $14,
+		    status = $15
+		WHERE id = $16
+	`
+
+	_, err := r.db.Exec(query,
+		round.ChallengerAttackPoint, round.ChallengerDefensePoint,
+		round.OpponentAttackPoint, round.OpponentDefensePoint,
+		round.ChallengerDamage, round.OpponentDamage,
+		round.ChallengerHp, round.OpponentHp,
+		round.ChallengerCritical, round.ChallengerDodged, round.ChallengerShieldBlocked,
+		round.OpponentCritical, round.OpponentDodged, round.OpponentShieldBlocked,
+		domain.RoundStatusFinished, round.ID,
+	)
+	return err
+}
@@ -31,7 +31,8 @@ func (r *EquipmentItemRepository) FindByCategorySlug(slug string) ([]*domain.Equ
 func (r *EquipmentItemRepository) FindByCategorySlugAndArtifact(slug string, artifact bool) ([]*domain.EquipmentItem, error) {
 	query := `
 		SELECT ei.id, ei.created_at, ei.deleted_at, ei.name, ei.slug, ei.attack, ei.defense, ei.hp,
-			ei.required_level, ei.price, ei.artifact, ei.equipment_category_id, ei.image
+			ei.required_level, ei.price, ei.artifact, ei.equipment_category_id, ei.image,
+			ei.crit_chance, ei.crit_power, ei.dodge_chance, ei.block_chance
 		FROM equipment_items ei
 		INNER JOIN equipment_categories ec ON ei.equipment_category_id = ec.id
 		WHERE ec.type = $1::equipment_category_type 
@@ -52,10 +53,12 @@ func (r *EquipmentItemRepository) FindByCategorySlugAndArtifact(slug string, art
 
 func (r *EquipmentItemRepository) FindByID(id uuid.UUID) (*domain.EquipmentItem, error) {
 	query := `
-		SELECT id, created_at, deleted_at, name, slug, attack, defense, hp,
-			required_level, price, artifact, equipment_category_id, image
-		FROM equipment_items
-		WHERE id = $1 AND deleted_at IS NULL
+		SELECT ei.id, ei.created_at, ei.deleted_at, ei.name, ei.slug, ei.attack, ei.defense, ei.hp,
+			ei.required_level, ei.price, ei.artifact, ei.equipment_category_id, ei.image, ec.type as equipment_type,
+			ei.crit_chance, ei.crit_power, ei.dodge_chance, ei.block_chance
+		FROM equipment_items ei
+		INNER JOIN equipment_categories ec ON ei.equipment_category_id = ec.id
+		WHERE ei.id = $1 AND ei.deleted_at IS NULL
 	`
 
 	item := &domain.EquipmentItem{}
@@ -73,7 +76,8 @@ func (r *EquipmentItemRepository) FindByID(id uuid.UUID) (*domain.EquipmentItem,
 func (r *EquipmentItemRepository) FindByIDs(ids []uuid.UUID) ([]*domain.EquipmentItem, error) {
 	query := `
 		SELECT ei.id, ei.created_at, ei.deleted_at, ei.name, ei.slug, ei.attack, ei.defense, ei.hp,
-			required_level, ei.price, ei.artifact, ei.equipment_category_id, ei.image, ec.type as equipment_type
+			required_level, ei.price, ei.artifact, ei.equipment_category_id, ei.image, ec.type as equipment_type,
+			ei.crit_chance, ei.crit_power, ei.dodge_chance, ei.block_chance
 		FROM equipment_items ei
 		INNER JOIN equipment_categories ec 
 		    ON ei.equipment_category_id = ec.id
@@ -92,10 +96,12 @@ func (r *EquipmentItemRepository) FindByIDs(ids []uuid.UUID) ([]*domain.Equipmen
 
 func (r *EquipmentItemRepository) FindBySlug(slug string) (*domain.EquipmentItem, error) {
 	query := `
-		SELECT id, created_at, deleted_at, name, slug, attack, defense, hp,
-			required_level, price, artifact, equipment_category_id, image
-		FROM equipment_items
-		WHERE slug = $1 AND deleted_at IS NULL
+		SELECT ei.id, ei.created_at, ei.deleted_at, ei.name, ei.slug, ei.attack, ei.defense, ei.hp,
+			ei.required_level, ei.price, ei.artifact, ei.equipment_category_id, ei.image, ec.type as equipment_type,
+			ei.crit_chance, ei.crit_power, ei.dodge_chance, ei.block_chance
+		FROM equipment_items ei
+		INNER JOIN equipment_categories ec ON ei.equipment_category_id = ec.id
+		WHERE ei.slug = $1 AND ei.deleted_at IS NULL
 	`
 
 	item := &domain.EquipmentItem{}
@@ -112,14 +118,16 @@ func (r *EquipmentItemRepository) FindBySlug(slug string) (*domain.EquipmentItem
 
 func (r *EquipmentItemRepository) Create(item *domain.EquipmentItem) error {
 	query := `
-		INSERT INTO equipment_items (name, slug, attack, defense, hp, required_level, price, artifact, equipment_category_id, image)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO equipment_items (name, slug, attack, defense, hp, required_level, price, artifact, equipment_category_id, image,
+			crit_chance, crit_power, dodge_chance, block_chance)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id
 	`
 
 	err := r.db.QueryRow(query,
 		item.Name, item.Slug, item.Attack, item.Defense, item.Hp,
 		item.RequiredLevel, item.Price, item.Artifact, item.EquipmentCategoryID, item.Image,
+		item.CritChance, item.CritPower, item.DodgeChance, item.BlockChance,
 	).Scan(&item.ID)
 	if err != nil {
 		return err
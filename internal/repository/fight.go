@@ -1,11 +1,38 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
+
 	"github.com/google/uuid"
 
 	"moonshine/internal/domain"
 )
 
+var (
+	ErrFightNotFound = errors.New("fight not found")
+)
+
+// FightFilter narrows the fight history. Zero values match everything.
+type FightFilter struct {
+	BotID   *uuid.UUID
+	Outcome domain.FightOutcome
+}
+
+// finishedFightsQuery selects a user's finished fights together with their
+// outcome: the player won if some round left the bot without HP, unless they
+// ran away first.
+const finishedFightsQuery = `
+	SELECT f.id, f.created_at, f.deleted_at, f.user_id, f.bot_id, f.status, f.dropped_gold, f.exp, f.dropped_item_id,
+		f.lost_gold, f.lost_exp, f.respawn_location_id, f.weakened_until, f.dropped_consumable_item_id,
+		CASE WHEN f.status = 'FLED' THEN 'FLED' WHEN EXISTS (
+			SELECT 1 FROM rounds r
+			WHERE r.fight_id = f.id AND r.status = 'FINISHED' AND r.bot_hp = 0 AND r.deleted_at IS NULL
+		) THEN 'WON' ELSE 'LOST' END AS outcome
+	FROM fights f
+	WHERE f.user_id = $1 AND f.status <> 'IN_PROGRESS' AND f.deleted_at IS NULL
+`
+
 type FightRepository struct {
 	db ExtHandle
 }
@@ -32,7 +59,8 @@ func (r *FightRepository) Create(fight *domain.Fight) (uuid.UUID, error) {
 
 func (r *FightRepository) FindActiveByUserID(userID uuid.UUID) (*domain.Fight, error) {
 	query := `
-		SELECT id, created_at, deleted_at, user_id, bot_id, status, dropped_gold, exp, dropped_item_id
+		SELECT id, created_at, deleted_at, user_id, bot_id, status, dropped_gold, exp, dropped_item_id,
+			lost_gold, lost_exp, respawn_location_id, weakened_until, dropped_consumable_item_id
 		FROM fights
 		WHERE user_id = $1 AND status = $2 AND deleted_at IS NULL
 		ORDER BY created_at DESC
@@ -48,18 +76,130 @@ func (r *FightRepository) FindActiveByUserID(userID uuid.UUID) (*domain.Fight, e
 	return fight, nil
 }
 
-func (r *FightRepository) Finish(id uuid.UUID, droppedGold, exp uint) (*domain.Fight, error) {
+func (r *FightRepository) FindByID(id uuid.UUID) (*domain.Fight, error) {
+	query := `
+		SELECT id, created_at, deleted_at, user_id, bot_id, status, dropped_gold, exp, dropped_item_id,
+			lost_gold, lost_exp, respawn_location_id, weakened_until, dropped_consumable_item_id
+		FROM fights
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	fight := &domain.Fight{}
+	err := r.db.Get(fight, query, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrFightNotFound
+		}
+		return nil, err
+	}
+
+	return fight, nil
+}
+
+func (r *FightRepository) Finish(id uuid.UUID, droppedGold, exp uint, droppedItemID *uuid.UUID) (*domain.Fight, error) {
 	query := `
 		UPDATE fights
 		SET status = $1,
 		    dropped_gold = $2,
-		    exp = $3
-		WHERE id = $4
-		RETURNING id, created_at, deleted_at, user_id, bot_id, status, dropped_gold, exp, dropped_item_id
+		    exp = $3,
+		    dropped_item_id = $4
+		WHERE id = $5
+		RETURNING id, created_at, deleted_at, user_id, bot_id, status, dropped_gold, exp, dropped_item_id,
+			lost_gold, lost_exp, respawn_location_id, weakened_until, dropped_consumable_item_id
+	`
+
+	fight := &domain.Fight{}
+	err := r.db.Get(fight, query, string(domain.FightStatusFinished), droppedGold, exp, droppedItemID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return fight, nil
+}
+
+// SetDroppedConsumable records the consumable the beaten bot left behind.
+func (r *FightRepository) SetDroppedConsumable(id, consumableItemID uuid.UUID) error {
+	query := `UPDATE fights SET dropped_consumable_item_id = $1 WHERE id = $2`
+
+	_, err := r.db.Exec(query, consumableItemID, id)
+	return err
+}
+
+// RecordDefeat stores what losing the fight cost the player.
+func (r *FightRepository) RecordDefeat(fight *domain.Fight) error {
+	query := `
+		UPDATE fights
+		SET lost_gold = $1,
+		    lost_exp = $2,
+		    respawn_location_id = $3,
+		    weakened_until = $4
+		WHERE id = $5
+	`
+
+	_, err := r.db.Exec(query, fight.LostGold, fight.LostExp, fight.RespawnLocationID, fight.WeakenedUntil, fight.ID)
+	return err
+}
+
+func (r *FightRepository) FindFinishedByUserID(userID uuid.UUID, filter FightFilter, limit, offset int) ([]*domain.Fight, error) {
+	query := `
+		SELECT * FROM (` + finishedFightsQuery + `) f
+		WHERE ($2::uuid IS NULL OR f.bot_id = $2) AND ($3 = '' OR f.outcome = $3)
+		ORDER BY f.created_at DESC, f.id DESC
+		LIMIT $4 OFFSET $5
+	`
+
+	fights := []*domain.Fight{}
+	err := r.db.Select(&fights, query, userID, filter.BotID, string(filter.Outcome), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return fights, nil
+}
+
+func (r *FightRepository) CountFinishedByUserID(userID uuid.UUID, filter FightFilter) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM (` + finishedFightsQuery + `) f
+		WHERE ($2::uuid IS NULL OR f.bot_id = $2) AND ($3 = '' OR f.outcome = $3)
+	`
+
+	var count int
+	err := r.db.Get(&count, query, userID, filter.BotID, string(filter.Outcome))
+
+	return count, err
+}
+
+// FindFinishedByIDAndUserID returns one of the user's finished fights, so
+// players can only replay their own.
+func (r *FightRepository) FindFinishedByIDAndUserID(id, userID uuid.UUID) (*domain.Fight, error) {
+	query := `
+		SELECT * FROM (` + finishedFightsQuery + `) f
+		WHERE f.id = $2
+	`
+
+	fight := &domain.Fight{}
+	err := r.db.Get(fight, query, userID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrFightNotFound
+		}
+		return nil, err
+	}
+
+	return fight, nil
+}
+
+func (r *FightRepository) Flee(id uuid.UUID) (*domain.Fight, error) {
+	query := `
+		UPDATE fights
+		SET status = $1
+		WHERE id = $2
+		RETURNING id, created_at, deleted_at, user_id, bot_id, status, dropped_gold, exp, dropped_item_id,
+			lost_gold, lost_exp, respawn_location_id, weakened_until, dropped_consumable_item_id
 	`
 
 	fight := &domain.Fight{}
-	err := r.db.Get(fight, query, string(domain.FightStatusFinished), droppedGold, exp, id)
+	err := r.db.Get(fight, query, string(domain.FightStatusFled), id)
 	if err != nil {
 		return nil, err
 	}
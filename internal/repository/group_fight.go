@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+)
+
+var (
+	ErrGroupFightNotFound = errors.New("group fight not found")
+)
+
+type GroupFightRepository struct {
+	db ExtHandle
+}
+
+func NewGroupFightRepository(db ExtHandle) *GroupFightRepository {
+	return &GroupFightRepository{db: db}
+}
+
+func (r *GroupFightRepository) Create(fight *domain.GroupFight) error {
+	query := `
+		INSERT INTO group_fights (leader_id, bot_id, location_id, max_members)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, status
+	`
+
+	return r.db.QueryRow(query,
+		fight.LeaderID, fight.BotID, fight.LocationID, fight.MaxMembers,
+	).Scan(&fight.ID, &fight.CreatedAt, &fight.Status)
+}
+
+func (r *GroupFightRepository) FindByID(id uuid.UUID) (*domain.GroupFight, error) {
+	return r.get(`
+		SELECT id, created_at, deleted_at, leader_id, bot_id, location_id, status, max_members, dropped_item_id
+		FROM group_fights
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id)
+}
+
+func (r *GroupFightRepository) FindByIDForUpdate(id uuid.UUID) (*domain.GroupFight, error) {
+	return r.get(`
+		SELECT id, created_at, deleted_at, leader_id, bot_id, location_id, status, max_members, dropped_item_id
+		FROM group_fights
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`, id)
+}
+
+// FindActiveByUserID returns the running group fight the user is a member of.
+func (r *GroupFightRepository) FindActiveByUserID(userID uuid.UUID) (*domain.GroupFight, error) {
+	return r.get(`
+		SELECT gf.id, gf.created_at, gf.deleted_at, gf.leader_id, gf.bot_id, gf.location_id, gf.status,
+			gf.max_members, gf.dropped_item_id
+		FROM group_fights gf
+		INNER JOIN group_fight_members gfm ON gfm.group_fight_id = gf.id
+		WHERE gfm.user_id = $1 AND gf.status = $2 AND gf.deleted_at IS NULL
+		ORDER BY gf.created_at DESC
+		LIMIT 1
+	`, userID, domain.GroupFightStatusInProgress)
+}
+
+// FindJoinableByLocationID lists the running group fights in the location
+// that still have a free place.
+func (r *GroupFightRepository) FindJoinableByLocationID(locationID uuid.UUID) ([]*domain.GroupFight, error) {
+	query := `
+		SELECT gf.id, gf.created_at, gf.deleted_at, gf.leader_id, gf.bot_id, gf.location_id, gf.status,
+			gf.max_members, gf.dropped_item_id
+		FROM group_fights gf
+		WHERE gf.location_id = $1 AND gf.status = $2 AND gf.deleted_at IS NULL
+		  AND (SELECT COUNT(*) FROM group_fight_members gfm WHERE gfm.group_fight_id = gf.id) < gf.max_members
+		ORDER BY gf.created_at
+	`
+
+	fights := []*domain.GroupFight{}
+	err := r.db.Select(&fights, query, locationID, domain.GroupFightStatusInProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	return fights, nil
+}
+
+func (r *GroupFightRepository) Finish(id uuid.UUID, status domain.GroupFightStatus, droppedItemID *uuid.UUID) error {
+	query := `UPDATE group_fights SET status = $1, dropped_item_id = $2 WHERE id = $3`
+	_, err := r.db.Exec(query, status, droppedItemID, id)
+	return err
+}
+
+func (r *GroupFightRepository) AddMember(fightID, userID uuid.UUID, hp uint) error {
+	query := `INSERT INTO group_fight_members (group_fight_id, user_id, hp) VALUES ($1, $2, $3)`
+	_, err := r.db.Exec(query, fightID, userID, hp)
+	return err
+}
+
+// FindMembers returns the members in the order they joined.
+func (r *GroupFightRepository) FindMembers(fightID uuid.UUID) ([]*domain.GroupFightMember, error) {
+	query := `
+		SELECT gfm.id, gfm.created_at, gfm.group_fight_id, gfm.user_id, u.username, gfm.hp, gfm.damage,
+			gfm.exp, gfm.gold, gfm.lost_gold, gfm.lost_exp, gfm.respawn_location_id, gfm.weakened_until
+		FROM group_fight_members gfm
+		INNER JOIN users u ON u.id = gfm.user_id
+		WHERE gfm.group_fight_id = $1
+		ORDER BY gfm.created_at, gfm.id
+	`
+
+	var members []*domain.GroupFightMember
+	err := r.db.Select(&members, query, fightID)
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *GroupFightRepository) UpdateMember(member *domain.GroupFightMember) error {
+	query := `UPDATE group_fight_members SET hp = $1, damage = $2, exp = $3, gold = $4 WHERE id = $5`
+	_, err := r.db.Exec(query, member.Hp, member.Damage, member.Exp, member.Gold, member.ID)
+	return err
+}
+
+// RecordMemberDefeat stores what being knocked out cost the member.
+func (r *GroupFightRepository) RecordMemberDefeat(member *domain.GroupFightMember) error {
+	query := `
+		UPDATE group_fight_members
+		SET lost_gold = $1,
+		    lost_exp = $2,
+		    respawn_location_id = $3,
+		    weakened_until = $4
+		WHERE id = $5
+	`
+
+	_, err := r.db.Exec(query, member.LostGold, member.LostExp, member.RespawnLocationID, member.WeakenedUntil, member.ID)
+	return err
+}
+
+func (r *GroupFightRepository) get(query string, args ...interface{}) (*domain.GroupFight, error) {
+	fight := &domain.GroupFight{}
+	err := r.db.Get(fight, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGroupFightNotFound
+		}
+		return nil, err
+	}
+
+	return fight, nil
+}
+
+type GroupFightRoundRepository struct {
+	db ExtHandle
+}
+
+func NewGroupFightRoundRepository(db ExtHandle) *GroupFightRoundRepository {
+	return &GroupFightRoundRepository{db: db}
+}
+
+// Create opens a new round that has to be played within turnTimeout.
+func (r *GroupFightRoundRepository) Create(fightID uuid.UUID, botHp uint, turnTimeout time.Duration) error {
+	query := `
+		INSERT INTO group_fight_rounds (group_fight_id, bot_hp, deadline_at, status)
+		VALUES ($1, $2, CURRENT_TIMESTAMP + $3 * INTERVAL '1 second', $4)
+	`
+
+	_, err := r.db.Exec(query, fightID, botHp, turnTimeout.Seconds(), domain.RoundStatusInProgress)
+	return err
+}
+
+func (r *GroupFightRoundRepository) FindByFightID(fightID uuid.UUID) ([]*domain.GroupFightRound, error) {
+	query := `
+		SELECT id, created_at, deleted_at, group_fight_id, status, deadline_at, bot_hp, target_id,
+			bot_attack_point, bot_defense_point, bot_damage, bot_ability, bot_critical, target_dodged,
+			target_shield_blocked
+		FROM group_fight_rounds
+		WHERE group_fight_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	var rounds []*domain.GroupFightRound
+	err := r.db.Select(&rounds, query, fightID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rounds, nil
+}
+
+func (r *GroupFightRoundRepository) FindCurrentForUpdate(fightID uuid.UUID) (*domain.GroupFightRound, error) {
+	query := `
+		SELECT id, created_at, deleted_at, group_fight_id, status, deadline_at, bot_hp, target_id,
+			bot_attack_point, bot_defense_point, bot_damage, bot_ability, bot_critical, target_dodged,
+			target_shield_blocked
+		FROM group_fight_rounds
+		WHERE group_fight_id = $1 AND status = $2 AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1
+		FOR UPDATE
+	`
+
+	round := &domain.GroupFightRound{}
+	err := r.db.Get(round, query, fightID, domain.RoundStatusInProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	return round, nil
+}
+
+// FindExpiredForUpdate locks the current round only if its turn deadline has
+// passed.
+func (r *GroupFightRoundRepository) FindExpiredForUpdate(fightID uuid.UUID) (*domain.GroupFightRound, error) {
+	query := `
+		SELECT id, created_at, deleted_at, group_fight_id, status, deadline_at, bot_hp, target_id,
+			bot_attack_point, bot_defense_point, bot_damage, bot_ability, bot_critical, target_dodged,
+			target_shield_blocked
+		FROM group_fight_rounds
+		WHERE group_fight_id = $1 AND status = $2 AND deadline_at < CURRENT_TIMESTAMP AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1
+		FOR UPDATE
+	`
+
+	round := &domain.GroupFightRound{}
+	err := r.db.Get(round, query, fightID, domain.RoundStatusInProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	return round, nil
+}
+
+// FindExpiredFightIDs returns group fights whose current round is past its
+// turn deadline.
+func (r *GroupFightRoundRepository) FindExpiredFightIDs() ([]uuid.UUID, error) {
+	query := `
+		SELECT DISTINCT group_fight_id
+		FROM group_fight_rounds
+		WHERE status = $1 AND deadline_at < CURRENT_TIMESTAMP AND deleted_at IS NULL
+	`
+
+	var ids []uuid.UUID
+	err := r.db.Select(&ids, query, domain.RoundStatusInProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *GroupFightRoundRepository) FinishRound(round *domain.GroupFightRound) error {
+	query := `
+		UPDATE group_fight_rounds
+		SET bot_hp = $1,
+		    target_id = $2,
+		    bot_attack_point = $3,
+		    bot_defense_point = $4,
+		    bot_damage = $5,
+		    bot_ability = $6,
+		    bot_critical = $7,
+		    target_dodged = $8,
+		    target_shield_blocked = $9,
+		    status = $10
+		WHERE id = $11
+	`
+
+	_, err := r.db.Exec(query,
+		round.BotHp, round.TargetID, round.BotAttackPoint, round.BotDefensePoint,
+		round.BotDamage, round.BotAbility, round.BotCritical, round.TargetDodged, round.TargetShieldBlocked,
+		domain.RoundStatusFinished, round.ID,
+	)
+	return err
+}
+
+func (r *GroupFightRoundRepository) AddMove(move *domain.GroupFightMove) error {
+	query := `
+		INSERT INTO group_fight_moves (round_id, user_id, attack_point, defense_point)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(query,
+		move.RoundID, move.UserID, move.AttackPoint, move.DefensePoint,
+	).Scan(&move.ID, &move.CreatedAt)
+}
+
+// SetMoveHit records how the member's hit on the bot went.
+func (r *GroupFightRoundRepository) SetMoveHit(move *domain.GroupFightMove) error {
+	query := `
+		UPDATE group_fight_moves
+		SET damage = $1, critical = $2, bot_dodged = $3, bot_shield_blocked = $4
+		WHERE id = $5
+	`
+	_, err := r.db.Exec(query, move.Damage, move.Critical, move.BotDodged, move.BotShieldBlocked, move.ID)
+	return err
+}
+
+func (r *GroupFightRoundRepository) FindMovesByRoundID(roundID uuid.UUID) ([]*domain.GroupFightMove, error) {
+	query := `
+		SELECT id, created_at, round_id, user_id, attack_point, defense_point, damage,
+			critical, bot_dodged, bot_shield_blocked
+		FROM group_fight_moves
+		WHERE round_id = $1
+		ORDER BY created_at
+	`
+
+	var moves []*domain.GroupFightMove
+	err := r.db.Select(&moves, query, roundID)
+	if err != nil {
+		return nil, err
+	}
+
+	return moves, nil
+}
+
+// FindMovesByFightID returns the moves of every round of the fight.
+func (r *GroupFightRoundRepository) FindMovesByFightID(fightID uuid.UUID) ([]*domain.GroupFightMove, error) {
+	query := `
+		SELECT m.id, m.created_at, m.round_id, m.user_id, m.attack_point, m.defense_point, m.damage,
+			m.critical, m.bot_dodged, m.bot_shield_blocked
+		FROM group_fight_moves m
+		INNER JOIN group_fight_rounds r ON r.id = m.round_id
+		WHERE r.group_fight_id = $1
+		ORDER BY m.created_at
+	`
+
+	var moves []*domain.GroupFightMove
+	err := r.db.Select(&moves, query, fightID)
+	if err != nil {
+		return nil, err
+	}
+
+	return moves, nil
+}
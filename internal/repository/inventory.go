@@ -49,7 +49,8 @@ func (r *InventoryRepository) FindByUserID(userID uuid.UUID) ([]*domain.Equipmen
 	query := `
 		SELECT 
 			ei.id, ei.created_at, ei.deleted_at, ei.name, ei.slug, ei.attack, ei.defense, ei.hp,
-			ei.required_level, ei.price, ei.artifact, ei.equipment_category_id, ei.image
+			ei.required_level, ei.price, ei.artifact, ei.equipment_category_id, ei.image,
+			ei.crit_chance, ei.crit_power, ei.dodge_chance, ei.block_chance
 		FROM inventory i
 		INNER JOIN equipment_items ei ON i.equipment_item_id = ei.id
 		WHERE i.user_id = $1 
@@ -67,3 +68,59 @@ func (r *InventoryRepository) FindByUserID(userID uuid.UUID) ([]*domain.Equipmen
 
 	return items, nil
 }
+
+func (r *InventoryRepository) CreateConsumable(inventory *domain.InventoryConsumable) error {
+	query := `
+		INSERT INTO inventory (user_id, consumable_item_id)
+		VALUES ($1, $2)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(query,
+		inventory.UserID,
+		inventory.ConsumableItemID,
+	).Scan(&inventory.ID, &inventory.CreatedAt)
+}
+
+// FindConsumablesByUserID lists the consumables the user carries, one entry
+// per inventory row, oldest first.
+func (r *InventoryRepository) FindConsumablesByUserID(userID uuid.UUID) ([]*domain.InventoryConsumable, error) {
+	query := `
+		SELECT i.id, i.created_at, i.deleted_at, i.user_id, i.consumable_item_id
+		FROM inventory i
+		INNER JOIN consumable_items ci ON i.consumable_item_id = ci.id
+		WHERE i.user_id = $1
+			AND i.deleted_at IS NULL
+			AND ci.deleted_at IS NULL
+		ORDER BY i.created_at ASC
+	`
+
+	consumables := []*domain.InventoryConsumable{}
+	err := r.db.Select(&consumables, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return consumables, nil
+}
+
+// TakeConsumable removes one consumable from the user's inventory and returns
+// which item it was. Two concurrent uses of the same row cannot both succeed.
+func (r *InventoryRepository) TakeConsumable(userID, inventoryID uuid.UUID) (uuid.UUID, error) {
+	query := `
+		DELETE FROM inventory
+		WHERE id = $1 AND user_id = $2 AND consumable_item_id IS NOT NULL AND deleted_at IS NULL
+		RETURNING consumable_item_id
+	`
+
+	var itemID uuid.UUID
+	err := r.db.QueryRow(query, inventoryID, userID).Scan(&itemID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, ErrInventoryNotFound
+		}
+		return uuid.Nil, err
+	}
+
+	return itemID, nil
+}
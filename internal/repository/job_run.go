@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"moonshine/internal/domain"
+)
+
+var (
+	ErrJobRunNotFound = errors.New("job run not found")
+)
+
+// jobRunsRetention bounds the history kept per job.
+const jobRunsRetention = 24 * time.Hour
+
+type JobRunRepository struct {
+	db ExtHandle
+}
+
+func NewJobRunRepository(db ExtHandle) *JobRunRepository {
+	return &JobRunRepository{db: db}
+}
+
+// Create records a finished run and prunes runs of the same job older than
+// the retention period.
+func (r *JobRunRepository) Create(run *domain.JobRun) error {
+	query := `
+		WITH pruned AS (
+			DELETE FROM job_runs
+			WHERE job_name = $1 AND started_at < CURRENT_TIMESTAMP - $5 * INTERVAL '1 second'
+		)
+		INSERT INTO job_runs (job_name, instance_id, started_at, duration_ms, error)
+		VALUES ($1, $2, CURRENT_TIMESTAMP - $3 * INTERVAL '1 millisecond', $3, $4)
+		RETURNING id, started_at, finished_at
+	`
+
+	return r.db.QueryRow(query,
+		run.JobName, run.InstanceID, run.DurationMs, run.Error, jobRunsRetention.Seconds(),
+	).Scan(&run.ID, &run.StartedAt, &run.FinishedAt)
+}
+
+func (r *JobRunRepository) FindLastByJobName(jobName string) (*domain.JobRun, error) {
+	query := `
+		SELECT id, job_name, instance_id, started_at, finished_at, duration_ms, error
+		FROM job_runs
+		WHERE job_name = $1
+		ORDER BY started_at DESC
+		LIMIT 1
+	`
+
+	run := &domain.JobRun{}
+	err := r.db.Get(run, query, jobName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrJobRunNotFound
+		}
+		return nil, err
+	}
+
+	return run, nil
+}
+
+func (r *JobRunRepository) FindByJobName(jobName string, limit, offset int) ([]*domain.JobRun, error) {
+	query := `
+		SELECT id, job_name, instance_id, started_at, finished_at, duration_ms, error
+		FROM job_runs
+		WHERE job_name = $1
+		ORDER BY started_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	runs := []*domain.JobRun{}
+	err := r.db.Select(&runs, query, jobName, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+)
+
+func TestJobRunRepository_CreateAndFind(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Test database not initialized")
+	}
+
+	repo := NewJobRunRepository(testDB.DB())
+	jobName := fmt.Sprintf("test_job_%d", time.Now().UnixNano())
+
+	_, err := repo.FindLastByJobName(jobName)
+	assert.Equal(t, ErrJobRunNotFound, err)
+
+	require.NoError(t, repo.Create(&domain.JobRun{JobName: jobName, InstanceID: "a", DurationMs: 12}))
+
+	msg := "boom"
+	failed := &domain.JobRun{JobName: jobName, InstanceID: "b", DurationMs: 3, Error: &msg}
+	require.NoError(t, repo.Create(failed))
+	assert.False(t, failed.FinishedAt.Before(failed.StartedAt))
+
+	last, err := repo.FindLastByJobName(jobName)
+	require.NoError(t, err)
+	assert.Equal(t, failed.ID, last.ID)
+	assert.True(t, last.Failed())
+	assert.Equal(t, 3*time.Millisecond, last.Duration())
+
+	runs, err := repo.FindByJobName(jobName, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, runs, 2)
+}
@@ -28,13 +28,13 @@ func (r *LocationRepository) Create(location *domain.Location) error {
 	query := `
 		INSERT INTO locations (name, slug, cell, inactive, image, image_bg)
 		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, created_at
+		RETURNING id, created_at, regen_multiplier
 	`
 
 	err := r.db.QueryRow(query,
 		location.Name, location.Slug, location.Cell, location.Inactive,
 		location.Image, location.ImageBg,
-	).Scan(&location.ID, &location.CreatedAt)
+	).Scan(&location.ID, &location.CreatedAt, &location.RegenMultiplier)
 	if err != nil {
 		if isUniqueConstraintError(err) {
 			return ErrLocationExists
@@ -44,9 +44,40 @@ func (r *LocationRepository) Create(location *domain.Location) error {
 	return nil
 }
 
+// UpdateRegenMultiplier sets how much faster than usual HP comes back to
+// players standing in the location. Players already there switch to the new
+// rate, except those in a fight, whose HP stays frozen.
+func (r *LocationRepository) UpdateRegenMultiplier(id uuid.UUID, multiplier float64) error {
+	query := `
+		WITH location AS (
+			UPDATE locations SET regen_multiplier = $1 WHERE id = $2 AND deleted_at IS NULL RETURNING id
+		)
+		UPDATE users
+		SET current_hp = regenerated_hp(users.current_hp, users.hp, users.hp_changed_at, users.hp_regen_rate),
+		    hp_changed_at = CURRENT_TIMESTAMP,
+		    hp_regen_rate = $1
+		FROM location
+		WHERE users.location_id = location.id
+		    AND users.deleted_at IS NULL
+		    AND NOT EXISTS (SELECT 1 FROM fights WHERE user_id = users.id AND status = $3 AND deleted_at IS NULL)
+		    AND NOT EXISTS (
+		        SELECT 1 FROM duels
+		        WHERE (challenger_id = users.id OR opponent_id = users.id) AND status = $4 AND deleted_at IS NULL
+		    )
+		    AND NOT EXISTS (
+		        SELECT 1 FROM group_fights gf
+		        INNER JOIN group_fight_members gfm ON gfm.group_fight_id = gf.id
+		        WHERE gfm.user_id = users.id AND gfm.hp > 0 AND gf.status = $5 AND gf.deleted_at IS NULL
+		    )
+	`
+	_, err := r.db.Exec(query, multiplier, id, domain.FightStatusInProgress, domain.DuelStatusInProgress,
+		domain.GroupFightStatusInProgress)
+	return err
+}
+
 func (r *LocationRepository) FindByID(id uuid.UUID) (*domain.Location, error) {
 	query := `
-		SELECT id, created_at, deleted_at, name, slug, cell, inactive, image, image_bg
+		SELECT id, created_at, deleted_at, name, slug, cell, inactive, image, image_bg, regen_multiplier
 		FROM locations
 		WHERE id = $1 AND deleted_at IS NULL
 	`
@@ -65,7 +96,7 @@ func (r *LocationRepository) FindByID(id uuid.UUID) (*domain.Location, error) {
 
 func (r *LocationRepository) FindStartLocation() (*domain.Location, error) {
 	query := `
-		SELECT id, created_at, deleted_at, name, slug, cell, inactive, image, image_bg
+		SELECT id, created_at, deleted_at, name, slug, cell, inactive, image, image_bg, regen_multiplier
 		FROM locations
 		WHERE slug = $1 AND deleted_at IS NULL
 	`
@@ -84,7 +115,7 @@ func (r *LocationRepository) FindStartLocation() (*domain.Location, error) {
 
 func (r *LocationRepository) FindBySlug(slug string) (*domain.Location, error) {
 	query := `
-		SELECT id, created_at, deleted_at, name, slug, cell, inactive, image, image_bg
+		SELECT id, created_at, deleted_at, name, slug, cell, inactive, image, image_bg, regen_multiplier
 		FROM locations
 		WHERE slug = $1 AND deleted_at IS NULL
 	`
@@ -103,7 +134,7 @@ func (r *LocationRepository) FindBySlug(slug string) (*domain.Location, error) {
 
 func (r *LocationRepository) FindCellsByLocationID(locationID uuid.UUID) ([]*domain.Location, error) {
 	query := `
-		SELECT id, created_at, deleted_at, name, slug, cell, inactive, image, image_bg
+		SELECT id, created_at, deleted_at, name, slug, cell, inactive, image, image_bg, regen_multiplier
 		FROM locations
 		WHERE cell = true 
 		AND deleted_at IS NULL
@@ -121,7 +152,7 @@ func (r *LocationRepository) FindCellsByLocationID(locationID uuid.UUID) ([]*dom
 
 func (r *LocationRepository) FindAllCells() ([]*domain.Location, error) {
 	query := `
-		SELECT id, created_at, deleted_at, name, slug, cell, inactive, image, image_bg
+		SELECT id, created_at, deleted_at, name, slug, cell, inactive, image, image_bg, regen_multiplier
 		FROM locations
 		WHERE cell = true 
 		AND deleted_at IS NULL
@@ -155,7 +186,7 @@ func (r *LocationRepository) FindAllConnections() ([]*domain.LocationLocation, e
 
 func (r *LocationRepository) FindAll() ([]*domain.Location, error) {
 	query := `
-		SELECT id, created_at, deleted_at, name, slug, cell, inactive, image, image_bg
+		SELECT id, created_at, deleted_at, name, slug, cell, inactive, image, image_bg, regen_multiplier
 		FROM locations
 		WHERE deleted_at IS NULL
 	`
@@ -171,7 +202,7 @@ func (r *LocationRepository) FindAll() ([]*domain.Location, error) {
 
 func (r *LocationRepository) DefaultOutdoorLocation() (*domain.Location, error) {
 	query := `
-		SELECT id, created_at, deleted_at, name, slug, cell, inactive, image, image_bg
+		SELECT id, created_at, deleted_at, name, slug, cell, inactive, image, image_bg, regen_multiplier
 		FROM locations
 		WHERE slug = $1 AND deleted_at IS NULL
 	`
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"moonshine/internal/domain"
+)
+
+var (
+	ErrMovementNotFound = errors.New("movement not found")
+)
+
+type MovementRepository struct {
+	db ExtHandle
+}
+
+func NewMovementRepository(db ExtHandle) *MovementRepository {
+	return &MovementRepository{db: db}
+}
+
+// Upsert replaces the user's current movement plan. The first step is taken
+// one StepSeconds interval from now.
+func (r *MovementRepository) Upsert(movement *domain.Movement) error {
+	query := `
+		INSERT INTO movements (user_id, path, step_index, step_seconds, next_step_at)
+		VALUES ($1, $2, 0, $3, CURRENT_TIMESTAMP + $3 * INTERVAL '1 second')
+		ON CONFLICT (user_id) DO UPDATE
+		SET path = EXCLUDED.path,
+		    step_index = 0,
+		    step_seconds = EXCLUDED.step_seconds,
+		    next_step_at = EXCLUDED.next_step_at,
+		    created_at = CURRENT_TIMESTAMP
+		RETURNING id, created_at, step_index, next_step_at
+	`
+
+	return r.db.QueryRow(query,
+		movement.UserID, pq.StringArray(movement.Path), movement.StepSeconds,
+	).Scan(&movement.ID, &movement.CreatedAt, &movement.StepIndex, &movement.NextStepAt)
+}
+
+func (r *MovementRepository) FindByUserID(userID uuid.UUID) (*domain.Movement, error) {
+	query := `
+		SELECT id, created_at, deleted_at, user_id, path, step_index, step_seconds, next_step_at
+		FROM movements
+		WHERE user_id = $1 AND deleted_at IS NULL
+	`
+
+	movement := &domain.Movement{}
+	err := r.db.Get(movement, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMovementNotFound
+		}
+		return nil, err
+	}
+
+	return movement, nil
+}
+
+// FindNextDueForUpdate locks one movement whose next step is due. Rows locked
+// by other instances are skipped, so several workers can share the table.
+func (r *MovementRepository) FindNextDueForUpdate() (*domain.Movement, error) {
+	query := `
+		SELECT id, created_at, deleted_at, user_id, path, step_index, step_seconds, next_step_at
+		FROM movements
+		WHERE next_step_at <= CURRENT_TIMESTAMP AND deleted_at IS NULL
+		ORDER BY next_step_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	movement := &domain.Movement{}
+	err := r.db.Get(movement, query)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMovementNotFound
+		}
+		return nil, err
+	}
+
+	return movement, nil
+}
+
+func (r *MovementRepository) Advance(id uuid.UUID, stepIndex, stepSeconds int) error {
+	query := `
+		UPDATE movements
+		SET step_index = $1,
+		    next_step_at = CURRENT_TIMESTAMP + $2 * INTERVAL '1 second'
+		WHERE id = $3
+	`
+	_, err := r.db.Exec(query, stepIndex, stepSeconds, id)
+	return err
+}
+
+// Postpone pushes the next step back by delay without advancing the plan.
+func (r *MovementRepository) Postpone(id uuid.UUID, delay time.Duration) error {
+	query := `UPDATE movements SET next_step_at = CURRENT_TIMESTAMP + $1 * INTERVAL '1 second' WHERE id = $2`
+	_, err := r.db.Exec(query, delay.Seconds(), id)
+	return err
+}
+
+func (r *MovementRepository) Delete(id uuid.UUID) error {
+	query := `DELETE FROM movements WHERE id = $1`
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+// DeleteByUserID drops the user's plan and reports whether there was one.
+func (r *MovementRepository) DeleteByUserID(userID uuid.UUID) (bool, error) {
+	query := `DELETE FROM movements WHERE user_id = $1`
+	res, err := r.db.Exec(query, userID)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+
+	"moonshine/internal/domain"
+)
+
+type MovementLogRepository struct {
+	db ExtHandle
+}
+
+func NewMovementLogRepository(db ExtHandle) *MovementLogRepository {
+	return &MovementLogRepository{db: db}
+}
+
+func (r *MovementLogRepository) Create(movementLog *domain.MovementLog) error {
+	query := `
+		INSERT INTO movement_logs (user_id, from_cell, to_cell)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(query,
+		movementLog.UserID, movementLog.FromCell, movementLog.ToCell,
+	).Scan(&movementLog.ID, &movementLog.CreatedAt)
+}
+
+func (r *MovementLogRepository) FindByUserID(userID uuid.UUID, limit, offset int) ([]*domain.MovementLog, error) {
+	query := `
+		SELECT id, user_id, from_cell, to_cell, created_at
+		FROM movement_logs
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	logs := []*domain.MovementLog{}
+	err := r.db.Select(&logs, query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+func (r *MovementLogRepository) CountByUserID(userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM movement_logs WHERE user_id = $1`
+
+	var count int
+	err := r.db.Get(&count, query, userID)
+
+	return count, err
+}
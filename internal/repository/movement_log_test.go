@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+)
+
+func TestMovementLogRepository_CreateAndFind(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Test database not initialized")
+	}
+
+	repo := NewMovementLogRepository(testDB.DB())
+	userRepo := NewUserRepository(testDB.DB())
+	locationRepo := NewLocationRepository(testDB.DB())
+	ts := time.Now().UnixNano()
+
+	location := &domain.Location{
+		Name: fmt.Sprintf("Test Location %d", ts),
+		Slug: fmt.Sprintf("test-location-%d", ts),
+	}
+	require.NoError(t, locationRepo.Create(location))
+
+	user := &domain.User{
+		Username:   fmt.Sprintf("mover%d", ts),
+		Email:      fmt.Sprintf("mover%d@example.com", ts),
+		Password:   "hashedpassword",
+		LocationID: location.ID,
+	}
+	require.NoError(t, userRepo.Create(user))
+
+	from := "1cell"
+	require.NoError(t, repo.Create(&domain.MovementLog{UserID: user.ID, ToCell: "1cell"}))
+	second := &domain.MovementLog{UserID: user.ID, FromCell: &from, ToCell: "2cell"}
+	require.NoError(t, repo.Create(second))
+	assert.NotEqual(t, uuid.Nil, second.ID)
+
+	total, err := repo.CountByUserID(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+
+	logs, err := repo.FindByUserID(user.ID, 1, 0)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "2cell", logs[0].ToCell)
+	require.NotNil(t, logs[0].FromCell)
+	assert.Equal(t, "1cell", *logs[0].FromCell)
+
+	logs, err = repo.FindByUserID(user.ID, 10, 1)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "1cell", logs[0].ToCell)
+	assert.Nil(t, logs[0].FromCell)
+}
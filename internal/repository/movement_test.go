@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"moonshine/internal/domain"
+)
+
+func TestMovementRepository(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Test database not initialized")
+	}
+
+	repo := NewMovementRepository(testDB.DB())
+	userRepo := NewUserRepository(testDB.DB())
+	locationRepo := NewLocationRepository(testDB.DB())
+	ts := time.Now().UnixNano()
+
+	location := &domain.Location{
+		Name: fmt.Sprintf("Test Location %d", ts),
+		Slug: fmt.Sprintf("test-location-%d", ts),
+	}
+	require.NoError(t, locationRepo.Create(location))
+
+	user := &domain.User{
+		Username:   fmt.Sprintf("walker%d", ts),
+		Email:      fmt.Sprintf("walker%d@example.com", ts),
+		Password:   "hashedpassword",
+		LocationID: location.ID,
+	}
+	require.NoError(t, userRepo.Create(user))
+
+	_, err := repo.FindByUserID(user.ID)
+	assert.Equal(t, ErrMovementNotFound, err)
+
+	movement := &domain.Movement{UserID: user.ID, Path: []string{"1cell", "2cell"}, StepSeconds: 5}
+	require.NoError(t, repo.Upsert(movement))
+
+	replaced := &domain.Movement{UserID: user.ID, Path: []string{"3cell", "4cell", "5cell"}, StepSeconds: 5}
+	require.NoError(t, repo.Upsert(replaced))
+	assert.Equal(t, movement.ID, replaced.ID)
+
+	found, err := repo.FindByUserID(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3cell", "4cell", "5cell"}, []string(found.Path))
+	assert.Equal(t, 0, found.StepIndex)
+
+	require.NoError(t, repo.Advance(found.ID, 1, 0))
+	advanced, err := repo.FindByUserID(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, advanced.StepIndex)
+	assert.Equal(t, "4cell", advanced.NextCell())
+
+	require.NoError(t, repo.Postpone(found.ID, time.Minute))
+	postponed, err := repo.FindByUserID(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, postponed.StepIndex)
+	assert.True(t, postponed.NextStepAt.After(advanced.NextStepAt.Add(30*time.Second)))
+
+	require.NoError(t, repo.Delete(found.ID))
+	_, err = repo.FindByUserID(user.ID)
+	assert.Equal(t, ErrMovementNotFound, err)
+}
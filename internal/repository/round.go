@@ -1,11 +1,19 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
+	"time"
+
 	"github.com/google/uuid"
 
 	"moonshine/internal/domain"
 )
 
+var (
+	ErrRoundNotInProgress = errors.New("round is not in progress")
+)
+
 type RoundRepository struct {
 	db ExtHandle
 }
@@ -28,7 +36,8 @@ func (r *RoundRepository) FindByFightID(fightID uuid.UUID) ([]*domain.Round, err
 	query := `
 		SELECT id, created_at, deleted_at, fight_id, player_damage, bot_damage, 
 			status, player_hp, bot_hp, player_attack_point, player_defense_point, 
-			bot_attack_point, bot_defense_point
+			bot_attack_point, bot_defense_point, timed_out, bot_ability, critical, dodged, shield_blocked,
+			bot_critical, bot_dodged, bot_shield_blocked, consumable_item_id
 		FROM rounds 
 		WHERE fight_id = $1 AND deleted_at IS NULL 
 		ORDER BY created_at DESC
@@ -66,3 +75,99 @@ func (r *RoundRepository) FinishRound(id uuid.UUID, botAttackPoint, botDefensePo
 
 	return nil
 }
+
+// FinishFreeHit closes a round in which only the bot struck, e.g. after a
+// failed escape. The player's points stay empty.
+func (r *RoundRepository) FinishFreeHit(id uuid.UUID, botAttackPoint string, botDmg, finalPlayerHp uint) error {
+	query := `
+		UPDATE rounds
+		SET bot_attack_point = $1,
+		    bot_damage = $2,
+		    player_hp = $3,
+		    status = $4
+		WHERE id = $5
+	`
+
+	_, err := r.db.Exec(query, botAttackPoint, botDmg, finalPlayerHp, domain.RoundStatusFinished, id)
+	return err
+}
+
+// Abandon closes a round nobody moved in, leaving HP untouched.
+func (r *RoundRepository) Abandon(id uuid.UUID) error {
+	query := `UPDATE rounds SET status = $1 WHERE id = $2`
+
+	_, err := r.db.Exec(query, domain.RoundStatusFinished, id)
+	return err
+}
+
+// LockInProgress locks the round for the rest of the transaction and fails
+// with ErrRoundNotInProgress if someone else already finished it.
+func (r *RoundRepository) LockInProgress(id uuid.UUID) error {
+	query := `SELECT id FROM rounds WHERE id = $1 AND status = $2 FOR UPDATE`
+
+	var lockedID uuid.UUID
+	err := r.db.Get(&lockedID, query, id, domain.RoundStatusInProgress)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrRoundNotInProgress
+	}
+	return err
+}
+
+func (r *RoundRepository) MarkTimedOut(id uuid.UUID) error {
+	query := `UPDATE rounds SET timed_out = true WHERE id = $1`
+
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+func (r *RoundRepository) SetBotAbility(id uuid.UUID, ability domain.BotAbility) error {
+	query := `UPDATE rounds SET bot_ability = $1 WHERE id = $2`
+
+	_, err := r.db.Exec(query, ability, id)
+	return err
+}
+
+func (r *RoundRepository) SetHitFlags(id uuid.UUID, critical, dodged, shieldBlocked bool) error {
+	query := `UPDATE rounds SET critical = $1, dodged = $2, shield_blocked = $3 WHERE id = $4`
+
+	_, err := r.db.Exec(query, critical, dodged, shieldBlocked, id)
+	return err
+}
+
+// SetBotHitFlags records the rolls of the bot's side of the round.
+func (r *RoundRepository) SetBotHitFlags(id uuid.UUID, critical, dodged, shieldBlocked bool) error {
+	query := `UPDATE rounds SET bot_critical = $1, bot_dodged = $2, bot_shield_blocked = $3 WHERE id = $4`
+
+	_, err := r.db.Exec(query, critical, dodged, shieldBlocked, id)
+	return err
+}
+
+// SetConsumable records the item the player used instead of striking.
+func (r *RoundRepository) SetConsumable(id, consumableItemID uuid.UUID) error {
+	query := `UPDATE rounds SET consumable_item_id = $1 WHERE id = $2`
+
+	_, err := r.db.Exec(query, consumableItemID, id)
+	return err
+}
+
+// FindIdleFightIDs returns fights whose current round has been waiting for
+// the player longer than timeout.
+func (r *RoundRepository) FindIdleFightIDs(timeout time.Duration) ([]uuid.UUID, error) {
+	query := `
+		SELECT r.fight_id
+		FROM rounds r
+		INNER JOIN fights f ON f.id = r.fight_id
+		WHERE r.status = $1 AND f.status = $2
+		  AND r.created_at <= CURRENT_TIMESTAMP - $3 * INTERVAL '1 second'
+		  AND r.deleted_at IS NULL AND f.deleted_at IS NULL
+		ORDER BY r.created_at
+	`
+
+	var ids []uuid.UUID
+	err := r.db.Select(&ids, query, domain.RoundStatusInProgress, domain.FightStatusInProgress, timeout.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -12,10 +13,20 @@ import (
 )
 
 var (
-	ErrUserNotFound = errors.New("user not found")
-	ErrUserExists   = errors.New("user already exists")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUserExists         = errors.New("user already exists")
+	ErrNotEnoughFreeStats = errors.New("not enough free stats")
+	ErrNotEnoughGold      = errors.New("not enough gold")
 )
 
+// currentHpColumn is the user's HP right now. The current_hp column only holds
+// it as of hp_changed_at; regeneration since then is worked out on read.
+const currentHpColumn = `regenerated_hp(users.current_hp, users.hp, users.hp_changed_at, users.hp_regen_rate)`
+
+// locationRegenRate is the regen rate of the location the user stands in,
+// used whenever HP starts regenerating again.
+const locationRegenRate = `(SELECT l.regen_multiplier FROM locations l WHERE l.id = users.location_id)`
+
 type UserRepository struct {
 	db           *sqlx.DB
 	locationRepo *LocationRepository
@@ -34,9 +45,10 @@ func (r *UserRepository) Create(user *domain.User) error {
 	query := `
 		INSERT INTO users (
 			username, email, password, name, avatar_id, location_id,
-			attack, defense, current_hp, exp, free_stats, gold, hp, level
+			attack, defense, current_hp, exp, free_stats, gold, hp, level, hp_regen_rate
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14,
+			COALESCE((SELECT regen_multiplier FROM locations WHERE id = $6), 1)
 		)
 		RETURNING id, created_at, updated_at
 	`
@@ -55,22 +67,28 @@ func (r *UserRepository) Create(user *domain.User) error {
 }
 
 func (r *UserRepository) FindByID(id uuid.UUID) (*domain.User, error) {
+	return r.FindByIDWithExt(r.db, id)
+}
+
+func (r *UserRepository) FindByIDWithExt(h ExtHandle, id uuid.UUID) (*domain.User, error) {
 	query := `
 		SELECT users.id, users.created_at, users.updated_at, users.deleted_at, users.username, users.email, users.password, users.name, 
-			users.avatar_id, users.location_id, users.attack, users.defense, users.current_hp, users.exp,
-			users.free_stats, users.gold, users.hp, users.level,
+			users.avatar_id, users.location_id, users.attack, users.defense,
+			` + currentHpColumn + ` AS current_hp, users.exp,
+			users.free_stats, users.allocated_attack, users.allocated_defense, users.allocated_hp,
+			users.gold, users.hp, users.level,
 			users.chest_equipment_item_id, users.belt_equipment_item_id, users.head_equipment_item_id,
 			users.neck_equipment_item_id, users.weapon_equipment_item_id, users.shield_equipment_item_id,
 			users.legs_equipment_item_id, users.feet_equipment_item_id, users.arms_equipment_item_id,
 			users.hands_equipment_item_id, users.ring1_equipment_item_id, users.ring2_equipment_item_id,
-			users.ring3_equipment_item_id, users.ring4_equipment_item_id, avatars.image as avatar
+			users.ring3_equipment_item_id, users.ring4_equipment_item_id, users.weakened_until, avatars.image as avatar
 		FROM users
 		LEFT JOIN avatars ON avatars.id = users.avatar_id
 		WHERE users.id = $1 AND users.deleted_at IS NULL
 	`
 
 	user := &domain.User{}
-	err := r.db.Get(user, query, id)
+	err := h.Get(user, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrUserNotFound
@@ -84,13 +102,15 @@ func (r *UserRepository) FindByID(id uuid.UUID) (*domain.User, error) {
 func (r *UserRepository) FindByUsername(username string) (*domain.User, error) {
 	query := `
 		SELECT users.id, users.created_at, users.updated_at, users.deleted_at, users.username, users.email, users.password, users.name, 
-			users.avatar_id, users.location_id, users.attack, users.defense, users.current_hp, users.exp,
-			users.free_stats, users.gold, users.hp, users.level,
+			users.avatar_id, users.location_id, users.attack, users.defense,
+			` + currentHpColumn + ` AS current_hp, users.exp,
+			users.free_stats, users.allocated_attack, users.allocated_defense, users.allocated_hp,
+			users.gold, users.hp, users.level,
 			users.chest_equipment_item_id, users.belt_equipment_item_id, users.head_equipment_item_id,
 			users.neck_equipment_item_id, users.weapon_equipment_item_id, users.shield_equipment_item_id,
 			users.legs_equipment_item_id, users.feet_equipment_item_id, users.arms_equipment_item_id,
 			users.hands_equipment_item_id, users.ring1_equipment_item_id, users.ring2_equipment_item_id,
-			users.ring3_equipment_item_id, users.ring4_equipment_item_id, avatars.image as avatar
+			users.ring3_equipment_item_id, users.ring4_equipment_item_id, users.weakened_until, avatars.image as avatar
 		FROM users
 		LEFT JOIN avatars ON avatars.id = users.avatar_id
 		WHERE users.username = $1 AND users.deleted_at IS NULL
@@ -108,6 +128,62 @@ func (r *UserRepository) FindByUsername(username string) (*domain.User, error) {
 	return user, nil
 }
 
+// FindArmour sums the defense of the items the user wears on each body part.
+func (r *UserRepository) FindArmour(userID uuid.UUID) (domain.Armour, error) {
+	query := `
+		SELECT COALESCE(head.defense, 0) AS head,
+		       COALESCE(neck.defense, 0) AS neck,
+		       COALESCE(chest.defense, 0) AS chest,
+		       COALESCE(belt.defense, 0) AS belt,
+		       COALESCE(legs.defense, 0) AS legs
+		FROM users
+		LEFT JOIN equipment_items head ON head.id = users.head_equipment_item_id
+		LEFT JOIN equipment_items neck ON neck.id = users.neck_equipment_item_id
+		LEFT JOIN equipment_items chest ON chest.id = users.chest_equipment_item_id
+		LEFT JOIN equipment_items belt ON belt.id = users.belt_equipment_item_id
+		LEFT JOIN equipment_items legs ON legs.id = users.legs_equipment_item_id
+		WHERE users.id = $1 AND users.deleted_at IS NULL
+	`
+
+	var armour domain.Armour
+	err := r.db.Get(&armour, query, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return armour, ErrUserNotFound
+		}
+		return armour, err
+	}
+
+	return armour, nil
+}
+
+// FindCombatStats sums the secondary stats of everything the user wears. Only
+// the shield gives block chance.
+func (r *UserRepository) FindCombatStats(userID uuid.UUID) (domain.CombatStats, error) {
+	query := `
+		SELECT COALESCE(SUM(ei.crit_chance), 0) AS crit_chance,
+		       COALESCE(SUM(ei.crit_power), 0) AS crit_power,
+		       COALESCE(SUM(ei.dodge_chance), 0) AS dodge_chance,
+		       COALESCE(SUM(ei.block_chance) FILTER (WHERE slots.slot = 'shield'), 0) AS block_chance
+		FROM users
+		CROSS JOIN LATERAL (VALUES
+			('chest', users.chest_equipment_item_id), ('belt', users.belt_equipment_item_id),
+			('head', users.head_equipment_item_id), ('neck', users.neck_equipment_item_id),
+			('weapon', users.weapon_equipment_item_id), ('shield', users.shield_equipment_item_id),
+			('legs', users.legs_equipment_item_id), ('feet', users.feet_equipment_item_id),
+			('arms', users.arms_equipment_item_id), ('hands', users.hands_equipment_item_id),
+			('ring', users.ring1_equipment_item_id), ('ring', users.ring2_equipment_item_id),
+			('ring', users.ring3_equipment_item_id), ('ring', users.ring4_equipment_item_id)
+		) AS slots(slot, item_id)
+		INNER JOIN equipment_items ei ON ei.id = slots.item_id
+		WHERE users.id = $1 AND users.deleted_at IS NULL
+	`
+
+	var stats domain.CombatStats
+	err := r.db.Get(&stats, query, userID)
+	return stats, err
+}
+
 func (r *UserRepository) UpdateGold(userID uuid.UUID, newGold uint) error {
 	query := `UPDATE users SET gold = $1 WHERE id = $2 AND deleted_at IS NULL`
 	_, err := r.db.Exec(query, newGold, userID)
@@ -126,31 +202,6 @@ type HPUpdate struct {
 	Hp        uint      `db:"hp"`
 }
 
-func (r *UserRepository) RegenerateAllUsersHealth(percent float64) ([]HPUpdate, error) {
-	query := `
-		UPDATE users 
-		SET current_hp = LEAST(
-			current_hp + GREATEST(1, ROUND(hp * $1 / 100.0)), 
-			hp
-		)
-		WHERE users.deleted_at IS NULL 
-		    AND current_hp < hp
-		    AND NOT EXISTS (
-		        SELECT 1 FROM fights 
-		        WHERE fights.user_id = users.id 
-		        AND fights.status = $2
-		        AND fights.deleted_at IS NULL
-		    )
-		RETURNING id, current_hp, hp
-	`
-	var updates []HPUpdate
-	err := r.db.Select(&updates, query, percent, domain.FightStatusInProgress)
-	if err != nil {
-		return nil, err
-	}
-	return updates, nil
-}
-
 func isUniqueConstraintError(err error) bool {
 	if err == nil {
 		return false
@@ -162,33 +213,301 @@ func isUniqueConstraintError(err error) bool {
 }
 
 func (r *UserRepository) UpdateLocationID(userID uuid.UUID, locationID uuid.UUID) error {
-	query := `UPDATE users SET location_id = $1 WHERE id = $2`
-	_, err := r.db.Exec(query, locationID, userID)
+	return r.UpdateLocationIDWithExt(r.db, userID, locationID)
+}
+
+// UpdateLocationIDWithExt moves the user and switches their HP regeneration to
+// the new location's rate, keeping what they have regenerated so far.
+func (r *UserRepository) UpdateLocationIDWithExt(h ExtHandle, userID uuid.UUID, locationID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET location_id = $1,
+		    current_hp = ` + currentHpColumn + `,
+		    hp_changed_at = CURRENT_TIMESTAMP,
+		    hp_regen_rate = (SELECT regen_multiplier FROM locations WHERE id = $1)
+		WHERE id = $2
+	`
+	_, err := h.Exec(query, locationID, userID)
 	return err
 }
 
-func (r *UserRepository) Update(userID uuid.UUID, addedGold, addedExp, newLevel, newCurrentHp uint) error {
-	return r.UpdateWithExt(r.db, userID, addedGold, addedExp, newLevel, newCurrentHp)
+func (r *UserRepository) Update(userID uuid.UUID, addedGold, addedExp, newLevel, newCurrentHp, addedFreeStats uint) error {
+	return r.UpdateWithExt(r.db, userID, addedGold, addedExp, newLevel, newCurrentHp, addedFreeStats)
 }
 
-func (r *UserRepository) UpdateWithExt(h ExtHandle, userID uuid.UUID, addedGold, addedExp, newLevel, newCurrentHp uint) error {
+func (r *UserRepository) UpdateWithExt(h ExtHandle, userID uuid.UUID, addedGold, addedExp, newLevel, newCurrentHp, addedFreeStats uint) error {
 	query := `
 		UPDATE users 
 		SET gold = gold + $1, 
 		    exp = exp + $2, 
 		    level = $3,
-		    current_hp = $4
+		    current_hp = $4,
+		    hp_changed_at = CURRENT_TIMESTAMP,
+		    hp_regen_rate = ` + locationRegenRate + `,
+		    free_stats = free_stats + $5
+		WHERE id = $6 AND deleted_at IS NULL
+	`
+	_, err := h.Exec(query, addedGold, addedExp, newLevel, newCurrentHp, addedFreeStats, userID)
+	return err
+}
+
+// AddLevelStatsWithExt raises the user's base stats by what their new levels
+// grant. Allocated points are untouched, so a stat reset keeps them.
+func (r *UserRepository) AddLevelStatsWithExt(h ExtHandle, userID uuid.UUID, hp, attack, defense uint) error {
+	query := `
+		UPDATE users
+		SET hp = hp + $1, attack = attack + $2, defense = defense + $3
+		WHERE id = $4 AND deleted_at IS NULL
+	`
+	_, err := h.Exec(query, hp, attack, defense, userID)
+	return err
+}
+
+// UpdateCurrentHpWithExt sets the user's HP once a fight is over and lets it
+// regenerate again at their location's rate.
+func (r *UserRepository) UpdateCurrentHpWithExt(h ExtHandle, userID uuid.UUID, currentHp uint) error {
+	query := `
+		UPDATE users
+		SET current_hp = $1, hp_changed_at = CURRENT_TIMESTAMP, hp_regen_rate = ` + locationRegenRate + `
+		WHERE id = $2 AND deleted_at IS NULL
+	`
+	_, err := h.Exec(query, currentHp, userID)
+	return err
+}
+
+// FreezeHpWithExt stops the user's HP regeneration for the length of a fight
+// and returns the HP they go into it with.
+func (r *UserRepository) FreezeHpWithExt(h ExtHandle, userID uuid.UUID) (uint, error) {
+	query := `
+		UPDATE users
+		SET current_hp = ` + currentHpColumn + `, hp_changed_at = CURRENT_TIMESTAMP, hp_regen_rate = 0
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING current_hp
+	`
+
+	var currentHp uint
+	err := h.QueryRow(query, userID).Scan(&currentHp)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrUserNotFound
+		}
+		return 0, err
+	}
+
+	return currentHp, nil
+}
+
+// UpdateFightHpWithExt sets the HP of a user who is still fighting; it stays
+// frozen until the fight is over.
+func (r *UserRepository) UpdateFightHpWithExt(h ExtHandle, userID uuid.UUID, currentHp uint) error {
+	query := `UPDATE users SET current_hp = $1, hp_changed_at = CURRENT_TIMESTAMP WHERE id = $2 AND deleted_at IS NULL`
+	_, err := h.Exec(query, currentHp, userID)
+	return err
+}
+
+// HealWithExt adds amount to the user's current HP, capped at their max HP,
+// and returns what they end up with.
+func (r *UserRepository) HealWithExt(h ExtHandle, userID uuid.UUID, amount uint) (uint, error) {
+	query := `
+		UPDATE users
+		SET current_hp = GREATEST(` + currentHpColumn + `, LEAST(hp, ` + currentHpColumn + ` + $1)),
+		    hp_changed_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND deleted_at IS NULL
+		RETURNING current_hp
+	`
+
+	var currentHp uint
+	err := h.QueryRow(query, amount, userID).Scan(&currentHp)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrUserNotFound
+		}
+		return 0, err
+	}
+
+	return currentHp, nil
+}
+
+// InnHealWithExt restores the user standing in innID to full HP for price
+// gold. It fails with ErrNotEnoughGold if they cannot pay or have left the inn.
+func (r *UserRepository) InnHealWithExt(h ExtHandle, userID, innID uuid.UUID, price uint) (uint, error) {
+	query := `
+		UPDATE users
+		SET gold = gold - $1, current_hp = hp, hp_changed_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND location_id = $3 AND gold >= $1 AND deleted_at IS NULL
+		RETURNING current_hp
+	`
+
+	var currentHp uint
+	err := h.QueryRow(query, price, userID, innID).Scan(&currentHp)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrNotEnoughGold
+		}
+		return 0, err
+	}
+
+	return currentHp, nil
+}
+
+// DefeatWithExt moves a beaten user to the respawn location, takes the
+// penalties and starts the weakened debuff. It returns when the debuff ends.
+func (r *UserRepository) DefeatWithExt(h ExtHandle, userID, locationID uuid.UUID, lostGold, lostExp uint,
+	weakenedFor time.Duration) (*time.Time, error) {
+	query := `
+		UPDATE users
+		SET location_id = $1,
+		    current_hp = ` + currentHpColumn + `,
+		    hp_changed_at = CURRENT_TIMESTAMP,
+		    hp_regen_rate = (SELECT regen_multiplier FROM locations WHERE id = $1),
+		    gold = GREATEST(gold - $2, 0),
+		    exp = GREATEST(exp - $3, 0),
+		    weakened_until = CASE WHEN $4::float8 > 0 THEN CURRENT_TIMESTAMP + $4::float8 * INTERVAL '1 second' END
 		WHERE id = $5 AND deleted_at IS NULL
+		RETURNING weakened_until
 	`
-	_, err := h.Exec(query, addedGold, addedExp, newLevel, newCurrentHp, userID)
+
+	var weakenedUntil *time.Time
+	err := h.QueryRow(query, locationID, lostGold, lostExp, weakenedFor.Seconds(), userID).Scan(&weakenedUntil)
+	if err != nil {
+		return nil, err
+	}
+
+	return weakenedUntil, nil
+}
+
+// AddBuffWithExt starts the item's buff on the user; it runs for the item's
+// BuffSeconds from now, measured by the database clock.
+func (r *UserRepository) AddBuffWithExt(h ExtHandle, userID uuid.UUID, item *domain.ConsumableItem) (*domain.UserBuff, error) {
+	query := `
+		INSERT INTO user_buffs (user_id, consumable_item_id, attack, defense, crit_chance, dodge_chance, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP + $7 * INTERVAL '1 second')
+		RETURNING id, created_at, deleted_at, user_id, consumable_item_id, attack, defense, crit_chance, dodge_chance, expires_at
+	`
+
+	buff := &domain.UserBuff{}
+	err := h.Get(buff, query, userID, item.ID, item.BuffAttack, item.BuffDefense,
+		item.BuffCritChance, item.BuffDodgeChance, item.BuffSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	return buff, nil
+}
+
+// FindActiveBuffs returns the user's buffs that have not run out yet, the
+// soonest to expire first.
+func (r *UserRepository) FindActiveBuffs(userID uuid.UUID) ([]*domain.UserBuff, error) {
+	query := `
+		SELECT id, created_at, deleted_at, user_id, consumable_item_id, attack, defense, crit_chance, dodge_chance, expires_at
+		FROM user_buffs
+		WHERE user_id = $1 AND expires_at > CURRENT_TIMESTAMP AND deleted_at IS NULL
+		ORDER BY expires_at
+	`
+
+	buffs := []*domain.UserBuff{}
+	if err := r.db.Select(&buffs, query, userID); err != nil {
+		return nil, err
+	}
+
+	return buffs, nil
+}
+
+func (r *UserRepository) SpendStatsWithExt(h ExtHandle, userID uuid.UUID, attack, defense, hp uint) error {
+	query := `
+		UPDATE users
+		SET free_stats = free_stats - $1,
+		    attack = attack + $2,
+		    defense = defense + $3,
+		    hp = hp + $4 * $5,
+		    current_hp = ` + currentHpColumn + ` + $4 * $5,
+		    hp_changed_at = CURRENT_TIMESTAMP,
+		    allocated_attack = allocated_attack + $2,
+		    allocated_defense = allocated_defense + $3,
+		    allocated_hp = allocated_hp + $4
+		WHERE id = $6 AND free_stats >= $1 AND deleted_at IS NULL
+	`
+	res, err := h.Exec(query, attack+defense+hp, attack, defense, hp, domain.HpPerStatPoint, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotEnoughFreeStats
+	}
+
+	return nil
+}
+
+func (r *UserRepository) ResetStatsWithExt(h ExtHandle, userID uuid.UUID, price uint) error {
+	query := `
+		UPDATE users
+		SET free_stats = free_stats + allocated_attack + allocated_defense + allocated_hp,
+		    attack = attack - allocated_attack,
+		    defense = defense - allocated_defense,
+		    hp = hp - allocated_hp * $1,
+		    current_hp = LEAST(` + currentHpColumn + `, hp - allocated_hp * $1),
+		    hp_changed_at = CURRENT_TIMESTAMP,
+		    allocated_attack = 0,
+		    allocated_defense = 0,
+		    allocated_hp = 0,
+		    gold = gold - $2
+		WHERE id = $3 AND gold >= $2 AND deleted_at IS NULL
+	`
+	res, err := h.Exec(query, domain.HpPerStatPoint, price, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotEnoughGold
+	}
+
+	return nil
+}
+
+// LockWithExt locks the user's row until the transaction ends. Everything that
+// starts a fight takes it before checking InFightWithExt, so two concurrent
+// starts cannot both see the user as free.
+func (r *UserRepository) LockWithExt(h ExtHandle, userID uuid.UUID) error {
+	var id uuid.UUID
+	err := h.Get(&id, `SELECT id FROM users WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
 	return err
 }
 
+// InFight reports whether the user is busy with a bot fight or a running duel.
 func (r *UserRepository) InFight(userID uuid.UUID) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM fights WHERE user_id = $1 AND status = $2 AND deleted_at IS NULL)`
+	return r.InFightWithExt(r.db, userID)
+}
+
+func (r *UserRepository) InFightWithExt(h ExtHandle, userID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS(SELECT 1 FROM fights WHERE user_id = $1 AND status = $2 AND deleted_at IS NULL)
+		    OR EXISTS(
+		        SELECT 1 FROM duels
+		        WHERE (challenger_id = $1 OR opponent_id = $1) AND status = $3 AND deleted_at IS NULL
+		    )
+		    OR EXISTS(
+		        SELECT 1 FROM group_fights gf
+		        INNER JOIN group_fight_members gfm ON gfm.group_fight_id = gf.id
+		        WHERE gfm.user_id = $1 AND gfm.hp > 0 AND gf.status = $4 AND gf.deleted_at IS NULL
+		    )
+	`
 
 	exists := false
-	err := r.db.Get(&exists, query, userID, domain.FightStatusInProgress)
+	err := h.Get(&exists, query, userID, domain.FightStatusInProgress, domain.DuelStatusInProgress,
+		domain.GroupFightStatusInProgress)
 
 	return exists, err
 }
@@ -199,9 +518,9 @@ func (r *UserRepository) GetHPForUsers(userIDs []uuid.UUID) ([]HPUpdate, error)
 	}
 	
 	query, args, err := sqlx.In(`
-		SELECT id, current_hp, hp 
-		FROM users 
-		WHERE id IN (?) AND deleted_at IS NULL
+		SELECT users.id, ` + currentHpColumn + ` AS current_hp, users.hp
+		FROM users
+		WHERE users.id IN (?) AND users.deleted_at IS NULL
 	`, userIDs)
 	if err != nil {
 		return nil, err
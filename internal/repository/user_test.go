@@ -129,15 +129,13 @@ func TestUserRepository_FindByID_NotFound(t *testing.T) {
 	assert.ErrorIs(t, err, ErrUserNotFound)
 }
 
-func TestUserRepository_RegenerateAllUsersHealth(t *testing.T) {
+func TestUserRepository_RegeneratedHp(t *testing.T) {
 	if testDB == nil {
 		t.Skip("Test database not initialized")
 	}
 
 	repo := NewUserRepository(testDB.DB())
 	locationRepo := NewLocationRepository(testDB.DB())
-	botRepo := NewBotRepository(testDB.DB())
-	fightRepo := NewFightRepository(testDB.DB())
 	ts := time.Now().UnixNano()
 
 	location := &domain.Location{
@@ -149,18 +147,6 @@ func TestUserRepository_RegenerateAllUsersHealth(t *testing.T) {
 	err := locationRepo.Create(location)
 	require.NoError(t, err)
 
-	bot := &domain.Bot{
-		Name:    "Test Bot",
-		Slug:    fmt.Sprintf("test-bot-%d", ts),
-		Attack:  5,
-		Defense: 3,
-		Hp:      20,
-		Level:   1,
-		Avatar:  "images/bots/test",
-	}
-	err = botRepo.Create(bot)
-	require.NoError(t, err)
-
 	userInFight := &domain.User{
 		Username:   fmt.Sprintf("infight%d", ts),
 		Email:      fmt.Sprintf("infight%d@example.com", ts),
@@ -185,25 +171,71 @@ func TestUserRepository_RegenerateAllUsersHealth(t *testing.T) {
 	err = repo.Create(userNotInFight)
 	require.NoError(t, err)
 
-	fight := &domain.Fight{
-		UserID: userInFight.ID,
-		BotID:  bot.ID,
-		Status: domain.FightStatusInProgress,
-	}
-	_, err = fightRepo.Create(fight)
+	frozenHp, err := repo.FreezeHpWithExt(testDB.DB(), userInFight.ID)
 	require.NoError(t, err)
+	assert.Equal(t, uint(50), frozenHp)
 
-	initialHpInFight := userInFight.CurrentHp
-	initialHpNotInFight := userNotInFight.CurrentHp
-
-	_, err = repo.RegenerateAllUsersHealth(10.0)
+	// Ten ticks of 3 seconds at 1% of 100 HP each.
+	_, err = testDB.DB().Exec(
+		`UPDATE users SET hp_changed_at = hp_changed_at - INTERVAL '30 seconds' WHERE id IN ($1, $2)`,
+		userInFight.ID, userNotInFight.ID,
+	)
 	require.NoError(t, err)
 
 	userInFightAfter, err := repo.FindByID(userInFight.ID)
 	require.NoError(t, err)
-	assert.Equal(t, initialHpInFight, userInFightAfter.CurrentHp, "HP should not regenerate for user in fight")
+	assert.Equal(t, uint(50), userInFightAfter.CurrentHp, "HP should not regenerate for user in fight")
 
 	userNotInFightAfter, err := repo.FindByID(userNotInFight.ID)
 	require.NoError(t, err)
-	assert.Greater(t, userNotInFightAfter.CurrentHp, initialHpNotInFight, "HP should regenerate for user not in fight")
+	assert.Equal(t, uint(60), userNotInFightAfter.CurrentHp, "HP should regenerate for user not in fight")
+
+	updates, err := repo.GetHPForUsers([]uuid.UUID{userNotInFight.ID})
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	assert.Equal(t, uint(60), updates[0].CurrentHp)
+
+	err = repo.UpdateCurrentHpWithExt(testDB.DB(), userInFight.ID, 40)
+	require.NoError(t, err)
+	userInFightAfter, err = repo.FindByID(userInFight.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uint(40), userInFightAfter.CurrentHp, "HP should start over from the fight result")
+}
+
+func TestUserRepository_RegeneratedHp_LongIdleDoesNotOverflow(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Test database not initialized")
+	}
+
+	repo := NewUserRepository(testDB.DB())
+	locationRepo := NewLocationRepository(testDB.DB())
+	ts := time.Now().UnixNano()
+
+	location := &domain.Location{
+		Name: fmt.Sprintf("Test Location %d", ts),
+		Slug: fmt.Sprintf("test-location-%d", ts),
+	}
+	require.NoError(t, locationRepo.Create(location))
+
+	user := &domain.User{
+		Username:   fmt.Sprintf("idle%d", ts),
+		Email:      fmt.Sprintf("idle%d@example.com", ts),
+		Password:   "hashedpassword",
+		LocationID: location.ID,
+		Hp:         100,
+		CurrentHp:  1,
+		Level:      1,
+	}
+	require.NoError(t, repo.Create(user))
+
+	// About a billion ticks of 100 HP each is far past INTEGER.
+	_, err := testDB.DB().Exec(
+		`UPDATE users SET hp_changed_at = hp_changed_at - INTERVAL '100 years', hp_regen_rate = 100 WHERE id = $1`,
+		user.ID,
+	)
+	require.NoError(t, err)
+
+	found, err := repo.FindByID(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uint(100), found.CurrentHp)
 }
@@ -2,72 +2,202 @@ package worker
 
 import (
 	"context"
-	"sync"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 
+	"moonshine/internal/api/ws"
+	"moonshine/internal/domain"
 	"moonshine/internal/repository"
 )
 
+// cellsMovingPollInterval is how often the worker looks for due steps.
+const cellsMovingPollInterval = time.Second
+
+// movementRetryDelay is how long a step that failed waits before it is tried
+// again, so one broken plan does not hold up the others.
+const movementRetryDelay = 30 * time.Second
+
+// CellsMovingWorker walks users along cell paths. Plans live in the movements
+// table, so walks survive restarts and are shared between instances.
 type CellsMovingWorker struct {
+	db           *sqlx.DB
 	locationRepo *repository.LocationRepository
 	userRepo     *repository.UserRepository
+	movementRepo *repository.MovementRepository
+	publisher    ws.Publisher
 	interval     time.Duration
-	mu           sync.Mutex
-	activeUsers  map[uuid.UUID]context.CancelFunc
 }
 
 func NewCellsMovingWorker(
+	db *sqlx.DB,
 	locationRepo *repository.LocationRepository,
 	userRepo *repository.UserRepository,
 	interval time.Duration,
 ) *CellsMovingWorker {
 	return &CellsMovingWorker{
+		db:           db,
 		locationRepo: locationRepo,
 		userRepo:     userRepo,
+		movementRepo: repository.NewMovementRepository(db),
+		publisher:    ws.GetHub(),
 		interval:     interval,
-		activeUsers:  make(map[uuid.UUID]context.CancelFunc),
 	}
 }
 
-func (w *CellsMovingWorker) StartMovement(userID uuid.UUID, cellSlugs []string) error {
-	w.mu.Lock()
-	if cancel, exists := w.activeUsers[userID]; exists {
-		cancel()
-	}
-
-	ctx, cancel := context.WithCancel(context.Background())
-	w.activeUsers[userID] = cancel
-	w.mu.Unlock()
-
-	go func() {
-		defer func() {
-			w.mu.Lock()
-			delete(w.activeUsers, userID)
-			w.mu.Unlock()
-		}()
-
-		ticker := time.NewTicker(w.interval)
-		defer ticker.Stop()
-
-		for _, cellSlug := range cellSlugs {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				location, _ := w.locationRepo.FindBySlug(cellSlug)
-				if location == nil {
-					continue
-				}
-
-				err := w.userRepo.UpdateLocationID(userID, location.ID)
-				if err != nil {
-					return
-				}
-			}
+// StartMovement saves a new plan for the user, replacing any walk in progress.
+func (w *CellsMovingWorker) StartMovement(userID uuid.UUID, cellSlugs []string) (*domain.Movement, error) {
+	movement := &domain.Movement{
+		UserID:      userID,
+		Path:        cellSlugs,
+		StepSeconds: int(w.interval / time.Second),
+	}
+	if err := w.movementRepo.Upsert(movement); err != nil {
+		return nil, err
+	}
+
+	return movement, nil
+}
+
+func (w *CellsMovingWorker) StartWorker(ctx context.Context) {
+	ticker := time.NewTicker(cellsMovingPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.processDueSteps(ctx)
+		}
+	}
+}
+
+func (w *CellsMovingWorker) processDueSteps(ctx context.Context) {
+	for ctx.Err() == nil {
+		done, err := w.step(ctx)
+		if err != nil {
+			fmt.Printf("[CellsMovingWorker] Error making step: %v\n", err)
+			return
+		}
+		if done {
+			return
+		}
+	}
+}
+
+// step moves one user whose next step is due. It reports done when nothing is
+// due. A step that fails is postponed, so the next due plan gets its turn.
+func (w *CellsMovingWorker) step(ctx context.Context) (bool, error) {
+	tx, err := w.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	movement, err := repository.NewMovementRepository(tx).FindNextDueForUpdate()
+	if err != nil {
+		if errors.Is(err, repository.ErrMovementNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	location, err := w.walk(tx, movement)
+	if err != nil {
+		tx.Rollback()
+		fmt.Printf("[CellsMovingWorker] Error moving %s to %s, retrying in %s: %v\n",
+			movement.UserID, movement.NextCell(), movementRetryDelay, err)
+		return false, w.movementRepo.Postpone(movement.ID, movementRetryDelay)
+	}
+
+	if location != nil {
+		w.sendStep(movement, location)
+	}
+
+	return false, nil
+}
+
+// walk makes the movement's next step and commits tx. It returns the cell the
+// user reached, or nil when the plan was dropped instead.
+func (w *CellsMovingWorker) walk(tx *sqlx.Tx, movement *domain.Movement) (*domain.Location, error) {
+	movementRepoTx := repository.NewMovementRepository(tx)
+
+	// Fights cancel walks when they start; a plan that slipped through is
+	// dropped rather than dragging a fighting user across the map.
+	inFight, err := w.userRepo.InFight(movement.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if inFight {
+		if err := movementRepoTx.Delete(movement.ID); err != nil {
+			return nil, err
+		}
+		return nil, tx.Commit()
+	}
+
+	// A cell that no longer exists cannot be walked through.
+	location, err := w.locationRepo.FindBySlug(movement.NextCell())
+	if errors.Is(err, repository.ErrLocationNotFound) {
+		fmt.Printf("[CellsMovingWorker] Cell %s is gone, dropping the walk of %s\n", movement.NextCell(), movement.UserID)
+		if err := movementRepoTx.Delete(movement.ID); err != nil {
+			return nil, err
+		}
+		return nil, tx.Commit()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.moveUser(tx, movement.UserID, location); err != nil {
+		return nil, err
+	}
+
+	if movement.IsLastStep() {
+		err = movementRepoTx.Delete(movement.ID)
+	} else {
+		err = movementRepoTx.Advance(movement.ID, movement.StepIndex+1, movement.StepSeconds)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return location, tx.Commit()
+}
+
+func (w *CellsMovingWorker) sendStep(movement *domain.Movement, location *domain.Location) {
+	data := ws.MovementStepData{
+		Cell:          location.Slug,
+		RemainingPath: movement.RemainingPath()[1:],
+		Arrived:       movement.IsLastStep(),
+	}
+	if !data.Arrived {
+		nextStepAt := time.Now().Add(movement.StepInterval())
+		data.NextStepAt = &nextStepAt
+	}
+
+	w.publisher.Publish(movement.UserID, ws.MovementStepEvent(data))
+}
+
+func (w *CellsMovingWorker) moveUser(tx *sqlx.Tx, userID uuid.UUID, location *domain.Location) error {
+	var fromCell *string
+	if user, err := w.userRepo.FindByID(userID); err == nil {
+		if current, err := w.locationRepo.FindByID(user.LocationID); err == nil {
+			fromCell = &current.Slug
 		}
-	}()
+	}
+
+	if err := w.userRepo.UpdateLocationIDWithExt(tx, userID, location.ID); err != nil {
+		return err
+	}
 
-	return nil
+	movementLogRepo := repository.NewMovementLogRepository(tx)
+	return movementLogRepo.Create(&domain.MovementLog{
+		UserID:   userID,
+		FromCell: fromCell,
+		ToCell:   location.Slug,
+	})
 }
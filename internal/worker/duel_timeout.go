@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/api/services"
+)
+
+type DuelTimeoutWorker struct {
+	duelService *services.DuelService
+}
+
+func NewDuelTimeoutWorker(db *sqlx.DB) *DuelTimeoutWorker {
+	return &DuelTimeoutWorker{
+		duelService: services.NewDuelService(db),
+	}
+}
+
+func (w *DuelTimeoutWorker) Job(interval time.Duration) Job {
+	return Job{
+		Name:     "duel_timeout",
+		Interval: interval,
+		Run:      w.resolveExpired,
+	}
+}
+
+func (w *DuelTimeoutWorker) resolveExpired(ctx context.Context) error {
+	count, err := w.duelService.ResolveExpiredRounds(ctx)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		fmt.Printf("[DuelTimeoutWorker] Resolved %d expired duels\n", count)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/api/services"
+)
+
+// FightTimeoutWorker keeps abandoned fights from staying IN_PROGRESS forever,
+// which would block HP regeneration and movement for the player.
+type FightTimeoutWorker struct {
+	fightService    *services.FightService
+	roundTimeout    time.Duration
+	maxMissedRounds int
+}
+
+func NewFightTimeoutWorker(db *sqlx.DB, roundTimeout time.Duration, maxMissedRounds int) *FightTimeoutWorker {
+	return &FightTimeoutWorker{
+		fightService:    services.NewFightService(db),
+		roundTimeout:    roundTimeout,
+		maxMissedRounds: maxMissedRounds,
+	}
+}
+
+func (w *FightTimeoutWorker) Job(interval time.Duration) Job {
+	return Job{
+		Name:     "fight_timeout",
+		Interval: interval,
+		Run:      w.resolveIdle,
+	}
+}
+
+func (w *FightTimeoutWorker) resolveIdle(ctx context.Context) error {
+	count, err := w.fightService.ResolveIdleRounds(ctx, w.roundTimeout, w.maxMissedRounds)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		fmt.Printf("[FightTimeoutWorker] Resolved %d idle fights\n", count)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/api/services"
+)
+
+type GroupFightTimeoutWorker struct {
+	groupFightService *services.GroupFightService
+}
+
+func NewGroupFightTimeoutWorker(db *sqlx.DB) *GroupFightTimeoutWorker {
+	return &GroupFightTimeoutWorker{
+		groupFightService: services.NewGroupFightService(db),
+	}
+}
+
+func (w *GroupFightTimeoutWorker) Job(interval time.Duration) Job {
+	return Job{
+		Name:     "group_fight_timeout",
+		Interval: interval,
+		Run:      w.resolveExpired,
+	}
+}
+
+func (w *GroupFightTimeoutWorker) resolveExpired(ctx context.Context) error {
+	count, err := w.groupFightService.ResolveExpiredRounds(ctx)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		fmt.Printf("[GroupFightTimeoutWorker] Resolved %d expired group fights\n", count)
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 
 	"moonshine/internal/api/services"
@@ -12,64 +13,62 @@ import (
 	"moonshine/internal/repository"
 )
 
+// HpWorker pushes hp_update to the users connected to this instance whenever
+// their regenerated HP changes. HP itself is computed on read, so every
+// instance runs its own worker and offline players cost nothing.
 type HpWorker struct {
 	healthRegenerationService *services.HealthRegenerationService
-	userRepo                  *repository.UserRepository
 	hub                       *ws.Hub
-	ticker                    *time.Ticker
+	sent                      map[uuid.UUID]repository.HPUpdate
 }
 
-func NewHpWorker(db *sqlx.DB, interval time.Duration) *HpWorker {
+func NewHpWorker(db *sqlx.DB) *HpWorker {
 	userRepo := repository.NewUserRepository(db)
 	healthRegenerationService := services.NewHealthRegenerationService(userRepo)
 
 	return &HpWorker{
 		healthRegenerationService: healthRegenerationService,
-		userRepo:                  userRepo,
 		hub:                       ws.GetHub(),
-		ticker:                    time.NewTicker(interval),
+		sent:                      make(map[uuid.UUID]repository.HPUpdate),
 	}
 }
 
-func (w *HpWorker) StartWorker(ctx context.Context) {
-	defer w.ticker.Stop()
+func (w *HpWorker) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-w.ticker.C:
-			w.regenerateHp()
+		case <-ticker.C:
+			if err := w.pushHp(); err != nil {
+				fmt.Printf("[HpWorker] Error pushing HP: %v\n", err)
+			}
 		}
 	}
 }
 
-func (w *HpWorker) regenerateHp() {
-	regeneratedCount, err := w.healthRegenerationService.RegenerateAllUsers(1.0)
-	if err != nil {
-		fmt.Printf("[HpWorker] Error regenerating: %v\n", err)
-		return
-	}
+// pushHp sends each connected user their HP if it differs from what they were
+// last sent. Users who have disconnected are forgotten, so they get a fresh
+// update when they come back.
+func (w *HpWorker) pushHp() error {
+	userIDs := w.hub.GetConnectedUserIDs()
 
-	connectedUserIDs := w.hub.GetConnectedUserIDs()
-	fmt.Printf("[HpWorker] Regenerated %d users, %d connected\n", regeneratedCount, len(connectedUserIDs))
-	
-	if len(connectedUserIDs) == 0 {
-		return
-	}
-
-	updates, err := w.userRepo.GetHPForUsers(connectedUserIDs)
+	updates, err := w.healthRegenerationService.CurrentHP(userIDs)
 	if err != nil {
-		fmt.Printf("[HpWorker] Error getting HP: %v\n", err)
-		return
+		return err
 	}
 
+	sent := make(map[uuid.UUID]repository.HPUpdate, len(updates))
 	for _, update := range updates {
-		err := w.hub.SendHPUpdate(update.UserID, update.CurrentHp, update.Hp)
-		if err != nil {
-			fmt.Printf("[HpWorker] Error sending HP update to %s: %v\n", update.UserID, err)
-		} else {
-			fmt.Printf("[HpWorker] Sent HP update to %s: %d/%d\n", update.UserID, update.CurrentHp, update.Hp)
+		sent[update.UserID] = update
+		if last, ok := w.sent[update.UserID]; ok && last == update {
+			continue
 		}
+		w.hub.PublishLocal(update.UserID, ws.HPUpdateEvent(update.CurrentHp, update.Hp))
 	}
+	w.sent = sent
+
+	return nil
 }
@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"moonshine/internal/domain"
+)
+
+// ProgressionReloader re-reads the progression file on SIGHUP, so the level
+// curve can change without a redeploy. An invalid file is logged and the
+// previous curve stays active.
+type ProgressionReloader struct {
+	path string
+}
+
+func NewProgressionReloader(path string) *ProgressionReloader {
+	return &ProgressionReloader{path: path}
+}
+
+func (r *ProgressionReloader) Start(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			r.reload()
+		}
+	}
+}
+
+func (r *ProgressionReloader) reload() {
+	progression, err := domain.LoadProgression(r.path)
+	if err == nil {
+		err = domain.SetProgression(progression)
+	}
+	if err != nil {
+		fmt.Printf("[ProgressionReloader] Keeping current progression: %v\n", err)
+		return
+	}
+
+	fmt.Printf("[ProgressionReloader] Loaded progression version %d with max level %d\n",
+		progression.Version, progression.MaxLevel)
+}
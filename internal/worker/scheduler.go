@@ -0,0 +1,154 @@
+package worker
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"moonshine/internal/domain"
+	"moonshine/internal/repository"
+)
+
+// Job is a periodic task that must run on a single instance at a time.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs jobs on whichever instance holds the job's Postgres advisory
+// lock. The lock belongs to a dedicated connection, so when the leader dies
+// the lock is released and another instance takes over on its next tick.
+type Scheduler struct {
+	db         *sqlx.DB
+	jobRunRepo *repository.JobRunRepository
+	instanceID string
+	jobs       []Job
+}
+
+func NewScheduler(db *sqlx.DB) *Scheduler {
+	hostname, _ := os.Hostname()
+
+	return &Scheduler{
+		db:         db,
+		jobRunRepo: repository.NewJobRunRepository(db),
+		instanceID: fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}
+}
+
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start runs all registered jobs until ctx is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			s.runJob(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+}
+
+func (s *Scheduler) runJob(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	var lock *sqlx.Conn
+	defer func() {
+		if lock != nil {
+			s.release(lock, job.Name)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if lock == nil {
+				lock = s.tryAcquire(ctx, job.Name)
+				if lock == nil {
+					continue
+				}
+				fmt.Printf("[Scheduler] %s is now running %s\n", s.instanceID, job.Name)
+			} else if err := lock.PingContext(ctx); err != nil {
+				fmt.Printf("[Scheduler] Lost lock for %s: %v\n", job.Name, err)
+				discard(lock)
+				lock = nil
+				continue
+			}
+
+			s.execute(ctx, job)
+		}
+	}
+}
+
+func (s *Scheduler) tryAcquire(ctx context.Context, name string) *sqlx.Conn {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		fmt.Printf("[Scheduler] Error getting connection for %s: %v\n", name, err)
+		return nil
+	}
+
+	acquired := false
+	if err := conn.GetContext(ctx, &acquired, `SELECT pg_try_advisory_lock(hashtext($1))`, name); err != nil {
+		fmt.Printf("[Scheduler] Error locking %s: %v\n", name, err)
+		discard(conn)
+		return nil
+	}
+
+	if !acquired {
+		conn.Close()
+		return nil
+	}
+
+	return conn
+}
+
+// release unlocks before the connection goes back to the pool, since a
+// session lock would otherwise outlive the job.
+func (s *Scheduler) release(conn *sqlx.Conn, name string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock(hashtext($1))`, name); err != nil {
+		discard(conn)
+		return
+	}
+	conn.Close()
+}
+
+func (s *Scheduler) execute(ctx context.Context, job Job) {
+	started := time.Now()
+	err := job.Run(ctx)
+
+	run := &domain.JobRun{
+		JobName:    job.Name,
+		InstanceID: s.instanceID,
+		DurationMs: time.Since(started).Milliseconds(),
+	}
+	if err != nil {
+		msg := err.Error()
+		run.Error = &msg
+		fmt.Printf("[Scheduler] %s failed: %v\n", job.Name, err)
+	}
+
+	if err := s.jobRunRepo.Create(run); err != nil {
+		fmt.Printf("[Scheduler] Error recording run of %s: %v\n", job.Name, err)
+	}
+}
+
+// discard closes the underlying connection instead of returning it to the pool.
+func discard(conn *sqlx.Conn) {
+	conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+	conn.Close()
+}
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN allocated_attack INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN allocated_defense INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN allocated_hp INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS allocated_attack;
+ALTER TABLE users DROP COLUMN IF EXISTS allocated_defense;
+ALTER TABLE users DROP COLUMN IF EXISTS allocated_hp;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE bot_loot (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    bot_id UUID NOT NULL,
+    equipment_item_id UUID NOT NULL,
+    weight INTEGER NOT NULL DEFAULT 1,
+    min_level INTEGER NOT NULL DEFAULT 1,
+    max_level INTEGER,
+    CONSTRAINT fk_bot_loot_bot FOREIGN KEY (bot_id) REFERENCES bots(id) ON DELETE CASCADE,
+    CONSTRAINT fk_bot_loot_item FOREIGN KEY (equipment_item_id) REFERENCES equipment_items(id) ON DELETE CASCADE,
+    CONSTRAINT chk_bot_loot_weight CHECK (weight > 0)
+);
+
+CREATE INDEX idx_bot_loot_bot_id ON bot_loot(bot_id) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS bot_loot;
+-- +goose StatementEnd
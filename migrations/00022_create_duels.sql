@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE duel_status AS ENUM ('PENDING', 'IN_PROGRESS', 'FINISHED', 'DECLINED');
+
+CREATE TABLE duels (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    challenger_id UUID NOT NULL,
+    opponent_id UUID NOT NULL,
+    location_id UUID NOT NULL,
+    status duel_status NOT NULL DEFAULT 'PENDING',
+    winner_id UUID,
+    CONSTRAINT fk_duels_challenger FOREIGN KEY (challenger_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_duels_opponent FOREIGN KEY (opponent_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_duels_location FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_duels_winner FOREIGN KEY (winner_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_duels_challenger_id ON duels(challenger_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_duels_opponent_id ON duels(opponent_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE duel_rounds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    duel_id UUID NOT NULL,
+    status round_status NOT NULL DEFAULT 'IN_PROGRESS',
+    deadline_at TIMESTAMP NOT NULL,
+    challenger_hp INTEGER NOT NULL DEFAULT 0,
+    opponent_hp INTEGER NOT NULL DEFAULT 0,
+    challenger_damage INTEGER NOT NULL DEFAULT 0,
+    opponent_damage INTEGER NOT NULL DEFAULT 0,
+    challenger_attack_point body_part,
+    challenger_defense_point body_part,
+    opponent_attack_point body_part,
+    opponent_defense_point body_part,
+    CONSTRAINT fk_duel_rounds_duel FOREIGN KEY (duel_id) REFERENCES duels(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_duel_rounds_duel_id ON duel_rounds(duel_id);
+CREATE INDEX idx_duel_rounds_deadline ON duel_rounds(deadline_at) WHERE status = 'IN_PROGRESS';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS duel_rounds;
+DROP TABLE IF EXISTS duels;
+DROP TYPE IF EXISTS duel_status;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE movements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    user_id UUID NOT NULL,
+    path TEXT[] NOT NULL,
+    step_index INTEGER NOT NULL DEFAULT 0,
+    step_seconds INTEGER NOT NULL,
+    next_step_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_movements_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT uq_movements_user UNIQUE (user_id)
+);
+
+CREATE INDEX idx_movements_next_step_at ON movements(next_step_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS movements;
+-- +goose StatementEnd
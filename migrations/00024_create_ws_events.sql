@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every event is stored with its recipient, so listeners can replay what
+-- they missed while reconnecting. Replays read id ranges, which the primary
+-- key already serves; created_at is for the retention cleanup.
+CREATE TABLE ws_events (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id UUID NOT NULL,
+    payload TEXT NOT NULL
+);
+
+CREATE INDEX idx_ws_events_created_at ON ws_events(created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS ws_events;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE bot_strategy AS ENUM ('RANDOM', 'FAVOURS_HEAD', 'MIRROR', 'WEIGHTED');
+
+ALTER TABLE bots ADD COLUMN strategy bot_strategy NOT NULL DEFAULT 'RANDOM';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE bots DROP COLUMN IF EXISTS strategy;
+
+DROP TYPE IF EXISTS bot_strategy;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+DO $$ BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_enum WHERE enumlabel = 'FLED' AND enumtypid = (SELECT oid FROM pg_type WHERE typname = 'fight_status')) THEN
+        ALTER TYPE fight_status ADD VALUE 'FLED';
+    END IF;
+END $$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Cannot remove enum value in PostgreSQL, so we recreate the type
+UPDATE fights SET status = 'FINISHED' WHERE status = 'FLED';
+
+CREATE TYPE fight_status_new AS ENUM ('IN_PROGRESS', 'FINISHED');
+
+ALTER TABLE fights ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE fights
+  ALTER COLUMN status TYPE fight_status_new USING status::text::fight_status_new;
+
+DROP TYPE fight_status;
+ALTER TYPE fight_status_new RENAME TO fight_status;
+
+ALTER TABLE fights ALTER COLUMN status SET DEFAULT 'IN_PROGRESS'::fight_status;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE rounds ADD COLUMN timed_out BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX idx_rounds_in_progress_created_at ON rounds(created_at) WHERE status = 'IN_PROGRESS';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_rounds_in_progress_created_at;
+
+ALTER TABLE rounds DROP COLUMN IF EXISTS timed_out;
+-- +goose StatementEnd
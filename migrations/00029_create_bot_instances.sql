@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE location_bots
+    ADD COLUMN max_population INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN respawn_seconds INTEGER NOT NULL DEFAULT 60;
+
+CREATE TYPE bot_instance_state AS ENUM ('ALIVE', 'IN_FIGHT', 'DEAD');
+
+CREATE TABLE bot_instances (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    location_bot_id UUID NOT NULL,
+    location_id UUID NOT NULL,
+    bot_id UUID NOT NULL,
+    slot INTEGER NOT NULL,
+    state bot_instance_state NOT NULL DEFAULT 'ALIVE',
+    current_hp INTEGER NOT NULL,
+    fight_id UUID,
+    respawn_at TIMESTAMP,
+    CONSTRAINT fk_bot_instances_location_bot FOREIGN KEY (location_bot_id) REFERENCES location_bots(id) ON DELETE CASCADE,
+    CONSTRAINT fk_bot_instances_location FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_bot_instances_bot FOREIGN KEY (bot_id) REFERENCES bots(id) ON DELETE CASCADE,
+    CONSTRAINT fk_bot_instances_fight FOREIGN KEY (fight_id) REFERENCES fights(id) ON DELETE SET NULL,
+    CONSTRAINT uq_bot_instances_slot UNIQUE (location_bot_id, slot)
+);
+
+CREATE INDEX idx_bot_instances_location_id ON bot_instances(location_id);
+CREATE INDEX idx_bot_instances_fight_id ON bot_instances(fight_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS bot_instances;
+
+DROP TYPE IF EXISTS bot_instance_state;
+
+ALTER TABLE location_bots
+    DROP COLUMN IF EXISTS respawn_seconds,
+    DROP COLUMN IF EXISTS max_population;
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE bots
+    ADD COLUMN is_boss BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN kill_cooldown_seconds INTEGER NOT NULL DEFAULT 0;
+
+CREATE TYPE bot_ability AS ENUM ('DOUBLE_STRIKE', 'HEAL', 'UNBLOCKABLE_HEAD');
+
+CREATE TABLE bot_phases (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    bot_id UUID NOT NULL,
+    hp_percent INTEGER NOT NULL,
+    attack INTEGER,
+    defense INTEGER,
+    strategy bot_strategy,
+    ability bot_ability,
+    ability_chance INTEGER NOT NULL DEFAULT 0,
+    ability_power INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT fk_bot_phases_bot FOREIGN KEY (bot_id) REFERENCES bots(id) ON DELETE CASCADE,
+    CONSTRAINT chk_bot_phases_hp_percent CHECK (hp_percent > 0 AND hp_percent <= 100),
+    CONSTRAINT chk_bot_phases_ability_chance CHECK (ability_chance >= 0 AND ability_chance <= 100)
+);
+
+CREATE UNIQUE INDEX idx_bot_phases_bot_id_hp_percent ON bot_phases(bot_id, hp_percent) WHERE deleted_at IS NULL;
+
+CREATE TABLE boss_kills (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    user_id UUID NOT NULL,
+    bot_id UUID NOT NULL,
+    killed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_boss_kills_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_boss_kills_bot FOREIGN KEY (bot_id) REFERENCES bots(id) ON DELETE CASCADE,
+    CONSTRAINT uq_boss_kills_user_bot UNIQUE (user_id, bot_id)
+);
+
+ALTER TABLE rounds ADD COLUMN bot_ability bot_ability;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE rounds DROP COLUMN IF EXISTS bot_ability;
+
+DROP TABLE IF EXISTS boss_kills;
+
+DROP TABLE IF EXISTS bot_phases;
+
+DROP TYPE IF EXISTS bot_ability;
+
+ALTER TABLE bots
+    DROP COLUMN IF EXISTS kill_cooldown_seconds,
+    DROP COLUMN IF EXISTS is_boss;
+-- +goose StatementEnd
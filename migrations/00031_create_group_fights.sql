@@ -0,0 +1,86 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE group_fight_status AS ENUM ('IN_PROGRESS', 'WON', 'LOST');
+
+CREATE TABLE group_fights (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    leader_id UUID NOT NULL,
+    bot_id UUID NOT NULL,
+    location_id UUID NOT NULL,
+    status group_fight_status NOT NULL DEFAULT 'IN_PROGRESS',
+    max_members INTEGER NOT NULL,
+    dropped_item_id UUID,
+    CONSTRAINT fk_group_fights_leader FOREIGN KEY (leader_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_group_fights_bot FOREIGN KEY (bot_id) REFERENCES bots(id) ON DELETE CASCADE,
+    CONSTRAINT fk_group_fights_location FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE CASCADE,
+    CONSTRAINT fk_group_fights_dropped_item FOREIGN KEY (dropped_item_id) REFERENCES equipment_items(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_group_fights_location_id ON group_fights(location_id) WHERE status = 'IN_PROGRESS';
+
+CREATE TABLE group_fight_members (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    group_fight_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    hp INTEGER NOT NULL,
+    damage INTEGER NOT NULL DEFAULT 0,
+    exp INTEGER NOT NULL DEFAULT 0,
+    gold INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT fk_group_fight_members_fight FOREIGN KEY (group_fight_id) REFERENCES group_fights(id) ON DELETE CASCADE,
+    CONSTRAINT fk_group_fight_members_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT uq_group_fight_members_user UNIQUE (group_fight_id, user_id)
+);
+
+CREATE INDEX idx_group_fight_members_user_id ON group_fight_members(user_id);
+
+CREATE TABLE group_fight_rounds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    group_fight_id UUID NOT NULL,
+    status round_status NOT NULL DEFAULT 'IN_PROGRESS',
+    deadline_at TIMESTAMP NOT NULL,
+    bot_hp INTEGER NOT NULL,
+    target_id UUID,
+    bot_attack_point body_part,
+    bot_defense_point body_part,
+    bot_damage INTEGER NOT NULL DEFAULT 0,
+    bot_ability bot_ability,
+    CONSTRAINT fk_group_fight_rounds_fight FOREIGN KEY (group_fight_id) REFERENCES group_fights(id) ON DELETE CASCADE,
+    CONSTRAINT fk_group_fight_rounds_target FOREIGN KEY (target_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_group_fight_rounds_fight_id ON group_fight_rounds(group_fight_id);
+CREATE INDEX idx_group_fight_rounds_deadline ON group_fight_rounds(deadline_at) WHERE status = 'IN_PROGRESS';
+
+CREATE TABLE group_fight_moves (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    round_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    attack_point body_part NOT NULL,
+    defense_point body_part NOT NULL,
+    damage INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT fk_group_fight_moves_round FOREIGN KEY (round_id) REFERENCES group_fight_rounds(id) ON DELETE CASCADE,
+    CONSTRAINT fk_group_fight_moves_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT uq_group_fight_moves_user UNIQUE (round_id, user_id)
+);
+
+ALTER TABLE bot_instances
+    ADD COLUMN group_fight_id UUID,
+    ADD CONSTRAINT fk_bot_instances_group_fight FOREIGN KEY (group_fight_id) REFERENCES group_fights(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE bot_instances DROP COLUMN IF EXISTS group_fight_id;
+
+DROP TABLE IF EXISTS group_fight_moves;
+DROP TABLE IF EXISTS group_fight_rounds;
+DROP TABLE IF EXISTS group_fight_members;
+DROP TABLE IF EXISTS group_fights;
+DROP TYPE IF EXISTS group_fight_status;
+-- +goose StatementEnd
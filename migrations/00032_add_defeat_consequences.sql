@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN weakened_until TIMESTAMP;
+
+ALTER TABLE fights
+    ADD COLUMN lost_gold INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN lost_exp INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN respawn_location_id UUID,
+    ADD COLUMN weakened_until TIMESTAMP,
+    ADD CONSTRAINT fk_fights_respawn_location FOREIGN KEY (respawn_location_id) REFERENCES locations(id);
+
+ALTER TABLE group_fight_members
+    ADD COLUMN lost_gold INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN lost_exp INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN respawn_location_id UUID,
+    ADD COLUMN weakened_until TIMESTAMP,
+    ADD CONSTRAINT fk_group_fight_members_respawn_location FOREIGN KEY (respawn_location_id) REFERENCES locations(id);
+
+ALTER TABLE duels
+    ADD COLUMN challenger_respawn_location_id UUID,
+    ADD COLUMN challenger_weakened_until TIMESTAMP,
+    ADD COLUMN opponent_respawn_location_id UUID,
+    ADD COLUMN opponent_weakened_until TIMESTAMP,
+    ADD CONSTRAINT fk_duels_challenger_respawn_location FOREIGN KEY (challenger_respawn_location_id) REFERENCES locations(id),
+    ADD CONSTRAINT fk_duels_opponent_respawn_location FOREIGN KEY (opponent_respawn_location_id) REFERENCES locations(id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE duels
+    DROP CONSTRAINT IF EXISTS fk_duels_opponent_respawn_location,
+    DROP CONSTRAINT IF EXISTS fk_duels_challenger_respawn_location,
+    DROP COLUMN IF EXISTS opponent_weakened_until,
+    DROP COLUMN IF EXISTS opponent_respawn_location_id,
+    DROP COLUMN IF EXISTS challenger_weakened_until,
+    DROP COLUMN IF EXISTS challenger_respawn_location_id;
+
+ALTER TABLE group_fight_members
+    DROP CONSTRAINT IF EXISTS fk_group_fight_members_respawn_location,
+    DROP COLUMN IF EXISTS weakened_until,
+    DROP COLUMN IF EXISTS respawn_location_id,
+    DROP COLUMN IF EXISTS lost_exp,
+    DROP COLUMN IF EXISTS lost_gold;
+
+ALTER TABLE fights
+    DROP CONSTRAINT IF EXISTS fk_fights_respawn_location,
+    DROP COLUMN IF EXISTS weakened_until,
+    DROP COLUMN IF EXISTS respawn_location_id,
+    DROP COLUMN IF EXISTS lost_exp,
+    DROP COLUMN IF EXISTS lost_gold;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS weakened_until;
+-- +goose StatementEnd
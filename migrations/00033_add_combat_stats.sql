@@ -0,0 +1,93 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE equipment_items
+    ADD COLUMN crit_chance INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN crit_power INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN dodge_chance INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN block_chance INTEGER NOT NULL DEFAULT 0,
+    ADD CONSTRAINT chk_equipment_items_crit_chance CHECK (crit_chance >= 0 AND crit_chance <= 100),
+    ADD CONSTRAINT chk_equipment_items_dodge_chance CHECK (dodge_chance >= 0 AND dodge_chance <= 100),
+    ADD CONSTRAINT chk_equipment_items_block_chance CHECK (block_chance >= 0 AND block_chance <= 100);
+
+ALTER TABLE bots
+    ADD COLUMN crit_chance INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN crit_power INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN dodge_chance INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN block_chance INTEGER NOT NULL DEFAULT 0,
+    ADD CONSTRAINT chk_bots_crit_chance CHECK (crit_chance >= 0 AND crit_chance <= 100),
+    ADD CONSTRAINT chk_bots_dodge_chance CHECK (dodge_chance >= 0 AND dodge_chance <= 100),
+    ADD CONSTRAINT chk_bots_block_chance CHECK (block_chance >= 0 AND block_chance <= 100);
+
+ALTER TABLE rounds
+    ADD COLUMN critical BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN dodged BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN shield_blocked BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN bot_critical BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN bot_dodged BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN bot_shield_blocked BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE duel_rounds
+    ADD COLUMN challenger_critical BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN challenger_dodged BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN challenger_shield_blocked BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN opponent_critical BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN opponent_dodged BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN opponent_shield_blocked BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE group_fight_rounds
+    ADD COLUMN bot_critical BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN target_dodged BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN target_shield_blocked BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE group_fight_moves
+    ADD COLUMN critical BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN bot_dodged BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN bot_shield_blocked BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE group_fight_moves
+    DROP COLUMN IF EXISTS bot_shield_blocked,
+    DROP COLUMN IF EXISTS bot_dodged,
+    DROP COLUMN IF EXISTS critical;
+
+ALTER TABLE group_fight_rounds
+    DROP COLUMN IF EXISTS target_shield_blocked,
+    DROP COLUMN IF EXISTS target_dodged,
+    DROP COLUMN IF EXISTS bot_critical;
+
+ALTER TABLE duel_rounds
+    DROP COLUMN IF EXISTS opponent_shield_blocked,
+    DROP COLUMN IF EXISTS opponent_dodged,
+    DROP COLUMN IF EXISTS opponent_critical,
+    DROP COLUMN IF EXISTS challenger_shield_blocked,
+    DROP COLUMN IF EXISTS challenger_dodged,
+    DROP COLUMN IF EXISTS challenger_critical;
+
+ALTER TABLE rounds
+    DROP COLUMN IF EXISTS bot_shield_blocked,
+    DROP COLUMN IF EXISTS bot_dodged,
+    DROP COLUMN IF EXISTS bot_critical,
+    DROP COLUMN IF EXISTS shield_blocked,
+    DROP COLUMN IF EXISTS dodged,
+    DROP COLUMN IF EXISTS critical;
+
+ALTER TABLE bots
+    DROP CONSTRAINT IF EXISTS chk_bots_block_chance,
+    DROP CONSTRAINT IF EXISTS chk_bots_dodge_chance,
+    DROP CONSTRAINT IF EXISTS chk_bots_crit_chance,
+    DROP COLUMN IF EXISTS block_chance,
+    DROP COLUMN IF EXISTS dodge_chance,
+    DROP COLUMN IF EXISTS crit_power,
+    DROP COLUMN IF EXISTS crit_chance;
+
+ALTER TABLE equipment_items
+    DROP CONSTRAINT IF EXISTS chk_equipment_items_block_chance,
+    DROP CONSTRAINT IF EXISTS chk_equipment_items_dodge_chance,
+    DROP CONSTRAINT IF EXISTS chk_equipment_items_crit_chance,
+    DROP COLUMN IF EXISTS block_chance,
+    DROP COLUMN IF EXISTS dodge_chance,
+    DROP COLUMN IF EXISTS crit_power,
+    DROP COLUMN IF EXISTS crit_chance;
+-- +goose StatementEnd
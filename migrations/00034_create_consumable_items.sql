@@ -0,0 +1,92 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE consumable_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    image VARCHAR(255) NOT NULL DEFAULT '',
+    price INTEGER NOT NULL DEFAULT 0,
+    required_level INTEGER NOT NULL DEFAULT 1,
+    heal_hp INTEGER NOT NULL DEFAULT 0,
+    buff_attack INTEGER NOT NULL DEFAULT 0,
+    buff_defense INTEGER NOT NULL DEFAULT 0,
+    buff_crit_chance INTEGER NOT NULL DEFAULT 0,
+    buff_dodge_chance INTEGER NOT NULL DEFAULT 0,
+    buff_seconds INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT chk_consumable_items_buff_seconds CHECK (buff_seconds >= 0)
+);
+
+CREATE UNIQUE INDEX idx_consumable_items_slug ON consumable_items(slug) WHERE deleted_at IS NULL;
+
+ALTER TABLE inventory
+    ALTER COLUMN equipment_item_id DROP NOT NULL,
+    ADD COLUMN consumable_item_id UUID,
+    ADD CONSTRAINT fk_inventory_consumable_item FOREIGN KEY (consumable_item_id) REFERENCES consumable_items(id) ON DELETE CASCADE,
+    ADD CONSTRAINT chk_inventory_one_item CHECK (num_nonnulls(equipment_item_id, consumable_item_id) = 1);
+
+CREATE TABLE bot_consumable_drops (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    bot_id UUID NOT NULL,
+    consumable_item_id UUID NOT NULL,
+    chance INTEGER NOT NULL,
+    CONSTRAINT fk_bot_consumable_drops_bot FOREIGN KEY (bot_id) REFERENCES bots(id) ON DELETE CASCADE,
+    CONSTRAINT fk_bot_consumable_drops_item FOREIGN KEY (consumable_item_id) REFERENCES consumable_items(id) ON DELETE CASCADE,
+    CONSTRAINT chk_bot_consumable_drops_chance CHECK (chance > 0 AND chance <= 100)
+);
+
+CREATE INDEX idx_bot_consumable_drops_bot_id ON bot_consumable_drops(bot_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE user_buffs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    user_id UUID NOT NULL,
+    consumable_item_id UUID NOT NULL,
+    attack INTEGER NOT NULL DEFAULT 0,
+    defense INTEGER NOT NULL DEFAULT 0,
+    crit_chance INTEGER NOT NULL DEFAULT 0,
+    dodge_chance INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_user_buffs_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_buffs_item FOREIGN KEY (consumable_item_id) REFERENCES consumable_items(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_user_buffs_user_id_expires_at ON user_buffs(user_id, expires_at) WHERE deleted_at IS NULL;
+
+ALTER TABLE fights
+    ADD COLUMN dropped_consumable_item_id UUID,
+    ADD CONSTRAINT fk_fights_dropped_consumable_item FOREIGN KEY (dropped_consumable_item_id) REFERENCES consumable_items(id) ON DELETE SET NULL;
+
+ALTER TABLE rounds
+    ADD COLUMN consumable_item_id UUID,
+    ADD CONSTRAINT fk_rounds_consumable_item FOREIGN KEY (consumable_item_id) REFERENCES consumable_items(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE rounds
+    DROP CONSTRAINT IF EXISTS fk_rounds_consumable_item,
+    DROP COLUMN IF EXISTS consumable_item_id;
+
+ALTER TABLE fights
+    DROP CONSTRAINT IF EXISTS fk_fights_dropped_consumable_item,
+    DROP COLUMN IF EXISTS dropped_consumable_item_id;
+
+DROP TABLE IF EXISTS user_buffs;
+
+DROP TABLE IF EXISTS bot_consumable_drops;
+
+DELETE FROM inventory WHERE consumable_item_id IS NOT NULL;
+
+ALTER TABLE inventory
+    DROP CONSTRAINT IF EXISTS chk_inventory_one_item,
+    DROP CONSTRAINT IF EXISTS fk_inventory_consumable_item,
+    DROP COLUMN IF EXISTS consumable_item_id,
+    ALTER COLUMN equipment_item_id SET NOT NULL;
+
+DROP TABLE IF EXISTS consumable_items;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE locations
+    ADD COLUMN regen_multiplier DOUBLE PRECISION NOT NULL DEFAULT 1,
+    ADD CONSTRAINT chk_locations_regen_multiplier CHECK (regen_multiplier >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE locations
+    DROP CONSTRAINT IF EXISTS chk_locations_regen_multiplier,
+    DROP COLUMN IF EXISTS regen_multiplier;
+-- +goose StatementEnd
@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN hp_changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN hp_regen_rate DOUBLE PRECISION NOT NULL DEFAULT 1,
+    ADD CONSTRAINT chk_users_hp_regen_rate CHECK (hp_regen_rate >= 0);
+
+-- Players already in a fight keep their HP frozen until it ends.
+UPDATE users u
+SET hp_regen_rate = CASE
+    WHEN EXISTS (
+        SELECT 1 FROM fights f
+        WHERE f.user_id = u.id AND f.status = 'IN_PROGRESS' AND f.deleted_at IS NULL
+    ) OR EXISTS (
+        SELECT 1 FROM duels d
+        WHERE (d.challenger_id = u.id OR d.opponent_id = u.id) AND d.status = 'IN_PROGRESS' AND d.deleted_at IS NULL
+    ) OR EXISTS (
+        SELECT 1 FROM group_fight_members gfm
+        INNER JOIN group_fights gf ON gf.id = gfm.group_fight_id
+        WHERE gfm.user_id = u.id AND gfm.hp > 0 AND gf.status = 'IN_PROGRESS' AND gf.deleted_at IS NULL
+    ) THEN 0
+    ELSE (SELECT l.regen_multiplier FROM locations l WHERE l.id = u.location_id)
+END;
+
+-- regenerated_hp is the HP a user has right now: every full 3 second tick
+-- since hp_changed_at gives back hp_regen_rate percent of their max HP, at
+-- least 1, never going above the max. The heal is clamped to the missing HP
+-- in NUMERIC before the cast back, so a long idle user cannot overflow
+-- INTEGER.
+CREATE FUNCTION regenerated_hp(current_hp INTEGER, hp INTEGER, hp_changed_at TIMESTAMP, hp_regen_rate DOUBLE PRECISION)
+RETURNS INTEGER AS $$
+    SELECT CASE
+        WHEN hp_regen_rate <= 0 OR current_hp >= hp THEN current_hp
+        ELSE (current_hp + LEAST(
+            hp - current_hp,
+            GREATEST(0, FLOOR(EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP::TIMESTAMP - hp_changed_at)) / 3))::NUMERIC
+                * GREATEST(1, ROUND(hp * hp_regen_rate::NUMERIC / 100.0))
+        ))::INTEGER
+    END
+$$ LANGUAGE SQL STABLE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE users SET current_hp = regenerated_hp(current_hp, hp, hp_changed_at, hp_regen_rate);
+
+DROP FUNCTION IF EXISTS regenerated_hp(INTEGER, INTEGER, TIMESTAMP, DOUBLE PRECISION);
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS chk_users_hp_regen_rate,
+    DROP COLUMN IF EXISTS hp_regen_rate,
+    DROP COLUMN IF EXISTS hp_changed_at;
+-- +goose StatementEnd